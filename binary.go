@@ -0,0 +1,127 @@
+package guru
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// The binary encodings (CBOR and MessagePack) encode the same structure as
+// MarshalJSON does, so they preserve the same information. The JSON is decoded
+// in to generic values, which are then encoded.
+
+// toValue converts err to generic values: nil, bool, int64, float64, string,
+// []interface{}, and map[string]interface{}.
+func toValue(err error) (interface{}, error) {
+	j, jErr := MarshalJSON(err)
+	if jErr != nil {
+		return nil, jErr
+	}
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+	var v interface{}
+	if jErr := d.Decode(&v); jErr != nil {
+		return nil, jErr
+	}
+	return numbers(v), nil
+}
+
+// numbers converts json.Numbers to int64 or float64.
+func numbers(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case json.Number:
+		if n, err := vv.Int64(); err == nil {
+			return n
+		}
+		f, _ := vv.Float64()
+		return f
+	case []interface{}:
+		for i := range vv {
+			vv[i] = numbers(vv[i])
+		}
+	case map[string]interface{}:
+		for k := range vv {
+			vv[k] = numbers(vv[k])
+		}
+	}
+	return v
+}
+
+// fromValue converts generic values back to an error.
+func fromValue(v interface{}) (error, error) {
+	if _, ok := v.(map[string]interface{}); !ok && v != nil {
+		return nil, fmt.Errorf("not a map but %T", v)
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(j)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var errShort = errors.New("unexpected end of data")
+
+// reader reads the binary encodings.
+type reader struct {
+	data  []byte
+	depth int
+}
+
+func (r *reader) byte() (byte, error) {
+	if len(r.data) < 1 {
+		return 0, errShort
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b, nil
+}
+
+func (r *reader) bytes(n uint64) ([]byte, error) {
+	if uint64(len(r.data)) < n {
+		return nil, errShort
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+func (r *reader) uint(size int) (uint64, error) {
+	b, err := r.bytes(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+// check checks that n items (of at least one byte) can be in the remaining
+// data, so a bogus length doesn't allocate a lot of memory.
+func (r *reader) check(n uint64) error {
+	if n > uint64(len(r.data)) {
+		return errShort
+	}
+	return nil
+}
+
+// enter is called when entering an array or map.
+func (r *reader) enter() error {
+	r.depth++
+	if r.depth > 1000 {
+		return errors.New("nested too deeply")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CodeBlock is a range of error codes owned by a package or subsystem; the
+// errors it creates use codes relative to the start of the block.
+type CodeBlock struct {
+	min, max int
+	name     string
+}
+
+// Block creates a new block for the codes from min to max (inclusive), and
+// registers name as the category for these codes in DefaultRegistry.
+//
+// For example:
+//
+//	var blk = guru.Block(1000, 1999, "storage")
+//
+//	blk.New(3, "disk full") // Error with code 1003.
+//
+// This will panic if min is larger than max.
+func Block(min, max int, name string) CodeBlock {
+	if min > max {
+		panic(fmt.Sprintf("guru.Block: min %d is larger than max %d", min, max))
+	}
+	RegisterCategory(min, max, name)
+	return CodeBlock{min: min, max: max, name: name}
+}
+
+// Name gets the block name.
+func (b CodeBlock) Name() string { return b.name }
+
+// Range gets the first and last code of the block.
+func (b CodeBlock) Range() (min, max int) { return b.min, b.max }
+
+// Code gets the code for offset in the block.
+//
+// This will panic if offset is negative or if the code is outside of the
+// block.
+func (b CodeBlock) Code(offset int) int {
+	if offset < 0 || offset > b.max-b.min {
+		panic(fmt.Sprintf("guru.CodeBlock %q: offset %d is outside the block %d-%d (max offset %d)",
+			b.name, offset, b.min, b.max, b.max-b.min))
+	}
+	return b.min + offset
+}
+
+// Contains reports if the code of err, as returned by Code(), is in the block.
+func (b CodeBlock) Contains(err error) bool {
+	if !hasCode(err) {
+		return false
+	}
+	c := Code(err)
+	return c >= b.min && c <= b.max
+}
+
+// New calls New() with the code for offset.
+func (b CodeBlock) New(offset int, msg string) error {
+	code := b.Code(offset)
+	return created(KindNew, code, msg, nil, &withCode{
+		error: errors.New(msg),
+		code:  code,
+	})
+}
+
+// NewSub calls NewSub() with the code for offset.
+func (b CodeBlock) NewSub(offset, subcode int, msg string) error {
+	code := b.Code(offset)
+	return created(KindNew, code, msg, nil, &withCode{
+		error: errors.New(msg),
+		code:  code,
+		sub:   subcode,
+	})
+}
+
+// Errorf calls Errorf() with the code for offset.
+func (b CodeBlock) Errorf(offset int, format string, args ...interface{}) error {
+	return errorf(nil, b.Code(offset), format, args)
+}
+
+// WithCode calls WithCode() with the code for offset.
+func (b CodeBlock) WithCode(offset int, err error) error {
+	code := b.Code(offset)
+	if err == nil {
+		return nil
+	}
+	return created(KindWrap, code, "", err, &withCode{
+		error: err,
+		code:  code,
+	})
+}
+
+// Wrap calls Wrap() with the code for offset.
+func (b CodeBlock) Wrap(offset int, err error, msg string) error {
+	code := b.Code(offset)
+	if err == nil {
+		return nil
+	}
+	return created(KindWrap, code, msg, err, &wrapped{
+		msg:   msg,
+		code:  code,
+		error: err,
+	})
+}
+
+// Wrapf calls Wrapf() with the code for offset.
+func (b CodeBlock) Wrapf(offset int, err error, msg string, args ...interface{}) error {
+	code := b.Code(offset)
+	if err == nil {
+		return nil
+	}
+	m := fmt.Sprintf(msg, args...)
+	return created(KindWrap, code, m, err, &wrapped{
+		msg:   m,
+		code:  code,
+		error: err,
+	})
+}
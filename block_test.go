@@ -0,0 +1,61 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestBlock(t *testing.T) {
+	resetRegistry(t)
+	blk := Block(1000, 1999, "storage")
+
+	tests := []struct {
+		in       error
+		wantCode int
+		wantMsg  string
+	}{
+		{blk.New(3, "disk full"), 1003, "disk full"},
+		{blk.NewSub(0, 2, "x"), 1000, "x"},
+		{blk.Errorf(999, "x %d", 1), 1999, "x 1"},
+		{blk.Wrap(1, errors.New("x"), "y"), 1001, "y"},
+		{blk.Wrapf(1, errors.New("x"), "y %d", 2), 1001, "y 2"},
+		{blk.WithCode(5, errors.New("x")), 1005, "x"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			code, msg := Code(tt.in), tt.in.Error()
+			if code != tt.wantCode || msg != tt.wantMsg {
+				t.Errorf("\nout:  %d %q\nwant: %d %q\n", code, msg, tt.wantCode, tt.wantMsg)
+			}
+			if !blk.Contains(tt.in) {
+				t.Error("Contains is false")
+			}
+			if c := Category(tt.in); c != "storage" {
+				t.Errorf("Category: %q", c)
+			}
+		})
+	}
+
+	if blk.Contains(New(2000, "x")) || blk.Contains(errors.New("x")) {
+		t.Error("Contains is true")
+	}
+	if min, max := blk.Range(); min != 1000 || max != 1999 || blk.Name() != "storage" {
+		t.Errorf("Range: %d %d %q", min, max, blk.Name())
+	}
+
+	for _, f := range []func(){
+		func() { blk.Code(1000) },
+		func() { blk.New(-1, "x") },
+		func() { Block(2, 1, "x") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,211 @@
+package guru
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// MarshalCBOR encodes err as CBOR (RFC 8949), with the same structure as
+// MarshalJSON. Use FromCBOR to decode it.
+func MarshalCBOR(err error) ([]byte, error) {
+	v, vErr := toValue(err)
+	if vErr != nil {
+		return nil, vErr
+	}
+	return cborValue(nil, v), nil
+}
+
+// FromCBOR decodes an error encoded with MarshalCBOR.
+//
+// The errors in the chain won't be of the same type as the original errors;
+// see FromJSON. It will return nil if data is the CBOR null value.
+func FromCBOR(data []byte) (error, error) {
+	r := &reader{data: data}
+	v, err := r.cbor()
+	if err == nil && len(r.data) > 0 {
+		err = fmt.Errorf("%d bytes of trailing data", len(r.data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("guru.FromCBOR: %w", err)
+	}
+	return fromValue(v)
+}
+
+func cborHead(b []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, major|27), n)
+}
+
+func cborValue(b []byte, v interface{}) []byte {
+	switch vv := v.(type) {
+	case nil:
+		return append(b, 0xf6)
+	case bool:
+		if vv {
+			return append(b, 0xf5)
+		}
+		return append(b, 0xf4)
+	case int64:
+		if vv < 0 {
+			return cborHead(b, 1, uint64(-(vv + 1)))
+		}
+		return cborHead(b, 0, uint64(vv))
+	case float64:
+		return binary.BigEndian.AppendUint64(append(b, 0xfb), math.Float64bits(vv))
+	case string:
+		return append(cborHead(b, 3, uint64(len(vv))), vv...)
+	case []interface{}:
+		b = cborHead(b, 4, uint64(len(vv)))
+		for _, e := range vv {
+			b = cborValue(b, e)
+		}
+		return b
+	case map[string]interface{}:
+		b = cborHead(b, 5, uint64(len(vv)))
+		for _, k := range sortedKeys(vv) {
+			b = cborValue(append(cborHead(b, 3, uint64(len(k))), k...), vv[k])
+		}
+		return b
+	}
+	panic(fmt.Sprintf("guru: unexpected type %T", v)) // Never happens: toValue returns only the above.
+}
+
+func (r *reader) cbor() (interface{}, error) {
+	ib, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	major, info := ib>>5, ib&0x1f
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		case 25:
+			n, err := r.uint(2)
+			return float16(uint16(n)), err
+		case 26:
+			n, err := r.uint(4)
+			return float64(math.Float32frombits(uint32(n))), err
+		case 27:
+			n, err := r.uint(8)
+			return math.Float64frombits(n), err
+		}
+		return nil, fmt.Errorf("unsupported simple value %d", info)
+	}
+
+	var n uint64
+	switch {
+	case info < 24:
+		n = uint64(info)
+	case info <= 27:
+		if n, err = r.uint(1 << (info - 24)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported length %d (indefinite lengths aren't supported)", info)
+	}
+
+	switch major {
+	case 0:
+		if n > math.MaxInt64 {
+			return float64(n), nil
+		}
+		return int64(n), nil
+	case 1:
+		if n > math.MaxInt64 {
+			return -1 - float64(n), nil
+		}
+		return -1 - int64(n), nil
+	case 2, 3:
+		s, err := r.bytes(n)
+		return string(s), err
+	case 4:
+		if err := r.enter(); err != nil {
+			return nil, err
+		}
+		defer func() { r.depth-- }()
+		if err := r.check(n); err != nil {
+			return nil, err
+		}
+		a := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			v, err := r.cbor()
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		return a, nil
+	case 5:
+		if err := r.enter(); err != nil {
+			return nil, err
+		}
+		defer func() { r.depth-- }()
+		if err := r.check(n); err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := r.cbor()
+			if err != nil {
+				return nil, err
+			}
+			ks, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key is %T, not a string", k)
+			}
+			if m[ks], err = r.cbor(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case 6: // Tag; ignored.
+		if err := r.enter(); err != nil {
+			return nil, err
+		}
+		defer func() { r.depth-- }()
+		return r.cbor()
+	}
+	return nil, fmt.Errorf("unsupported major type %d", major)
+}
+
+// float16 converts a IEEE 754 half-precision float.
+func float16(h uint16) float64 {
+	var (
+		exp  = int(h>>10) & 0x1f
+		mant = float64(h & 0x3ff)
+		f    float64
+	)
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
@@ -0,0 +1,107 @@
+package guru
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var binaryTests = []error{
+	nil,
+	errors.New("x"),
+	New(1, "x"),
+	New(-1000000, "x"),
+	NewSub(1, 2, "x"),
+	Wrap(2, New(1, "x"), "y"),
+	WithFields(New(1, "x"), map[string]interface{}{"int": 1, "neg": -42, "float": 1.5, "str": "v", "bool": true,
+		"nil": nil, "list": []interface{}{"a", 1}, "map": map[string]interface{}{"k": "v"}, "big": int64(1) << 40}),
+	errors.Join(New(1, "x"), errors.New(strings.Repeat("y", 70000))),
+	WithOp(WithRequestID(MarkRetryable(New(1, "x")), "abc"), "op"),
+	WithTimeout(Wrap(2, New(1, "x"), "y")),
+}
+
+func TestCBOR(t *testing.T) {
+	for i, tt := range binaryTests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			b, err := MarshalCBOR(tt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := FromCBOR(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testBinaryRoundTrip(t, tt, out)
+		})
+	}
+}
+
+func testBinaryRoundTrip(t *testing.T, in, out error) {
+	t.Helper()
+	want, err := MarshalJSON(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := MarshalJSON(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(have) != string(want) {
+		t.Errorf("\nout:  %.200s\nwant: %.200s\n", have, want)
+	}
+	if a, b := fmt.Sprintf("%v", out), fmt.Sprintf("%v", in); in != nil && a != b {
+		t.Errorf("\nout:  %.200q\nwant: %.200q\n", a, b)
+	}
+}
+
+func TestCBOREncoding(t *testing.T) {
+	b, err := MarshalCBOR(New(1, "x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"code": 1, "message": "x"}
+	if h, want := hex.EncodeToString(b), "a264636f646501676d6573736167656178"; h != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", h, want)
+	}
+}
+
+func TestFromCBORErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "unexpected end of data"},
+		{"a264636f646501", "unexpected end of data"},
+		{"a164636f64650100", "trailing data"},
+		{"a1", "unexpected end of data"},
+		{"a10101", "map key is int64"},
+		{"01", "not a map"},
+		{"9bffffffffffffffff", "unexpected end of data"},
+		{"bf", "indefinite lengths"},
+		{strings.Repeat("81", 1001) + "01", "nested too deeply"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			b, _ := hex.DecodeString(tt.in)
+			_, err := FromCBOR(b)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("\nout:  %v\nwant: %v\n", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFloat16(t *testing.T) {
+	tests := []struct {
+		in   uint16
+		want float64
+	}{
+		{0x0000, 0}, {0x3c00, 1}, {0xc000, -2}, {0x3e00, 1.5}, {0x7bff, 65504}, {0x0001, 5.960464477539063e-8},
+	}
+	for _, tt := range tests {
+		if out := float16(tt.in); out != tt.want {
+			t.Errorf("%04x\nout:  %v\nwant: %v\n", tt.in, out, tt.want)
+		}
+	}
+}
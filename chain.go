@@ -0,0 +1,23 @@
+package guru
+
+import (
+	"iter"
+)
+
+// Walk calls fn for err and every error it wraps, until fn returns false. It
+// reports whether the entire tree was walked.
+//
+// Errors with an Unwrap() []error method (such as those created with
+// errors.Join) are walked depth-first, in the order they're returned; this is
+// the same order as errors.Is() and errors.As() use.
+func Walk(err error, fn func(error) bool) bool { return walk(err, fn) }
+
+// Chain returns an iterator over err and every error it wraps, in the same
+// order as Walk:
+//
+//	for e := range guru.Chain(err) {
+//		...
+//	}
+func Chain(err error) iter.Seq[error] {
+	return func(yield func(error) bool) { walk(err, yield) }
+}
@@ -0,0 +1,54 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		in   error
+		want []string
+	}{
+		{nil, nil},
+		{errors.New("x"), []string{"x"}},
+		{Wrap(2, New(1, "x"), "y"), []string{"error 2: error 1: x: y", "error 1: x", "x"}},
+		{errors.Join(New(1, "a"), fmt.Errorf("b: %w", errors.New("c"))),
+			[]string{"a\nb: c", "error 1: a", "a", "b: c", "c"}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			var out []string
+			for e := range Chain(tt.in) {
+				out = append(out, fmt.Sprintf("%v", e))
+			}
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+
+			var walked []string
+			if !Walk(tt.in, func(e error) bool { walked = append(walked, fmt.Sprintf("%v", e)); return true }) {
+				t.Error("Walk returned false")
+			}
+			if !reflect.DeepEqual(walked, tt.want) {
+				t.Errorf("Walk\nout:  %#v\nwant: %#v\n", walked, tt.want)
+			}
+		})
+	}
+
+	n := 0
+	for range Chain(Wrap(3, Wrap(2, New(1, "x"), "y"), "z")) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("break: %d", n)
+	}
+	if Walk(Wrap(2, New(1, "x"), "y"), func(error) bool { return false }) {
+		t.Error("Walk returned true")
+	}
+}
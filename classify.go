@@ -0,0 +1,96 @@
+package guru
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+)
+
+type class struct {
+	target error
+	code   int
+}
+
+// The default classes used by Classify; these are checked after the ones added
+// with RegisterClass.
+var defaultClasses = []class{
+	{fs.ErrNotExist, 404},
+	{fs.ErrPermission, 403},
+	{fs.ErrExist, 409},
+	{context.Canceled, 499},
+	{context.DeadlineExceeded, 504},
+	{io.ErrUnexpectedEOF, 400},
+	{io.EOF, 400},
+}
+
+var (
+	classMu sync.Mutex
+	classes atomic.Pointer[[]class]
+)
+
+// RegisterClass makes Classify assign code to errors that match target with
+// errors.Is. Classes added with RegisterClass are tried in the order they were
+// added, before the default classes, so this can also be used to change the
+// code for one of the defaults.
+func RegisterClass(target error, code int) {
+	classMu.Lock()
+	defer classMu.Unlock()
+	var cur []class
+	if c := classes.Load(); c != nil {
+		cur = *c
+	}
+	n := append(append(make([]class, 0, len(cur)+1), cur...), class{target, code})
+	classes.Store(&n)
+}
+
+// Classify assigns a code to well-known errors from the standard library, so
+// lower layers don't need to write a switch for them:
+//
+//	fs.ErrNotExist            404
+//	fs.ErrPermission          403
+//	fs.ErrExist               409
+//	context.Canceled          499
+//	context.DeadlineExceeded  504
+//	io.ErrUnexpectedEOF       400
+//	io.EOF                    400
+//	timeouts (IsTimeout)      504
+//
+// Use RegisterClass to add more classes or to change the defaults.
+//
+// err is returned as-is if it's nil, if it already has a code, or if it doesn't
+// match any of the classes. Otherwise it's wrapped as with WithCode.
+func Classify(err error) error {
+	if err == nil || hasCode(err) {
+		return err
+	}
+	code, ok := classify(err)
+	if !ok {
+		return err
+	}
+	return created(KindWrap, code, "", err, &withCode{
+		error: err,
+		code:  code,
+	})
+}
+
+func classify(err error) (int, bool) {
+	if c := classes.Load(); c != nil {
+		for _, cl := range *c {
+			if errors.Is(err, cl.target) {
+				return cl.code, true
+			}
+		}
+	}
+	for _, cl := range defaultClasses {
+		if errors.Is(err, cl.target) {
+			return cl.code, true
+		}
+	}
+	if IsTimeout(err) {
+		return 504, true
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+package guru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	old := classes.Load()
+	t.Cleanup(func() { classes.Store(old) })
+
+	errCustom := errors.New("custom")
+	RegisterClass(errCustom, 42)
+	RegisterClass(io.EOF, 422)
+
+	_, openErr := os.Open("/nonexistent/file")
+	timeout := &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}
+	plain := errors.New("plain")
+
+	tests := []struct {
+		in       error
+		wantCode int
+		same     bool
+	}{
+		{nil, 0, true},
+		{plain, 0, true},
+		{New(12, "coded"), 12, true},
+		{openErr, 404, false},
+		{fmt.Errorf("read: %w", os.ErrPermission), 403, false},
+		{os.ErrExist, 409, false},
+		{context.Canceled, 499, false},
+		{context.DeadlineExceeded, 504, false},
+		{io.ErrUnexpectedEOF, 400, false},
+		{io.EOF, 422, false},
+		{timeout, 504, false},
+		{errCustom, 42, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Classify(tt.in)
+			if c := Code(out); c != tt.wantCode {
+				t.Errorf("\nout:  %d\nwant: %d\n", c, tt.wantCode)
+			}
+			if (out == tt.in) != tt.same {
+				t.Errorf("same: %t", !tt.same)
+			}
+			if tt.in != nil && !errors.Is(out, tt.in) {
+				t.Error("errors.Is false")
+			}
+			if out != nil && out.Error() != tt.in.Error() {
+				t.Errorf("\nout:  %q\nwant: %q\n", out.Error(), tt.in.Error())
+			}
+		})
+	}
+}
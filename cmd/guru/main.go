@@ -0,0 +1,259 @@
+// Command guru looks up error codes in a registry exported with
+// guru.Registry.Export. Files ending in .yaml or .yml are read as YAML, and
+// anything else as JSON.
+//
+// Usage:
+//
+//	guru [-r registry.json] lookup code...
+//	guru [-r registry.json] list
+//	guru [-r registry.json] openapi
+//	guru diff old.json new.json
+//
+// The openapi command writes the OpenAPI 3 error response components for the
+// registry; see guru.Registry.WriteOpenAPI.
+//
+// The diff command compares two registries and exits with 1 if there are
+// breaking changes, such as removed codes or changed HTTP statuses; see
+// guru.Diff. Aside from registry exports it also accepts catalogs written by
+// guru-scan, using the messages as the descriptions.
+//
+// The registry is read from the file in $GURU_REGISTRY if -r isn't given.
+// Codes can be given as "4012", "E4012", "error 4012", or as a Guru Meditation
+// string ("Guru Meditation #00000FAC.00000000").
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"zgo.at/guru"
+)
+
+const usage = `usage: guru [-r registry.json] lookup code...
+       guru [-r registry.json] list
+       guru [-r registry.json] openapi
+       guru diff old.json new.json
+`
+
+func main() {
+	err := run(os.Stdout, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "guru:", err)
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprint(os.Stderr, usage)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, args []string) error {
+	f := flag.NewFlagSet("guru", flag.ContinueOnError)
+	f.SetOutput(io.Discard)
+	path := f.String("r", os.Getenv("GURU_REGISTRY"), "")
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("%w", flag.ErrHelp)
+	}
+	if f.NArg() == 0 {
+		return fmt.Errorf("need a command: %w", flag.ErrHelp)
+	}
+	if f.Arg(0) == "diff" {
+		if f.NArg() != 3 {
+			return fmt.Errorf("diff: need two files: %w", flag.ErrHelp)
+		}
+		return diff(w, f.Arg(1), f.Arg(2))
+	}
+	if *path == "" {
+		return errors.New("no registry: use -r or set $GURU_REGISTRY")
+	}
+
+	reg, err := load(*path)
+	if err != nil {
+		return err
+	}
+
+	switch cmd := f.Arg(0); cmd {
+	case "lookup":
+		if f.NArg() < 2 {
+			return fmt.Errorf("lookup: need a code: %w", flag.ErrHelp)
+		}
+		for i, a := range f.Args()[1:] {
+			code, err := parseCode(a)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			info, ok := reg.Lookup(code)
+			if !ok {
+				fmt.Fprintf(w, "%d: not registered\n", code)
+				if info.Category != "" {
+					fmt.Fprintf(w, "    Category:    %s\n", info.Category)
+				}
+				continue
+			}
+			explain(w, info)
+		}
+	case "list":
+		for _, info := range reg.All() {
+			l := fmt.Sprintf("%-8d %-24s %s", info.Code, info.Name, info.Category)
+			fmt.Fprintln(w, strings.TrimRight(l, " "))
+		}
+	case "openapi":
+		return reg.WriteOpenAPI(w)
+	default:
+		return fmt.Errorf("unknown command: %q: %w", cmd, flag.ErrHelp)
+	}
+	return nil
+}
+
+func load(path string) (*guru.Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := &guru.Registry{}
+	if d := bytes.TrimSpace(data); len(d) > 0 && d[0] == '[' {
+		if err := loadCatalog(reg, d); err != nil {
+			return nil, fmt.Errorf("loading %s: %w", path, err)
+		}
+		return reg, nil
+	}
+
+	format := guru.FormatJSON
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		format = guru.FormatYAML
+	}
+	if err := reg.Import(bytes.NewReader(data), format); err != nil {
+		return nil, fmt.Errorf("loading %s: %w", path, err)
+	}
+	return reg, nil
+}
+
+// loadCatalog registers the codes from a catalog written by guru-scan, with
+// all the messages for a code as the description.
+func loadCatalog(reg *guru.Registry, data []byte) error {
+	var sites []struct {
+		Code    *int   `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &sites); err != nil {
+		return err
+	}
+
+	msgs := make(map[int]map[string]struct{})
+	for _, s := range sites {
+		if s.Code == nil {
+			continue
+		}
+		if msgs[*s.Code] == nil {
+			msgs[*s.Code] = make(map[string]struct{})
+		}
+		if s.Message != "" {
+			msgs[*s.Code][s.Message] = struct{}{}
+		}
+	}
+	for code, set := range msgs {
+		m := make([]string, 0, len(set))
+		for k := range set {
+			m = append(m, k)
+		}
+		sort.Strings(m)
+		reg.Register(code, "", strings.Join(m, "; "))
+	}
+	return nil
+}
+
+func diff(w io.Writer, oldPath, newPath string) error {
+	old, err := load(oldPath)
+	if err != nil {
+		return err
+	}
+	new, err := load(newPath)
+	if err != nil {
+		return err
+	}
+
+	breaking := 0
+	for _, c := range guru.Diff(old, new) {
+		fmt.Fprintln(w, c)
+		if c.Breaking {
+			breaking++
+		}
+	}
+	if breaking > 0 {
+		return fmt.Errorf("%d breaking changes", breaking)
+	}
+	return nil
+}
+
+func explain(w io.Writer, info guru.Info) {
+	fmt.Fprintf(w, "%d %s\n", info.Code, info.Name)
+	if info.Description != "" {
+		fmt.Fprintf(w, "    %s\n", info.Description)
+	}
+
+	var details []string
+	if info.Category != "" {
+		details = append(details, "Category:    "+info.Category)
+	}
+	if info.HTTPStatus != 0 {
+		details = append(details, fmt.Sprintf("HTTP status: %d %s", info.HTTPStatus, http.StatusText(info.HTTPStatus)))
+	}
+	if info.ExitCode != 0 {
+		details = append(details, fmt.Sprintf("Exit code:   %d", info.ExitCode))
+	}
+	if info.Retryable {
+		details = append(details, "Retryable:   yes")
+	}
+	if info.HelpURL != "" {
+		details = append(details, "Help:        "+info.HelpURL)
+	}
+	if info.Deprecated {
+		d := "Deprecated:  yes"
+		if info.DeprecationReason != "" {
+			d += ", " + info.DeprecationReason
+		}
+		if info.ReplacedBy != 0 {
+			d += fmt.Sprintf("; use %d instead", info.ReplacedBy)
+		}
+		details = append(details, d)
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(w, "\n    %s\n", strings.Join(details, "\n    "))
+	}
+}
+
+// parseCode parses a code from the various ways it may be displayed.
+func parseCode(s string) (int, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	if m := strings.TrimPrefix(s, "Guru Meditation #"); m != s {
+		hex, _, _ := strings.Cut(m, ".")
+		c, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid code: %q", orig)
+		}
+		return int(int32(c)), nil
+	}
+	s = strings.TrimPrefix(s, "error ")
+	s = strings.TrimPrefix(s, "E")
+	s, _, _ = strings.Cut(s, ".") // Subcode
+	s = strings.TrimSuffix(s, ":")
+	c, err := guru.ParseCode(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid code: %q", orig)
+	}
+	return c, nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"4012", 4012, false},
+		{" E4012 ", 4012, false},
+		{"E4012.3", 4012, false},
+		{"error 4012:", 4012, false},
+		{"error 4012.3", 4012, false},
+		{"error 0xFAC.0x3", 4012, false},
+		{"E0o7654", 4012, false},
+		{"004012", 4012, false},
+		{"Guru Meditation #00000FAC.00000001", 4012, false},
+		{"Guru Meditation #FFFFFFFF.00000000", -1, false},
+		{"Guru Meditation #zz", 0, true},
+		{"x", 0, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, err := parseCode(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong err: %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	reg := filepath.Join(t.TempDir(), "registry.json")
+	err := os.WriteFile(reg, []byte(`{
+		"categories": [{"min": 4000, "max": 4999, "name": "billing"}],
+		"codes": [
+			{"code": 5, "name": "ErrFive"},
+			{"code": 4012, "name": "ErrInvoiceMissing", "description": "The invoice doesn't exist.",
+			 "http_status": 404, "help_url": "https://example.com/errors/4012"}
+		]
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	yaml := filepath.Join(t.TempDir(), "registry.yaml")
+	err = os.WriteFile(yaml, []byte("codes:\n  - code: 5\n    name: ErrFive\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg2 := filepath.Join(t.TempDir(), "registry.json")
+	err = os.WriteFile(reg2, []byte(`{
+		"codes": [{"code": 4012, "name": "ErrInvoiceMissing", "http_status": 410}, {"code": 4013, "name": "ErrX"}]
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cat := filepath.Join(t.TempDir(), "catalog.json")
+	err = os.WriteFile(cat, []byte(`[
+		{"code": 5, "func": "New", "message": "five", "pos": "a.go:1"},
+		{"code": 5, "func": "New", "message": "five", "pos": "a.go:2"},
+		{"code": 5, "func": "Wrap", "message": "cinq", "pos": "a.go:3"},
+		{"code": null, "func": "New", "expr": "c", "pos": "a.go:4"}
+	]`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{[]string{"-r", reg, "lookup", "E4012"}, `
+			4012 ErrInvoiceMissing
+			    The invoice doesn't exist.
+
+			    Category:    billing
+			    HTTP status: 404 Not Found
+			    Help:        https://example.com/errors/4012
+		`, nil},
+		{[]string{"-r", reg, "lookup", "5", "4013"}, `
+			5 ErrFive
+
+			4013: not registered
+			    Category:    billing
+		`, nil},
+		{[]string{"-r", reg, "list"}, `
+			5        ErrFive
+			4012     ErrInvoiceMissing        billing
+		`, nil},
+		{[]string{"-r", yaml, "list"}, `
+			5        ErrFive
+		`, nil},
+		{[]string{"-r", yaml, "openapi"}, `
+			{
+			  "components": {
+			    "schemas": {
+			      "Error": {
+			        "properties": {
+			          "code": {
+			            "description": "Error code.",
+			            "enum": [
+			              5
+			            ],
+			            "type": "integer"
+			          },
+			          "error_id": {
+			            "description": "Unique ID of the error, if there is one.",
+			            "type": "string"
+			          },
+			          "message": {
+			            "description": "Message that's safe to show to users.",
+			            "type": "string"
+			          },
+			          "request_id": {
+			            "description": "Request ID, if there is one.",
+			            "type": "string"
+			          }
+			        },
+			        "required": [
+			          "message"
+			        ],
+			        "type": "object"
+			      }
+			    },
+			    "responses": {
+			      "Error500": {
+			        "description": "Internal Server Error",
+			        "content": {
+			          "application/json": {
+			            "schema": {
+			              "$ref": "#/components/schemas/Error"
+			            },
+			            "examples": {
+			              "ErrFive": {
+			                "summary": "5 ErrFive",
+			                "value": {
+			                  "code": 5,
+			                  "message": "internal error"
+			                }
+			              }
+			            }
+			          }
+			        }
+			      }
+			    }
+			  }
+			}
+		`, nil},
+		{[]string{"diff", yaml, reg}, `
+			4012 added
+		`, nil},
+		{[]string{"diff", yaml, cat}, `
+			5 name: "ErrFive" -> "" (breaking)
+			5 description: "" -> "cinq; five"
+		`, errors.New("1 breaking changes")},
+		{[]string{"diff", reg, reg2}, `
+			5 removed (breaking)
+			4012 description: "The invoice doesn't exist." -> ""
+			4012 category: "billing" -> "" (breaking)
+			4012 http_status: "404" -> "410" (breaking)
+			4012 help_url: "https://example.com/errors/4012" -> ""
+			4013 added
+		`, errors.New("3 breaking changes")},
+		{[]string{"diff", reg}, "", flag.ErrHelp},
+		{[]string{"-r", reg}, "", flag.ErrHelp},
+		{[]string{"-r", reg, "lookup"}, "", flag.ErrHelp},
+		{[]string{"-r", reg, "explode"}, "", flag.ErrHelp},
+		{[]string{"-r", reg, "lookup", "x"}, "", nil},
+		{[]string{"-r", "/nonexistent", "list"}, "", os.ErrNotExist},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			b := new(strings.Builder)
+			err := run(b, tt.args)
+			if tt.want == "" {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("wrong err: %v", err)
+				}
+				return
+			}
+			if fmt.Sprint(err) != fmt.Sprint(tt.wantErr) {
+				t.Fatalf("wrong err: %v", err)
+			}
+
+			want := strings.ReplaceAll(strings.TrimLeft(tt.want, "\n"), "\t", "")
+			if out := b.String(); out != want {
+				t.Errorf("\nout:\n%s\nwant:\n%s", out, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package guru
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrorCode is an error code, for declaring codes as typed constants:
+//
+//	const ErrInvoiceMissing guru.ErrorCode = 4012
+//
+//	func init() { guru.Register(int(ErrInvoiceMissing), "ErrInvoiceMissing", "") }
+//
+//	err := ErrInvoiceMissing.New("no such invoice")
+//	if ErrInvoiceMissing.Is(err) {
+//		fmt.Println(ErrInvoiceMissing)  // ErrInvoiceMissing (4012)
+//	}
+//
+// The errors are the same as those created with New, Wrap, etc.; all functions
+// that accept an int code work as usual.
+type ErrorCode int
+
+// CodeOf is like Code, but returns an ErrorCode.
+func CodeOf(err error) ErrorCode { return ErrorCode(Code(err)) }
+
+// Int gets the code as an int.
+func (c ErrorCode) Int() int { return int(c) }
+
+// String gets the name registered in DefaultRegistry followed by the code, such
+// as "ErrInvoiceMissing (4012)", or just the code if it has no name.
+func (c ErrorCode) String() string {
+	if n := DefaultRegistry.Name(int(c)); n != "" {
+		return n + " (" + fmtCode(int(c)) + ")"
+	}
+	return fmtCode(int(c))
+}
+
+// Info gets the information registered in DefaultRegistry; see
+// Registry.Lookup.
+func (c ErrorCode) Info() (Info, bool) { return DefaultRegistry.Lookup(int(c)) }
+
+// Is reports if the highest-level code of err is c, as with Is.
+func (c ErrorCode) Is(err error) bool { return Is(err, int(c)) }
+
+// Has reports if any error in the chain of err has c as the code, as with Has.
+func (c ErrorCode) Has(err error) bool { return Has(err, int(c)) }
+
+// In reports if c is one of codes.
+func (c ErrorCode) In(codes ...ErrorCode) bool {
+	for _, cc := range codes {
+		if c == cc {
+			return true
+		}
+	}
+	return false
+}
+
+// New is like New, using c as the code.
+func (c ErrorCode) New(msg string) error {
+	return created(KindNew, int(c), msg, nil, &withCode{
+		error: errors.New(msg),
+		code:  int(c),
+	})
+}
+
+// Errorf is like Errorf, using c as the code.
+func (c ErrorCode) Errorf(format string, args ...interface{}) error {
+	return errorf(nil, int(c), format, args)
+}
+
+// Wrap is like Wrap, using c as the code. It will return nil if err is nil.
+func (c ErrorCode) Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return created(KindWrap, int(c), msg, err, &wrapped{
+		msg:   msg,
+		code:  int(c),
+		error: err,
+	})
+}
+
+// MarshalJSON encodes the code as a JSON number, or a string if a format was
+// set with SetCodeFormat.
+func (c ErrorCode) MarshalJSON() ([]byte, error) { return jsonCode(c).MarshalJSON() }
+
+// UnmarshalJSON decodes the code from a JSON number, or a string with a code (in
+// any format ParseCode accepts) or a name registered in DefaultRegistry.
+func (c *ErrorCode) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*c = ErrorCode(n)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("guru.ErrorCode: not a number or string: %s", data)
+	}
+	if n, err := ParseCode(s); err == nil {
+		*c = ErrorCode(n)
+		return nil
+	}
+	for _, info := range DefaultRegistry.All() {
+		if info.Name == s {
+			*c = ErrorCode(info.Code)
+			return nil
+		}
+	}
+	return fmt.Errorf("guru.ErrorCode: no code with the name %q", s)
+}
@@ -0,0 +1,87 @@
+package guru
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	resetRegistry(t)
+	Register(4012, "ErrInvoiceMissing", "The invoice doesn't exist.")
+	const (
+		missing ErrorCode = 4012
+		other   ErrorCode = 5
+	)
+
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{missing, "ErrInvoiceMissing (4012)"},
+		{other, "5"},
+		{missing.New("x"), "error 4012: x"},
+		{missing.Errorf("x: %w", io.EOF), "error 4012: x: EOF"},
+		{missing.Wrap(io.EOF, "x"), "error 4012: EOF: x"},
+		{missing.Wrap(nil, "x"), "<nil>"},
+		{CodeOf(missing.New("x")), "ErrInvoiceMissing (4012)"},
+		{CodeOf(io.EOF), "0"},
+		{missing.Int(), "4012"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := fmt.Sprintf("%v", tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	err := WithCode(1, missing.New("x"))
+	if missing.Is(err) || !missing.Has(err) || !ErrorCode(1).Is(err) {
+		t.Error("Is/Has wrong")
+	}
+	if !missing.In(other, missing) || missing.In(other) || missing.In() {
+		t.Error("In wrong")
+	}
+	if info, ok := missing.Info(); !ok || info.Description != "The invoice doesn't exist." {
+		t.Errorf("Info: %v %#v", ok, info)
+	}
+}
+
+func TestErrorCodeJSON(t *testing.T) {
+	resetRegistry(t)
+	Register(4012, "ErrInvoiceMissing", "")
+
+	j, err := json.Marshal(map[string]ErrorCode{"code": 4012})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(j) != `{"code":4012}` {
+		t.Errorf("%s", j)
+	}
+
+	tests := []struct {
+		in      string
+		want    ErrorCode
+		wantErr string
+	}{
+		{`4012`, 4012, ""},
+		{`"4012"`, 4012, ""},
+		{`"ErrInvoiceMissing"`, 4012, ""},
+		{`"ErrNope"`, 0, `guru.ErrorCode: no code with the name "ErrNope"`},
+		{`true`, 0, `guru.ErrorCode: not a number or string: true`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			var out ErrorCode
+			err := json.Unmarshal([]byte(tt.in), &out)
+			if (err == nil && tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Fatalf("\nout:  %v\nwant: %v\n", err, tt.wantErr)
+			}
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
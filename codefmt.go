@@ -0,0 +1,134 @@
+package guru
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// CodeFormat sets how codes are displayed; see SetCodeFormat.
+type CodeFormat struct {
+	Base  int // 10 (the default if 0), 16, or 8.
+	Width int // Minimum number of digits, padded with zeros.
+}
+
+var codeFormat atomic.Pointer[CodeFormat]
+
+// SetCodeFormat sets how codes and subcodes are displayed. Hexadecimal codes
+// are written as "0x1F4", octal codes as "0o764", and decimal codes are padded
+// with zeros to Width digits ("0042").
+//
+// This applies to the %v and %+v verbs, Tree, Fprint, ErrorCode.String,
+// FormatCode, the {code} variable in Localize, WriteMarkdown, WriteHTML,
+// MarshalJSON, MarshalText, and MarshalXML, as well as the JSON:API code in
+// guruhttp and the tags in gurusentry. Parse, ParseCode, FromJSON, and FromText
+// accept codes in any format regardless of this setting. Fingerprints and
+// exported registries always use plain decimal.
+//
+// Setting a format changes the JSON schema: the "code" field is written as a
+// string such as "0x1F4" instead of a number if the format isn't plain
+// decimal, so consumers that decode it as a number will break. The subcode is
+// always a number.
+//
+// It will panic if the base isn't 8, 10, or 16.
+func SetCodeFormat(f CodeFormat) {
+	switch f.Base {
+	case 0:
+		f.Base = 10
+	case 8, 10, 16:
+	default:
+		panic(fmt.Sprintf("guru.SetCodeFormat: invalid base %d", f.Base))
+	}
+	if f.Base == 10 && f.Width <= 1 {
+		codeFormat.Store(nil)
+		return
+	}
+	codeFormat.Store(&f)
+}
+
+// fmtCode formats the code n with the format set with SetCodeFormat.
+func fmtCode(n int) string {
+	f := codeFormat.Load()
+	if f == nil {
+		return strconv.Itoa(n)
+	}
+
+	sign, u := "", uint64(n)
+	if n < 0 {
+		sign, u = "-", uint64(-n)
+	}
+	var s string
+	switch f.Base {
+	case 16:
+		sign, s = sign+"0x", strings.ToUpper(strconv.FormatUint(u, 16))
+	case 8:
+		sign, s = sign+"0o", strconv.FormatUint(u, 8)
+	default:
+		s = strconv.FormatUint(u, 10)
+	}
+	if len(s) < f.Width {
+		s = strings.Repeat("0", f.Width-len(s)) + s
+	}
+	return sign + s
+}
+
+// FormatCode formats code with the format set with SetCodeFormat.
+func FormatCode(code int) string { return fmtCode(code) }
+
+// reCode matches a code in any of the formats SetCodeFormat writes.
+const reCode = `-?(?:0[xX][0-9a-fA-F]+|0[oO][0-7]+|[0-9]+)`
+
+// ParseCode parses a code in any of the formats from SetCodeFormat: a decimal
+// number (leading zeros are allowed, and don't mean octal), a hexadecimal
+// number starting with "0x", or an octal number starting with "0o".
+func ParseCode(s string) (int, error) {
+	num, neg := strings.CutPrefix(s, "-")
+	base := 10
+	switch {
+	case len(num) > 2 && (num[:2] == "0x" || num[:2] == "0X"):
+		num, base = num[2:], 16
+	case len(num) > 2 && (num[:2] == "0o" || num[:2] == "0O"):
+		num, base = num[2:], 8
+	}
+	if num == "" || num[0] == '+' || num[0] == '-' {
+		return 0, fmt.Errorf("guru.ParseCode: invalid code %q", s)
+	}
+	n, err := strconv.ParseInt(num, base, strconv.IntSize)
+	if err != nil {
+		return 0, fmt.Errorf("guru.ParseCode: invalid code %q", s)
+	}
+	if neg {
+		n = -n
+	}
+	return int(n), nil
+}
+
+// jsonCode is a code in JSON: a number, or a string if there is a code format.
+type jsonCode int
+
+func (c jsonCode) MarshalJSON() ([]byte, error) {
+	if codeFormat.Load() == nil {
+		return strconv.AppendInt(nil, int64(c), 10), nil
+	}
+	return strconv.AppendQuote(nil, fmtCode(int(c))), nil
+}
+
+func (c *jsonCode) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*c = jsonCode(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("code is not a number or string: %s", data)
+	}
+	n, err := ParseCode(s)
+	if err != nil {
+		return err
+	}
+	*c = jsonCode(n)
+	return nil
+}
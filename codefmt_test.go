@@ -0,0 +1,168 @@
+package guru
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetCodeFormat(t *testing.T) {
+	t.Cleanup(func() { SetCodeFormat(CodeFormat{}) })
+
+	tests := []struct {
+		f                    CodeFormat
+		wantV, wantJSON      string
+		wantText, wantString string
+	}{
+		{CodeFormat{}, "error 500.3: oh noes", `{"code":500,"subcode":3,"message":"oh noes"}`, "E500.3: oh noes", "500"},
+		{CodeFormat{Base: 10, Width: 1}, "error 500.3: oh noes", `{"code":500,"subcode":3,"message":"oh noes"}`, "E500.3: oh noes", "500"},
+		{CodeFormat{Base: 16}, "error 0x1F4.0x3: oh noes", `{"code":"0x1F4","subcode":3,"message":"oh noes"}`, "E0x1F4.0x3: oh noes", "0x1F4"},
+		{CodeFormat{Base: 16, Width: 4}, "error 0x01F4.0x0003: oh noes", `{"code":"0x01F4","subcode":3,"message":"oh noes"}`, "E0x01F4.0x0003: oh noes", "0x01F4"},
+		{CodeFormat{Base: 8}, "error 0o764.0o3: oh noes", `{"code":"0o764","subcode":3,"message":"oh noes"}`, "E0o764.0o3: oh noes", "0o764"},
+		{CodeFormat{Width: 5}, "error 00500.00003: oh noes", `{"code":"00500","subcode":3,"message":"oh noes"}`, "E00500.00003: oh noes", "00500"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			SetCodeFormat(tt.f)
+			err := NewSub(500, 3, "oh noes")
+
+			if out := fmt.Sprintf("%v", err); out != tt.wantV {
+				t.Errorf("%%v\nout:  %#v\nwant: %#v\n", out, tt.wantV)
+			}
+			if out := Tree(err); out != tt.wantV {
+				t.Errorf("Tree\nout:  %#v\nwant: %#v\n", out, tt.wantV)
+			}
+			if out := ErrorCode(500).String(); out != tt.wantString {
+				t.Errorf("String\nout:  %#v\nwant: %#v\n", out, tt.wantString)
+			}
+
+			j, jErr := MarshalJSON(err)
+			if jErr != nil {
+				t.Fatal(jErr)
+			}
+			if string(j) != tt.wantJSON {
+				t.Errorf("JSON\nout:  %s\nwant: %s\n", j, tt.wantJSON)
+			}
+			text, _ := MarshalText(err)
+			if string(text) != tt.wantText {
+				t.Errorf("text\nout:  %s\nwant: %s\n", text, tt.wantText)
+			}
+
+			for _, dec := range []func() (error, error){
+				func() (error, error) { return FromJSON(j) },
+				func() (error, error) { return FromText(text) },
+				func() (error, error) { p, _ := Parse(tt.wantV); return p, nil },
+			} {
+				out, err := dec()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if Code(out) != 500 || Subcode(out) != 3 {
+					t.Errorf("decode: %d.%d", Code(out), Subcode(out))
+				}
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("no panic")
+			}
+		}()
+		SetCodeFormat(CodeFormat{Base: 2})
+	})
+}
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"500", 500, false},
+		{"-500", -500, false},
+		{"00500", 500, false},
+		{"0x1F4", 500, false},
+		{"0X1f4", 500, false},
+		{"-0x1F4", -500, false},
+		{"0o764", 500, false},
+		{"0", 0, false},
+
+		{"", 0, true},
+		{"-", 0, true},
+		{"+500", 0, true},
+		{"--500", 0, true},
+		{"0x", 0, true},
+		{"0x-1", 0, true},
+		{"0o8", 0, true},
+		{"0b101", 0, true},
+		{"1_000", 0, true},
+		{"x", 0, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, err := ParseCode(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err: %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONCode(t *testing.T) {
+	t.Cleanup(func() { SetCodeFormat(CodeFormat{}) })
+	SetCodeFormat(CodeFormat{Base: 16})
+
+	j, err := json.Marshal(struct{ C ErrorCode }{500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(j) != `{"C":"0x1F4"}` {
+		t.Errorf("\nout:  %s", j)
+	}
+	var out struct{ C ErrorCode }
+	if err := json.Unmarshal(j, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.C != 500 {
+		t.Errorf("\nout:  %d", out.C)
+	}
+}
+
+func TestCodeFormatOutput(t *testing.T) {
+	resetRegistry(t)
+	t.Cleanup(func() { SetCodeFormat(CodeFormat{}) })
+	SetCodeFormat(CodeFormat{Base: 16, Width: 4})
+
+	RegisterCatalog("nl", map[int]string{4012: "fout {code}"})
+	if out := Localize(New(4012, "x"), "nl"); out != "fout 0x0FAC" {
+		t.Errorf("Localize\nout:  %#v", out)
+	}
+	if out := FormatCode(4012); out != "0x0FAC" {
+		t.Errorf("FormatCode\nout:  %#v", out)
+	}
+
+	b := new(strings.Builder)
+	if err := testRegistry().WriteMarkdown(b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| 0x0FAC | ErrInvoiceMissing |", "use 0x019B instead"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteMarkdown: no %q in\n%s", want, b)
+		}
+	}
+	b.Reset()
+	if err := testRegistry().WriteHTML(b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<tr><td>0x0FAC</td>", "use 0x019B instead"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteHTML: no %q in\n%s", want, b)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package guru
+
+import (
+	"fmt"
+)
+
+// Bit layout of codes created with Compose, from the most significant bit:
+//
+//	bit  31     always 0, so codes are never negative
+//	bits 24-30  subsystem (0 to 127)
+//	bits 16-23  category  (0 to 255)
+//	bits  0-15  detail    (0 to 65535)
+//
+// This fits in 32 bits on all platforms, and each part is a separate group of
+// hex digits: subsystem 2, category 3, detail 1 is 0x02030001 (which is also
+// how GuruString and SetCodeFormat(CodeFormat{Base: 16, Width: 8}) display
+// it).
+const (
+	MaxSubsystem = 1<<7 - 1
+	MaxCategory  = 1<<8 - 1
+	MaxDetail    = 1<<16 - 1
+)
+
+// Compose creates a code from the subsystem, category, and detail, for
+// partitioning the codes in a systematic way:
+//
+//	const (
+//		Billing  = 2
+//		NotFound = 3
+//	)
+//
+//	var ErrInvoiceMissing = guru.New(guru.Compose(Billing, NotFound, 1), "no invoice")
+//
+// It will panic if any of the parts are negative or larger than MaxSubsystem,
+// MaxCategory, or MaxDetail. Note that Compose(0, 0, 0) is 0, which is the same
+// as no code.
+func Compose(subsystem, category, detail int) int {
+	switch {
+	case subsystem < 0 || subsystem > MaxSubsystem:
+		panic(fmt.Sprintf("guru.Compose: subsystem %d is outside the range 0 to %d", subsystem, MaxSubsystem))
+	case category < 0 || category > MaxCategory:
+		panic(fmt.Sprintf("guru.Compose: category %d is outside the range 0 to %d", category, MaxCategory))
+	case detail < 0 || detail > MaxDetail:
+		panic(fmt.Sprintf("guru.Compose: detail %d is outside the range 0 to %d", detail, MaxDetail))
+	}
+	return subsystem<<24 | category<<16 | detail
+}
+
+// Decompose splits a code created with Compose into its parts. Codes outside
+// the range of Compose (such as negative codes) are masked to the layout.
+func Decompose(code int) (subsystem, category, detail int) {
+	return code >> 24 & MaxSubsystem, code >> 16 & MaxCategory, code & MaxDetail
+}
+
+// Subsystem gets the subsystem of Code(err), for codes created with Compose.
+func Subsystem(err error) int { s, _, _ := Decompose(Code(err)); return s }
+
+// CodeCategory gets the category of Code(err), for codes created with Compose.
+//
+// This is unrelated to the category name from the registry that Category
+// returns.
+func CodeCategory(err error) int { _, c, _ := Decompose(Code(err)); return c }
+
+// CodeDetail gets the detail of Code(err), for codes created with Compose.
+//
+// This is unrelated to the typed details that Detail returns.
+func CodeDetail(err error) int { _, _, d := Decompose(Code(err)); return d }
@@ -0,0 +1,66 @@
+package guru
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		sub, cat, detail int
+		want             int
+		wantPanic        string
+	}{
+		{0, 0, 0, 0, ""},
+		{2, 3, 1, 0x02030001, ""},
+		{MaxSubsystem, MaxCategory, MaxDetail, 0x7FFFFFFF, ""},
+		{MaxSubsystem + 1, 0, 0, 0, "guru.Compose: subsystem 128 is outside the range 0 to 127"},
+		{0, -1, 0, 0, "guru.Compose: category -1 is outside the range 0 to 255"},
+		{0, 0, MaxDetail + 1, 0, "guru.Compose: detail 65536 is outside the range 0 to 65535"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil && tt.wantPanic != "" {
+					t.Fatal("no panic")
+				}
+				if r != nil && r != tt.wantPanic {
+					t.Fatalf("\nout:  %#v\nwant: %#v\n", r, tt.wantPanic)
+				}
+			}()
+
+			out := Compose(tt.sub, tt.cat, tt.detail)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			s, c, d := Decompose(out)
+			if s != tt.sub || c != tt.cat || d != tt.detail {
+				t.Errorf("Decompose: %d, %d, %d", s, c, d)
+			}
+		})
+	}
+}
+
+func TestDecomposeErr(t *testing.T) {
+	err := fmt.Errorf("context: %w", New(Compose(2, 3, 1), "oh noes"))
+	if s := Subsystem(err); s != 2 {
+		t.Errorf("Subsystem: %d", s)
+	}
+	if c := CodeCategory(err); c != 3 {
+		t.Errorf("CodeCategory: %d", c)
+	}
+	if d := CodeDetail(err); d != 1 {
+		t.Errorf("CodeDetail: %d", d)
+	}
+	if g := GuruString(err); g != "Guru Meditation #02030001.00000000" {
+		t.Errorf("GuruString: %s", g)
+	}
+
+	if s, c, d := Decompose(-1); s != MaxSubsystem || c != MaxCategory || d != MaxDetail {
+		t.Errorf("Decompose(-1): %d, %d, %d", s, c, d)
+	}
+	if s := Subsystem(nil); s != 0 {
+		t.Errorf("Subsystem(nil): %d", s)
+	}
+}
@@ -0,0 +1,40 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type constError struct {
+	code int
+	msg  string
+}
+
+func (e constError) Error() string { return e.msg }
+func (e constError) Code() int     { return e.code }
+func (e constError) Is(target error) bool {
+	c, ok := target.(CodeError)
+	return ok && int(c) == e.code
+}
+func (e constError) Format(s fmt.State, verb rune) {
+	formatCode(s, verb, nil, e.code, 0, "", plainError(e.msg))
+}
+
+// plainError is an error without a Format method; this avoids an allocation
+// from errors.New for formatCode.
+type plainError string
+
+func (e plainError) Error() string { return string(e) }
+
+// Const returns an immutable error with an error code, for use as a
+// package-level sentinel error:
+//
+//	var ErrNotFound = guru.Const(404, "not found")
+//
+// Unlike New, errors from Const are comparable: two errors with the same code
+// and message are equal with ==, and errors.Is works on errors that wrap it.
+// They can be wrapped to add context:
+//
+//	return guru.Wrap(404, ErrNotFound, "find invoice")
+//
+// Creating the error doesn't call hooks or check if the code is deprecated.
+func Const(code int, msg string) error { return constError{code: code, msg: msg} }
@@ -0,0 +1,69 @@
+package guru
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errNotFound = Const(404, "not found")
+
+func TestConst(t *testing.T) {
+	tests := []struct {
+		in       error
+		want     string
+		wantS    string
+		wantPlus string
+	}{
+		{errNotFound, "error 404: not found", "not found", "error 404: not found"},
+		{Wrap(404, errNotFound, "find invoice"), "error 404: error 404: not found: find invoice",
+			"not found: find invoice", "error 404: find invoice\nerror 404: not found"},
+		{fmt.Errorf("ctx: %w", errNotFound), "ctx: error 404: not found", "ctx: error 404: not found", "ctx: error 404: not found"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := fmt.Sprintf("%v", tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if out := fmt.Sprintf("%s", tt.in); out != tt.wantS {
+				t.Errorf("%%s\nout:  %#v\nwant: %#v\n", out, tt.wantS)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlus)
+			}
+			if !errors.Is(tt.in, errNotFound) || !errors.Is(tt.in, CodeError(404)) || Code(tt.in) != 404 {
+				t.Error("not errNotFound")
+			}
+			if errors.Is(tt.in, Const(404, "other")) || errors.Is(tt.in, New(404, "not found")) {
+				t.Error("matches other error")
+			}
+		})
+	}
+
+	if errNotFound != Const(404, "not found") {
+		t.Error("not equal")
+	}
+	if j, _ := MarshalJSON(errNotFound); string(j) != `{"code":404,"message":"not found"}` {
+		t.Errorf("JSON: %s", j)
+	}
+
+	type reply struct{ Err error }
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(reply{errNotFound}); err != nil {
+		t.Fatal(err)
+	}
+	var out reply
+	if err := gob.NewDecoder(buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Err != errNotFound {
+		t.Errorf("gob: %#v", out.Err)
+	}
+
+	err := Wrap(404, errNotFound, "ctx")
+	if n := testing.AllocsPerRun(100, func() { _, _ = errors.Is(err, errNotFound), Code(errNotFound) }); n != 0 {
+		t.Errorf("%v allocations", n)
+	}
+}
@@ -0,0 +1,68 @@
+package guru
+
+import (
+	"context"
+	"fmt"
+)
+
+type ctxKey struct{}
+
+type ctxValue struct {
+	err     error
+	code    int
+	hasCode bool
+}
+
+// NewContext returns a copy of ctx with err stored in it, which can be
+// retrieved with FromContext. The code of err is used for CodeFromContext, or
+// the code already in ctx if err has no code.
+func NewContext(ctx context.Context, err error) context.Context {
+	v := ctxValue{err: err}
+	if hasCode(err) {
+		v.code, v.hasCode = Code(err), true
+	} else {
+		v.code, v.hasCode = CodeFromContext(ctx)
+	}
+	return context.WithValue(ctx, ctxKey{}, v)
+}
+
+// ContextWithCode returns a copy of ctx with the default error code code
+// stored in it, for CodeFromContext and WrapContext. For example a HTTP
+// middleware can set the code for all errors in a group of routes.
+func ContextWithCode(ctx context.Context, code int) context.Context {
+	return context.WithValue(ctx, ctxKey{}, ctxValue{err: FromContext(ctx), code: code, hasCode: true})
+}
+
+// FromContext gets the error stored with NewContext, or nil if there is none.
+func FromContext(ctx context.Context) error {
+	v, _ := ctx.Value(ctxKey{}).(ctxValue)
+	return v.err
+}
+
+// CodeFromContext gets the code most recently stored with ContextWithCode or
+// NewContext. The second return value reports if there is a code.
+func CodeFromContext(ctx context.Context) (int, bool) {
+	v, _ := ctx.Value(ctxKey{}).(ctxValue)
+	return v.code, v.hasCode
+}
+
+// WrapContext is like Wrap, but uses the code from CodeFromContext. If there
+// is no code in the context err is wrapped with fmt.Errorf("%s: %w") if there
+// is a message, or returned as-is if there isn't. It will return nil if err is
+// nil.
+func WrapContext(ctx context.Context, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	code, ok := CodeFromContext(ctx)
+	if !ok {
+		if msg == "" {
+			return err
+		}
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if msg == "" {
+		return created(KindWrap, code, "", err, &withCode{error: err, code: code})
+	}
+	return created(KindWrap, code, msg, err, &wrapped{msg: msg, code: code, error: err})
+}
@@ -0,0 +1,63 @@
+package guru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	bg := context.Background()
+	err := New(42, "oh noes")
+
+	tests := []struct {
+		ctx      context.Context
+		wantErr  error
+		wantCode int
+		wantOK   bool
+		wantWrap string
+	}{
+		{bg, nil, 0, false, "ctx: inner"},
+		{NewContext(bg, err), err, 42, true, "error 42: inner: ctx"},
+		{NewContext(bg, errors.New("x")), errors.New("x"), 0, false, "ctx: inner"},
+		{ContextWithCode(bg, 500), nil, 500, true, "error 500: inner: ctx"},
+		{ContextWithCode(NewContext(bg, err), 500), err, 500, true, "error 500: inner: ctx"},
+		{NewContext(ContextWithCode(bg, 500), err), err, 42, true, "error 42: inner: ctx"},
+		{NewContext(ContextWithCode(bg, 500), errors.New("x")), errors.New("x"), 500, true, "error 500: inner: ctx"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if e := FromContext(tt.ctx); fmt.Sprint(e) != fmt.Sprint(tt.wantErr) {
+				t.Errorf("FromContext\nout:  %#v\nwant: %#v\n", e, tt.wantErr)
+			}
+			code, ok := CodeFromContext(tt.ctx)
+			if code != tt.wantCode || ok != tt.wantOK {
+				t.Errorf("CodeFromContext\nout:  %d %t\nwant: %d %t\n", code, ok, tt.wantCode, tt.wantOK)
+			}
+
+			w := WrapContext(tt.ctx, errors.New("inner"), "ctx")
+			if out := fmt.Sprint(w); out != tt.wantWrap {
+				t.Errorf("WrapContext\nout:  %#v\nwant: %#v\n", out, tt.wantWrap)
+			}
+			if Code(w) != tt.wantCode {
+				t.Errorf("WrapContext code\nout:  %d\nwant: %d\n", Code(w), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWrapContext(t *testing.T) {
+	ctx := ContextWithCode(context.Background(), 500)
+	if err := WrapContext(ctx, nil, "x"); err != nil {
+		t.Errorf("not nil: %#v", err)
+	}
+	inner := errors.New("x")
+	if err := WrapContext(context.Background(), inner, ""); err != inner {
+		t.Errorf("wrapped: %#v", err)
+	}
+	if err := WrapContext(ctx, inner, ""); Code(err) != 500 || !errors.Is(err, inner) {
+		t.Errorf("wrong: %#v", err)
+	}
+}
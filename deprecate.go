@@ -0,0 +1,81 @@
+package guru
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// DeprecationMode sets what happens when an error is created with a
+// deprecated code.
+type DeprecationMode int32
+
+// Deprecation modes.
+const (
+	DeprecationIgnore DeprecationMode = iota // Do nothing (the default).
+	DeprecationWarn                          // Print a warning to stderr.
+	DeprecationPanic                         // Panic.
+)
+
+var deprecationMode atomic.Int32
+
+// SetDeprecationMode sets what New, Errorf, Wrap, etc. do when they're called
+// with a code that's deprecated in DefaultRegistry (or the registry for
+// Registry.New, Registry.Wrap, etc.). This is intended for development and
+// tests.
+func SetDeprecationMode(m DeprecationMode) { deprecationMode.Store(int32(m)) }
+
+type deprecation struct {
+	replacement int
+	reason      string
+}
+
+// Deprecate marks the error code code as deprecated, with the code that
+// should be used instead (0 if there is none) and the reason.
+func (r *Registry) Deprecate(code, replacement int, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deprecated == nil {
+		r.deprecated = make(map[int]deprecation)
+	}
+	r.deprecated[code] = deprecation{replacement: replacement, reason: reason}
+}
+
+// Deprecate calls DefaultRegistry.Deprecate.
+func Deprecate(code, replacement int, reason string) {
+	DefaultRegistry.Deprecate(code, replacement, reason)
+}
+
+// checkDeprecated warns or panics if code is deprecated in the registry err
+// was created with, depending on the DeprecationMode. skip is the number of
+// stack frames to skip for the location of the caller, with 0 being the caller
+// of checkDeprecated.
+func checkDeprecated(err error, code, skip int) {
+	mode := DeprecationMode(deprecationMode.Load())
+	if mode == DeprecationIgnore {
+		return
+	}
+
+	reg := registryOf(err, false)
+	reg.mu.RLock()
+	d, ok := reg.deprecated[code]
+	reg.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	msg := fmt.Sprintf("guru: code %d is deprecated", code)
+	if d.reason != "" {
+		msg += ": " + d.reason
+	}
+	if d.replacement != 0 {
+		msg += fmt.Sprintf("; use %d instead", d.replacement)
+	}
+	if _, file, line, ok := runtime.Caller(skip + 2); ok {
+		msg += fmt.Sprintf(" (at %s:%d)", file, line)
+	}
+	if mode == DeprecationPanic {
+		panic(msg)
+	}
+	fmt.Fprintln(stderr, msg)
+}
@@ -0,0 +1,76 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDeprecate(t *testing.T) {
+	resetRegistry(t)
+	Deprecate(410, 411, "reworded")
+	Deprecate(420, 0, "")
+
+	info, ok := DefaultRegistry.Lookup(410)
+	want := Info{Code: 410, Deprecated: true, ReplacedBy: 411, DeprecationReason: "reworded"}
+	if !ok || info != want {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", info, want)
+	}
+
+	buf := new(strings.Builder)
+	origStderr := stderr
+	stderr = buf
+	t.Cleanup(func() {
+		stderr = origStderr
+		SetDeprecationMode(DeprecationIgnore)
+	})
+
+	err := errors.New("x")
+	calls := []func(){
+		func() { New(410, "x") },
+		func() { NewSub(410, 1, "x") },
+		func() { Errorf(410, "x") },
+		func() { WithCode(410, err) },
+		func() { Wrap(410, err, "x") },
+		func() { Wrapf(410, err, "x") },
+		func() { NewStack(410, "x") },
+		func() { E(410) },
+	}
+
+	New(410, "x")
+	if buf.Len() != 0 {
+		t.Errorf("warning with DeprecationIgnore: %q", buf)
+	}
+
+	SetDeprecationMode(DeprecationWarn)
+	for i, f := range calls {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			buf.Reset()
+			f()
+			re := `^guru: code 410 is deprecated: reworded; use 411 instead \(at .*/deprecate_test\.go:\d+\)\n$`
+			if !regexp.MustCompile(re).MatchString(buf.String()) {
+				t.Errorf("\nout:  %q\nwant: %q\n", buf, re)
+			}
+		})
+	}
+
+	buf.Reset()
+	New(420, "x")
+	New(411, "x")
+	WithCode(410, nil)
+	if out := buf.String(); !strings.HasPrefix(out, "guru: code 420 is deprecated (at ") || strings.Count(out, "\n") != 1 {
+		t.Errorf("%q", out)
+	}
+
+	SetDeprecationMode(DeprecationPanic)
+	func() {
+		defer func() {
+			if r := recover(); r == nil || !strings.HasPrefix(r.(string), "guru: code 410 is deprecated") {
+				t.Errorf("wrong panic: %v", r)
+			}
+		}()
+		New(410, "x")
+	}()
+}
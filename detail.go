@@ -0,0 +1,66 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type withDetail struct {
+	error
+	detail interface{}
+}
+
+func (e *withDetail) Unwrap() error { return e.error }
+func (e withDetail) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.error) {
+		fmt.Fprintf(s, "\ndetail: %+v", e.detail)
+	}
+}
+
+// WithDetail annotates err with a typed detail, such as quota information or
+// validation results, which can be retrieved with Detail:
+//
+//	err = guru.WithDetail(err, QuotaFailure{Limit: 100, Used: 100})
+//
+//	if q, ok := guru.Detail[QuotaFailure](err); ok {
+//		...
+//	}
+//
+// The detail doesn't change the error message, but is printed with the %+v
+// verb. It will return nil if err is nil.
+func WithDetail(err error, v interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &withDetail{error: err, detail: v}
+}
+
+// Detail gets the first detail of type T added with WithDetail.
+//
+// Details decoded with FromJSON are the generic JSON values (such as
+// map[string]interface{}), rather than the original types.
+func Detail[T any](err error) (T, bool) {
+	var (
+		d  T
+		ok bool
+	)
+	walk(err, func(err error) bool {
+		if wd, isD := err.(*withDetail); isD {
+			d, ok = wd.detail.(T)
+		}
+		return !ok
+	})
+	return d, ok
+}
+
+// Details gets all details added with WithDetail, from the outermost error to
+// the innermost. It will return nil if there are none.
+func Details(err error) []interface{} {
+	var details []interface{}
+	walk(err, func(err error) bool {
+		if wd, ok := err.(*withDetail); ok {
+			details = append(details, wd.detail)
+		}
+		return true
+	})
+	return details
+}
@@ -0,0 +1,72 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type quotaFailure struct {
+	Limit, Used int
+}
+
+func TestDetail(t *testing.T) {
+	tests := []struct {
+		in          error
+		wantQuota   quotaFailure
+		wantOK      bool
+		wantDetails []interface{}
+		wantPlus    string
+	}{
+		{nil, quotaFailure{}, false, nil, "<nil>"},
+		{WithDetail(nil, 1), quotaFailure{}, false, nil, "<nil>"},
+		{errors.New("x"), quotaFailure{}, false, nil, "x"},
+		{WithDetail(New(1, "x"), quotaFailure{100, 100}), quotaFailure{100, 100}, true,
+			[]interface{}{quotaFailure{100, 100}}, "error 1: x\ndetail: {Limit:100 Used:100}"},
+		{WithDetail(Wrap(2, WithDetail(New(1, "x"), quotaFailure{1, 2}), "y"), "version 3"), quotaFailure{1, 2}, true,
+			[]interface{}{"version 3", quotaFailure{1, 2}}, "error 2: y\nerror 1: x\ndetail: {Limit:1 Used:2}\ndetail: version 3"},
+		{WithDetail(New(1, "x"), &quotaFailure{1, 2}), quotaFailure{}, false,
+			[]interface{}{&quotaFailure{1, 2}}, "error 1: x\ndetail: &{Limit:1 Used:2}"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			q, ok := Detail[quotaFailure](tt.in)
+			if q != tt.wantQuota || ok != tt.wantOK {
+				t.Errorf("\nout:  %#v %t\nwant: %#v %t\n", q, ok, tt.wantQuota, tt.wantOK)
+			}
+			if out := Details(tt.in); !reflect.DeepEqual(out, tt.wantDetails) {
+				t.Errorf("details\nout:  %#v\nwant: %#v\n", out, tt.wantDetails)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlus)
+			}
+		})
+	}
+
+	err := WithDetail(New(1, "x"), "d")
+	if fmt.Sprintf("%v", err) != "error 1: x" || Code(err) != 1 {
+		t.Errorf("%v", err)
+	}
+	if s, ok := Detail[string](err); !ok || s != "d" {
+		t.Errorf("%q %t", s, ok)
+	}
+}
+
+func TestDetailJSON(t *testing.T) {
+	err := WithDetail(Wrap(2, WithDetail(New(1, "x"), quotaFailure{1, 2}), "y"), "v3")
+	j, jErr := MarshalJSON(err)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	want := `{"code":2,"message":"y","details":["v3"],"wrapped":{"code":1,"message":"x","details":[{"Limit":1,"Used":2}]}}`
+	if string(j) != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", j, want)
+	}
+
+	back := mustFromJSON(t, string(j))
+	wantDetails := []interface{}{"v3", map[string]interface{}{"Limit": 1.0, "Used": 2.0}}
+	if out := Details(back); !reflect.DeepEqual(out, wantDetails) {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", out, wantDetails)
+	}
+}
@@ -0,0 +1,101 @@
+package guru
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ChangeKind is the kind of a Change.
+type ChangeKind int
+
+// Change kinds.
+const (
+	ChangeAdded    ChangeKind = iota + 1 // Code is new.
+	ChangeRemoved                        // Code no longer exists.
+	ChangeModified                       // Field of the code was changed.
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	}
+	return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Change is a difference between two registries, as reported by Diff.
+type Change struct {
+	Code     int
+	Kind     ChangeKind
+	Field    string // Field name as in the JSON export, for ChangeModified.
+	Old, New string // Old and new value, for ChangeModified.
+	Breaking bool   // Change may break callers.
+}
+
+func (c Change) String() string {
+	s := strconv.Itoa(c.Code) + " " + c.Kind.String()
+	if c.Kind == ChangeModified {
+		s = fmt.Sprintf("%d %s: %q -> %q", c.Code, c.Field, c.Old, c.New)
+	}
+	if c.Breaking {
+		s += " (breaking)"
+	}
+	return s
+}
+
+// Diff reports the differences between the registries old and new, sorted by
+// code. This is intended for release checks, treating error codes as part of
+// an API.
+//
+// Changes that may break callers are marked as Breaking: removing a code
+// (unless it was deprecated in old), or changing the name, category, HTTP
+// status, or exit code. Changing the description and other fields is
+// reported, but isn't breaking.
+func Diff(old, new *Registry) []Change {
+	var (
+		o, n = old.All(), new.All()
+		ch   []Change
+	)
+	for len(o) > 0 || len(n) > 0 {
+		switch {
+		case len(n) == 0 || (len(o) > 0 && o[0].Code < n[0].Code):
+			ch = append(ch, Change{Code: o[0].Code, Kind: ChangeRemoved, Breaking: !o[0].Deprecated})
+			o = o[1:]
+		case len(o) == 0 || n[0].Code < o[0].Code:
+			ch = append(ch, Change{Code: n[0].Code, Kind: ChangeAdded})
+			n = n[1:]
+		default:
+			ch = append(ch, diffInfo(o[0], n[0])...)
+			o, n = o[1:], n[1:]
+		}
+	}
+	return ch
+}
+
+func diffInfo(o, n Info) []Change {
+	var ch []Change
+	add := func(field string, breaking bool, old, new interface{}) {
+		if old == new {
+			return
+		}
+		ch = append(ch, Change{Code: o.Code, Kind: ChangeModified, Field: field, Breaking: breaking,
+			Old: fmt.Sprint(old), New: fmt.Sprint(new)})
+	}
+	add("name", true, o.Name, n.Name)
+	add("description", false, o.Description, n.Description)
+	add("message", false, o.Message, n.Message)
+	add("category", true, o.Category, n.Category)
+	add("http_status", true, o.HTTPStatus, n.HTTPStatus)
+	add("exit_code", true, o.ExitCode, n.ExitCode)
+	add("retryable", false, o.Retryable, n.Retryable)
+	add("severity", false, o.Severity, n.Severity)
+	add("help_url", false, o.HelpURL, n.HelpURL)
+	add("deprecated", false, o.Deprecated, n.Deprecated)
+	add("replaced_by", false, o.ReplacedBy, n.ReplacedBy)
+	add("deprecation_reason", false, o.DeprecationReason, n.DeprecationReason)
+	return ch
+}
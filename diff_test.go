@@ -0,0 +1,42 @@
+package guru
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := &Registry{}
+	old.Register(1, "ErrFirst", "First.")
+	old.Register(2, "ErrSecond", "Second.")
+	old.Register(3, "ErrThird", "Third.")
+	old.Register(4, "ErrFourth", "Fourth.")
+	old.RegisterHTTPStatus(4, 404)
+	old.Deprecate(3, 0, "")
+
+	new := &Registry{}
+	new.Register(1, "ErrFirst", "First.")
+	new.Register(4, "ErrFour", "The fourth.")
+	new.RegisterHTTPStatus(4, 410)
+	new.Register(5, "ErrFifth", "Fifth.")
+
+	var out []string
+	for _, c := range Diff(old, new) {
+		out = append(out, c.String())
+	}
+	want := []string{
+		"2 removed (breaking)",
+		"3 removed",
+		`4 name: "ErrFourth" -> "ErrFour" (breaking)`,
+		`4 description: "Fourth." -> "The fourth."`,
+		`4 http_status: "404" -> "410" (breaking)`,
+		"5 added",
+	}
+	if fmt.Sprint(out) != fmt.Sprint(want) {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", out, want)
+	}
+
+	if d := Diff(old, old); d != nil {
+		t.Errorf("not nil: %v", d)
+	}
+}
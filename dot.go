@@ -0,0 +1,67 @@
+package guru
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT renders err as a Graphviz digraph, with a node for every error in the
+// chain labelled with the code and message, and an edge to every error it
+// wraps:
+//
+//	digraph err {
+//		node [shape=box];
+//		n0 [label="error 2: import"];
+//		n1 [label="2 errors"];
+//		n0 -> n1;
+//		...
+//	}
+//
+// It will return an empty graph if err is nil.
+func DOT(err error) string {
+	var b strings.Builder
+	b.WriteString("digraph err {\n\tnode [shape=box];\n")
+	n := 0
+	dot(&b, err, &n)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dot writes the nodes for err, returning the ID of the first node or -1 if
+// err is nil.
+func dot(b *strings.Builder, err error, n *int) int {
+	if err == nil {
+		return -1
+	}
+
+	var (
+		first = *n
+		prev  = -1
+		errs  = multi(err)
+	)
+	for _, e := range Flatten(err) {
+		if e.Depth > 0 {
+			continue
+		}
+		label := entryLine(e)
+		if label == "" && errs != nil {
+			label = fmt.Sprintf("%d errors", len(errs))
+		}
+		fmt.Fprintf(b, "\tn%d [label=\"%s\"];\n", *n, dotEscape(label))
+		if prev >= 0 {
+			fmt.Fprintf(b, "\tn%d -> n%d;\n", prev, *n)
+		}
+		prev = *n
+		*n++
+	}
+	for _, e := range errs {
+		if id := dot(b, e, n); id >= 0 {
+			fmt.Fprintf(b, "\tn%d -> n%d;\n", prev, id)
+		}
+	}
+	return first
+}
+
+var dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotEscape(s string) string { return dotReplacer.Replace(s) }
@@ -0,0 +1,43 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{nil, "digraph err {\n\tnode [shape=box];\n}\n"},
+		{Wrap(2, New(1, `say "x"`), "y\nz"), `digraph err {
+	node [shape=box];
+	n0 [label="error 2: y\nz"];
+	n1 [label="error 1: say \"x\""];
+	n0 -> n1;
+}
+`},
+		{Wrap(2, errors.Join(New(1, "a"), fmt.Errorf("b: %w", errors.New("c")), nil), "import"), `digraph err {
+	node [shape=box];
+	n0 [label="error 2: import"];
+	n1 [label="2 errors"];
+	n0 -> n1;
+	n2 [label="error 1: a"];
+	n1 -> n2;
+	n3 [label="b"];
+	n4 [label="c"];
+	n3 -> n4;
+	n1 -> n3;
+}
+`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := DOT(tt.in); out != tt.want {
+				t.Errorf("\nout:\n%s\nwant:\n%s", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package guru
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// For testing.
+var (
+	osExit           = os.Exit
+	stderr io.Writer = os.Stderr
+)
+
+// RegisterExitCode registers the process exit code to use for the error code
+// code in ExitCode and Exit.
+func (r *Registry) RegisterExitCode(code, exit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.exit == nil {
+		r.exit = make(map[int]int)
+	}
+	r.exit[code] = exit
+}
+
+// ExitCode gets the process exit code for err.
+//
+// The exit code registered with RegisterExitCode for Code(err) is used if
+// there is one, or 1 if there isn't. It will return 0 if err is nil.
+func (r *Registry) ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	code, ok := mappedCode(err)
+	if !ok {
+		return 1
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if exit, ok := r.exit[code]; ok {
+		return exit
+	}
+	return 1
+}
+
+// RegisterExitCode calls DefaultRegistry.RegisterExitCode.
+func RegisterExitCode(code, exit int) { DefaultRegistry.RegisterExitCode(code, exit) }
+
+// ExitCode calls ExitCode on the registry of err; see RegistryOf.
+func ExitCode(err error) int { return RegistryOf(err).ExitCode(err) }
+
+// Exit prints err to stderr and exits the process with ExitCode(err).
+//
+// It will exit with 0 without printing anything if err is nil.
+func Exit(err error) {
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+	}
+	osExit(ExitCode(err))
+}
@@ -0,0 +1,49 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExit(t *testing.T) {
+	resetRegistry(t)
+	RegisterExitCode(42, 3)
+	RegisterExitCode(0, 4)
+
+	buf := new(strings.Builder)
+	var exit int
+	origExit, origStderr := osExit, stderr
+	osExit, stderr = func(c int) { exit = c }, buf
+	t.Cleanup(func() { osExit, stderr = origExit, origStderr })
+
+	tests := []struct {
+		in       error
+		want     int
+		wantText string
+	}{
+		{nil, 0, ""},
+		{errors.New("oh noes"), 1, "oh noes\n"},
+		{New(42, "oh noes"), 3, "error 42: oh noes\n"},
+		{New(0, "oh noes"), 4, "error 0: oh noes\n"},
+		{Wrap(1, New(42, "oh noes"), "ctx"), 1, "error 1: error 42: oh noes: ctx\n"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			buf.Reset()
+			if out := ExitCode(tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+
+			Exit(tt.in)
+			if exit != tt.want {
+				t.Errorf("exit: %d", exit)
+			}
+			if buf.String() != tt.wantText {
+				t.Errorf("\nout:  %q\nwant: %q", buf.String(), tt.wantText)
+			}
+		})
+	}
+}
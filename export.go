@@ -0,0 +1,203 @@
+package guru
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// registryJSON is the JSON representation of a Registry.
+type registryJSON struct {
+	Categories []categoryJSON `json:"categories,omitempty"`
+	Codes      []codeJSON     `json:"codes"`
+}
+
+type categoryJSON struct {
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+	Name string `json:"name"`
+}
+
+type codeJSON struct {
+	Code        int    `json:"code"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Message     string `json:"message,omitempty"`
+	HTTPStatus  int    `json:"http_status,omitempty"`
+	ExitCode    int    `json:"exit_code,omitempty"`
+	Retryable   *bool  `json:"retryable,omitempty"`
+	Severity    Level  `json:"severity,omitempty"`
+	HelpURL     string `json:"help_url,omitempty"`
+
+	Deprecated        bool   `json:"deprecated,omitempty"`
+	ReplacedBy        int    `json:"replaced_by,omitempty"`
+	DeprecationReason string `json:"deprecation_reason,omitempty"`
+}
+
+// MarshalJSON encodes everything registered in the registry as JSON:
+//
+//	{
+//	  "categories": [{"min": 4000, "max": 4999, "name": "billing"}],
+//	  "codes": [{"code": 4012, "name": "ErrInvoiceMissing", "http_status": 404}]
+//	}
+//
+// The codes are sorted by code.
+func (r *Registry) MarshalJSON() ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	j := registryJSON{Codes: []codeJSON{}}
+	for _, c := range r.categories {
+		j.Categories = append(j.Categories, categoryJSON{Min: c.min, Max: c.max, Name: c.name})
+	}
+	for _, c := range r.codes() {
+		info, _ := r.lookup(c)
+		cj := codeJSON{
+			Code:        c,
+			Name:        info.Name,
+			Description: info.Description,
+			Message:     info.Message,
+			HTTPStatus:  info.HTTPStatus,
+			ExitCode:    info.ExitCode,
+			Severity:    info.Severity,
+			HelpURL:     info.HelpURL,
+
+			Deprecated:        info.Deprecated,
+			ReplacedBy:        info.ReplacedBy,
+			DeprecationReason: info.DeprecationReason,
+		}
+		if rt, ok := r.retry[c]; ok {
+			cj.Retryable = &rt
+		}
+		j.Codes = append(j.Codes, cj)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON registers everything in the JSON, as encoded by MarshalJSON.
+//
+// This adds to the existing registrations, replacing any registrations for the
+// same code.
+func (r *Registry) UnmarshalJSON(data []byte) error {
+	var j registryJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	for _, c := range j.Categories {
+		if c.Min > c.Max {
+			return fmt.Errorf("guru.Registry.UnmarshalJSON: category %q: min %d is larger than max %d",
+				c.Name, c.Min, c.Max)
+		}
+	}
+
+	for _, c := range j.Categories {
+		r.RegisterCategory(c.Min, c.Max, c.Name)
+	}
+	for _, c := range j.Codes {
+		if c.Name != "" || c.Description != "" {
+			r.Register(c.Code, c.Name, c.Description)
+		}
+		if c.Message != "" {
+			r.RegisterMessage(c.Code, c.Message)
+		}
+		if c.HTTPStatus != 0 {
+			r.RegisterHTTPStatus(c.Code, c.HTTPStatus)
+		}
+		if c.ExitCode != 0 {
+			r.RegisterExitCode(c.Code, c.ExitCode)
+		}
+		if c.Retryable != nil {
+			r.RegisterRetryable(c.Code, *c.Retryable)
+		}
+		if c.Severity != 0 {
+			r.RegisterSeverity(c.Code, c.Severity)
+		}
+		if c.HelpURL != "" {
+			r.RegisterHelpURL(c.Code, c.HelpURL)
+		}
+		if c.Deprecated {
+			r.Deprecate(c.Code, c.ReplacedBy, c.DeprecationReason)
+		}
+	}
+	return nil
+}
+
+// ExportFormat is a format for Registry.Export and Registry.Import.
+type ExportFormat string
+
+// Formats for Export and Import.
+const (
+	FormatJSON ExportFormat = "json"
+	FormatYAML ExportFormat = "yaml"
+)
+
+// Export writes everything registered in the registry to w, so that it can be
+// shared with other programs and loaded with Import.
+//
+// The JSON format is the same as MarshalJSON, but indented. The YAML format
+// has the same structure:
+//
+//	categories:
+//	  - min: 4000
+//	    max: 4999
+//	    name: billing
+//	codes:
+//	  - code: 4012
+//	    name: ErrInvoiceMissing
+//	    http_status: 404
+func (r *Registry) Export(w io.Writer, format ExportFormat) error {
+	j, err := r.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	switch format {
+	case FormatJSON:
+		var b bytes.Buffer
+		if err := json.Indent(&b, j, "", "  "); err != nil {
+			return err
+		}
+		out = append(b.Bytes(), '\n')
+	case FormatYAML:
+		var reg registryJSON
+		if err := json.Unmarshal(j, &reg); err != nil {
+			return err
+		}
+		out = toYAML(reg)
+	default:
+		return fmt.Errorf("guru.Registry.Export: unknown format %q", format)
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Import registers everything in rd, as written by Export.
+//
+// This adds to the existing registrations, replacing any registrations for the
+// same code. Only the subset of YAML that's needed for the structure
+// documented in Export is supported: block mappings and sequences, plain and
+// quoted scalars, block scalars ("|" and ">"), and comments.
+func (r *Registry) Import(rd io.Reader, format ExportFormat) error {
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatJSON:
+		return r.UnmarshalJSON(data)
+	case FormatYAML:
+		reg, err := fromYAML(data)
+		if err != nil {
+			return fmt.Errorf("guru.Registry.Import: %w", err)
+		}
+		j, err := json.Marshal(reg)
+		if err != nil {
+			return err
+		}
+		return r.UnmarshalJSON(j)
+	default:
+		return fmt.Errorf("guru.Registry.Import: unknown format %q", format)
+	}
+}
@@ -0,0 +1,223 @@
+package guru
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistryJSON(t *testing.T) {
+	var r Registry
+	r.RegisterCategory(4000, 4999, "billing")
+	r.Register(4012, "ErrInvoiceMissing", "The invoice doesn't exist.")
+	r.RegisterHTTPStatus(4012, 404)
+	r.RegisterHelpURL(4012, "https://example.com/errors/4012")
+	r.RegisterExitCode(5, 3)
+	r.RegisterRetryable(5, false)
+
+	j, err := json.Marshal(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"categories":[{"min":4000,"max":4999,"name":"billing"}],"codes":[` +
+		`{"code":5,"exit_code":3,"retryable":false},` +
+		`{"code":4012,"name":"ErrInvoiceMissing","description":"The invoice doesn't exist.","http_status":404,"help_url":"https://example.com/errors/4012"}]}`
+	if string(j) != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", j, want)
+	}
+
+	var r2 Registry
+	if err := json.Unmarshal(j, &r2); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r2.All(), r.All()) {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", r2.All(), r.All())
+	}
+	if _, ok := r2.retry[5]; !ok {
+		t.Error("retryable not set")
+	}
+
+	var empty Registry
+	j, err = json.Marshal(&empty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(j) != `{"codes":[]}` {
+		t.Errorf("empty: %s", j)
+	}
+
+	err = json.Unmarshal([]byte(`{"categories":[{"min":2,"max":1,"name":"x"}]}`), &empty)
+	if err == nil {
+		t.Error("err is nil")
+	}
+}
+
+func TestExport(t *testing.T) {
+	newReg := func() *Registry {
+		r := &Registry{}
+		r.RegisterCategory(4000, 4999, "billing")
+		r.Register(4012, "ErrInvoiceMissing", "The invoice doesn't exist.")
+		r.RegisterHTTPStatus(4012, 404)
+		r.RegisterHelpURL(4012, "https://example.com/errors/4012")
+		r.Register(5, "true", "line 1\nline 2: #x")
+		r.RegisterRetryable(5, true)
+		r.Deprecate(5, 6, "reworded")
+		r.RegisterMessage(4012, "no such invoice: %d")
+		r.RegisterSeverity(4012, LevelWarn)
+		return r
+	}
+
+	tests := []struct {
+		format ExportFormat
+		want   string
+	}{
+		{FormatJSON, `{
+		  "categories": [
+		    {
+		      "min": 4000,
+		      "max": 4999,
+		      "name": "billing"
+		    }
+		  ],
+		  "codes": [
+		    {
+		      "code": 5,
+		      "name": "true",
+		      "description": "line 1\nline 2: #x",
+		      "retryable": true,
+		      "deprecated": true,
+		      "replaced_by": 6,
+		      "deprecation_reason": "reworded"
+		    },
+		    {
+		      "code": 4012,
+		      "name": "ErrInvoiceMissing",
+		      "description": "The invoice doesn't exist.",
+		      "message": "no such invoice: %d",
+		      "http_status": 404,
+		      "severity": "warn",
+		      "help_url": "https://example.com/errors/4012"
+		    }
+		  ]
+		}
+		`},
+		{FormatYAML, `categories:
+		  - min: 4000
+		    max: 4999
+		    name: billing
+		codes:
+		  - code: 5
+		    name: "true"
+		    description: "line 1\nline 2: #x"
+		    retryable: true
+		    deprecated: true
+		    replaced_by: 6
+		    deprecation_reason: reworded
+		  - code: 4012
+		    name: ErrInvoiceMissing
+		    description: The invoice doesn't exist.
+		    message: "no such invoice: %d"
+		    http_status: 404
+		    severity: warn
+		    help_url: https://example.com/errors/4012
+		`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			want := strings.ReplaceAll(tt.want, "\t", "")
+
+			b := new(bytes.Buffer)
+			if err := newReg().Export(b, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != want {
+				t.Errorf("\nout:\n%s\nwant:\n%s", b, want)
+			}
+
+			var r Registry
+			if err := r.Import(b, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(r.All(), newReg().All()) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", r.All(), newReg().All())
+			}
+		})
+	}
+
+	if err := newReg().Export(new(bytes.Buffer), "xml"); err == nil {
+		t.Error("err is nil for unknown format")
+	}
+	if err := newReg().Import(strings.NewReader(""), "xml"); err == nil {
+		t.Error("err is nil for unknown format")
+	}
+}
+
+func TestImportYAML(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []Info
+		wantErr string
+	}{
+		{"", []Info{}, ""},
+		{"codes: []", []Info{}, ""},
+		{`
+			---
+			# Comment
+			codes:  # Comment
+			- code: 1 # Comment
+			  name: 'It''s # not a comment'
+			  description: |
+			    Line 1
+			      Line 2
+
+			    Line 3
+
+			- code: 2
+			  description: >-
+			    Folded
+			    text
+
+			    Para
+			  exit_code: 3
+			unknown:
+			  - code: 3
+			    name: x
+			`, []Info{
+			{Code: 1, Name: "It's # not a comment", Description: "Line 1\n  Line 2\n\nLine 3\n"},
+			{Code: 2, Description: "Folded text\nPara", ExitCode: 3},
+		}, ""},
+		{"codes:\n  -\n    code: 1\n    name: \"x\" # c\n", []Info{{Code: 1, Name: "x"}}, ""},
+
+		{"codes: x", nil, `line 1: "codes" must be a list`},
+		{"codes:\n  - code: x", nil, `line 2: code: not a number: "x"`},
+		{"codes:\n  - code: 1\n    retryable: x", nil, `line 3: retryable: not a boolean: "x"`},
+		{"codes:\n  code: 1", nil, "line 2: not in a list item"},
+		{"codes:\n\t- code: 1", nil, "line 2: tabs can't be used for indentation"},
+		{"codes:\n  - code: 1\n    name: \"x", nil, `line 3: unterminated string: "x`},
+		{"codes:\n  - code: 1\n    name: 'x' y", nil, `line 3: text after quoted string: "y"`},
+		{"codes:\n  - code 1", nil, `line 2: not a key: "code 1"`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			in := strings.ReplaceAll(tt.in, "\n\t\t\t", "\n")
+
+			var r Registry
+			err := r.Import(strings.NewReader(in), FormatYAML)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != "guru.Registry.Import: "+tt.wantErr {
+					t.Fatalf("wrong err: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out := r.All(); !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package guru
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	extractMu  sync.Mutex
+	extractors atomic.Pointer[[]func(error) (int, bool)]
+)
+
+// RegisterExtractor adds a function to get a code from errors that don't
+// implement Code() int, such as errors from other libraries:
+//
+//	guru.RegisterExtractor(func(err error) (int, bool) {
+//		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+//			return 409, true
+//		}
+//		return 0, false
+//	})
+//
+// The function is called for every error in the chain by Code, Codes, Has,
+// etc. and should return false if err doesn't have a code; it shouldn't unwrap
+// err. Extractors are tried in the order they were added.
+func RegisterExtractor(fn func(error) (int, bool)) {
+	extractMu.Lock()
+	defer extractMu.Unlock()
+	var cur []func(error) (int, bool)
+	if e := extractors.Load(); e != nil {
+		cur = *e
+	}
+	n := append(append(make([]func(error) (int, bool), 0, len(cur)+1), cur...), fn)
+	extractors.Store(&n)
+}
+
+// codeOf gets the code of err, without unwrapping it. A Group only has a code
+// if one of the errors in it has.
+func codeOf(err error) (int, bool) {
+	if g, ok := err.(*Group); ok {
+		for _, e := range g.errs {
+			if hasCode(e) {
+				return g.Code(), true
+			}
+		}
+		return 0, false
+	}
+	if c, ok := err.(coder); ok {
+		return c.Code(), true
+	}
+	if e := extractors.Load(); e != nil {
+		for _, fn := range *e {
+			if c, ok := fn(err); ok {
+				return c, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,63 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type pqError struct{ code string }
+
+func (e *pqError) Error() string { return "pq: " + e.code }
+
+func TestRegisterExtractor(t *testing.T) {
+	old := extractors.Load()
+	t.Cleanup(func() { extractors.Store(old) })
+
+	RegisterExtractor(func(err error) (int, bool) {
+		if pq, ok := err.(*pqError); ok && pq.code == "23505" {
+			return 409, true
+		}
+		return 0, false
+	})
+	RegisterExtractor(func(err error) (int, bool) {
+		if _, ok := err.(*pqError); ok {
+			return 500, true
+		}
+		return 0, false
+	})
+
+	tests := []struct {
+		in        error
+		wantCode  int
+		wantCodes []int
+		wantHTTP  int
+	}{
+		{errors.New("x"), 0, nil, 500},
+		{&pqError{"23505"}, 409, []int{409}, 409},
+		{&pqError{"42P01"}, 500, []int{500}, 500},
+		{fmt.Errorf("insert: %w", &pqError{"23505"}), 409, []int{409}, 409},
+		{Wrap(42, &pqError{"23505"}, "insert"), 42, []int{42, 409}, 500},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if c := Code(tt.in); c != tt.wantCode {
+				t.Errorf("\nout:  %d\nwant: %d\n", c, tt.wantCode)
+			}
+			if c := Codes(tt.in); !reflect.DeepEqual(c, tt.wantCodes) {
+				t.Errorf("codes\nout:  %v\nwant: %v\n", c, tt.wantCodes)
+			}
+			if s := HTTPStatus(tt.in); s != tt.wantHTTP {
+				t.Errorf("HTTPStatus\nout:  %d\nwant: %d\n", s, tt.wantHTTP)
+			}
+			if tt.wantCode != 0 && (!HasCode(tt.in) || !Has(tt.in, tt.wantCode) || !Is(tt.in, tt.wantCode)) {
+				t.Error("HasCode, Has, or Is false")
+			}
+		})
+	}
+
+	if j, _ := MarshalJSON(&pqError{"23505"}); string(j) != `{"code":409,"message":"pq: 23505"}` {
+		t.Errorf("JSON: %s", j)
+	}
+}
@@ -0,0 +1,120 @@
+package guru
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type withFields struct {
+	error
+	fields map[string]interface{}
+
+	// For templates: the fields were already added to the errors it wraps
+	// (for copies from withOuterFields), and the cached copy.
+	applied bool
+	in      *fieldsMemo
+}
+
+// fieldsMemo caches the copy from withOuterFields. A nil fieldsMemo doesn't
+// cache anything.
+type fieldsMemo struct {
+	once sync.Once
+	err  error
+}
+
+// inner gets the wrapped error, with the fields added for templates.
+func (e *withFields) inner() error {
+	if !hasTemplates.Load() || e.applied {
+		return e.error
+	}
+	if e.in == nil {
+		return withOuterFields(e.error, e.fields, false)
+	}
+	e.in.once.Do(func() { e.in.err = withOuterFields(e.error, e.fields, true) })
+	return e.in.err
+}
+
+func (e *withFields) Error() string { return e.inner().Error() }
+func (e *withFields) Unwrap() error { return e.error }
+func (e withFields) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.inner()) {
+		keys := make([]string, 0, len(e.fields))
+		for k := range e.fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprint(s, "\nfields:")
+		for _, k := range keys {
+			fmt.Fprintf(s, " %s=%v", k, e.fields[k])
+		}
+	}
+}
+
+// WithFields annotates err with the key/value pairs in fields, for example to
+// add the request or user ID. It will return nil if err is nil.
+//
+// The fields don't change the error message, but are printed with the %+v
+// verb. The fields are copied and errors are never modified after they're
+// created, so it's safe to annotate the same error from multiple goroutines.
+func WithFields(err error, fields map[string]interface{}) error {
+	if err == nil {
+		return nil
+	}
+	f := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		f[k] = v
+	}
+	return newFields(err, f)
+}
+
+// WithField is like WithFields, but for a single key/value pair.
+//
+// If err was created with WithFields or WithField then the fields are copied
+// to a new error along with the new field, instead of adding another error to
+// the chain; err itself isn't modified. It will return nil if err is nil.
+func WithField(err error, key string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	wf, ok := err.(*withFields)
+	if !ok {
+		return newFields(err, map[string]interface{}{key: value})
+	}
+	f := make(map[string]interface{}, len(wf.fields)+1)
+	for k, v := range wf.fields {
+		f[k] = v
+	}
+	f[key] = value
+	return newFields(wf.error, f)
+}
+
+func newFields(err error, f map[string]interface{}) *withFields {
+	wf := &withFields{error: err, fields: f}
+	if hasTemplates.Load() {
+		wf.in = new(fieldsMemo)
+	}
+	return wf
+}
+
+// Fields returns the fields of all errors in the chain merged together; if a
+// key is set more than once then the highest-level error wins. It will return
+// nil if none of the errors have any fields.
+func Fields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	walk(err, func(err error) bool {
+		if wf, ok := err.(*withFields); ok {
+			if fields == nil {
+				fields = make(map[string]interface{}, len(wf.fields))
+			}
+			for k, v := range wf.fields {
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+		}
+		return true
+	})
+	return fields
+}
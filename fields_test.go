@@ -0,0 +1,114 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var _ error = &withFields{}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		in   error
+		want map[string]interface{}
+	}{
+		{nil, nil},
+		{errors.New("foo"), nil},
+		{WithFields(errors.New("foo"), nil), map[string]interface{}{}},
+		{WithFields(New(1, "foo"), map[string]interface{}{"a": 1}), map[string]interface{}{"a": 1}},
+		{
+			WithFields(Wrap(2, WithFields(New(1, "foo"), map[string]interface{}{"a": 1, "b": 2}), "bar"),
+				map[string]interface{}{"a": "x", "c": 3}),
+			map[string]interface{}{"a": "x", "b": 2, "c": 3},
+		},
+		{
+			errors.Join(
+				WithFields(errors.New("foo"), map[string]interface{}{"a": 1}),
+				WithFields(errors.New("foo"), map[string]interface{}{"a": 2, "b": 2})),
+			map[string]interface{}{"a": 1, "b": 2},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Fields(tt.in)
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	if WithFields(nil, map[string]interface{}{"a": 1}) != nil {
+		t.Error("not nil")
+	}
+}
+
+func TestFormatFields(t *testing.T) {
+	f := map[string]interface{}{"user": 42, "req": "abc"}
+	err := WithFields(New(1, "oh noes"), f)
+	f["user"] = 666
+
+	tests := []struct {
+		fmt  string
+		want string
+	}{
+		{"%v", "error 1: oh noes"},
+		{"%+v", "error 1: oh noes\nfields: req=abc user=42"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := fmt.Sprintf(tt.fmt, err)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithField(t *testing.T) {
+	base := WithFields(New(1, "x"), map[string]interface{}{"a": 1})
+
+	tests := []struct {
+		in       error
+		want     map[string]interface{}
+		wantPlus string
+	}{
+		{WithField(nil, "a", 1), nil, "<nil>"},
+		{WithField(New(1, "x"), "a", 1), map[string]interface{}{"a": 1}, "error 1: x\nfields: a=1"},
+		{WithField(base, "b", 2), map[string]interface{}{"a": 1, "b": 2}, "error 1: x\nfields: a=1 b=2"},
+		{WithField(base, "a", 2), map[string]interface{}{"a": 2}, "error 1: x\nfields: a=2"},
+		{WithField(Wrap(2, base, "y"), "b", 2), map[string]interface{}{"a": 1, "b": 2},
+			"error 2: y\nerror 1: x\nfields: a=1\nfields: b=2"},
+		{base, map[string]interface{}{"a": 1}, "error 1: x\nfields: a=1"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := Fields(tt.in); !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlus)
+			}
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := WithField(base, "b", i)
+			if Fields(err)["b"] != i {
+				t.Errorf("wrong field: %v", Fields(err))
+			}
+		}(i)
+	}
+	wg.Wait()
+	if f := Fields(base); len(f) != 1 {
+		t.Errorf("base modified: %v", f)
+	}
+}
@@ -0,0 +1,168 @@
+package guru
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FileRecorder appends every event as an ErrorRecord in JSON to a file, rotating
+// it when it grows too large. This gives programs without a log collector (such
+// as CLI tools and edge devices) a local trail of errors; use QueryErrors to
+// read it.
+type FileRecorder struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	fp      *os.File
+	size    int64
+	closed  bool
+}
+
+// NewFileRecorder creates a new recorder that appends to the file at path,
+// creating it if it doesn't exist.
+//
+// The file is rotated when it grows larger than maxSize bytes (10M if it's 0):
+// the current file is renamed to path.1, path.1 to path.2, etc., and only keep
+// old files are kept.
+func NewFileRecorder(path string, maxSize int64, keep int) (*FileRecorder, error) {
+	if maxSize <= 0 {
+		maxSize = 10 << 20
+	}
+	r := &FileRecorder{path: path, maxSize: maxSize, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *FileRecorder) open() error {
+	fp, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("guru.FileRecorder: %w", err)
+	}
+	st, err := fp.Stat()
+	if err != nil {
+		fp.Close()
+		return fmt.Errorf("guru.FileRecorder: %w", err)
+	}
+	r.fp, r.size = fp, st.Size()
+	return nil
+}
+
+func (r *FileRecorder) rotate() error {
+	r.fp.Close()
+	r.fp = nil
+	if r.keep < 1 {
+		return os.Remove(r.path)
+	}
+	os.Remove(r.path + "." + strconv.Itoa(r.keep))
+	for i := r.keep - 1; i >= 1; i-- {
+		err := os.Rename(r.path+"."+strconv.Itoa(i), r.path+"."+strconv.Itoa(i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(r.path, r.path+".1")
+}
+
+// Record writes ev to the file. Errors are ignored, as there's nowhere to
+// report them.
+func (r *FileRecorder) Record(ev Event) {
+	j, err := json.Marshal(NewErrorRecord(ev))
+	if err != nil {
+		return
+	}
+	j = append(j, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fp != nil && r.size > 0 && r.size+int64(len(j)) > r.maxSize {
+		r.rotate()
+	}
+	if r.fp == nil {
+		if r.closed || r.open() != nil {
+			return
+		}
+	}
+	n, _ := r.fp.Write(j)
+	r.size += int64(n)
+}
+
+// Close closes the file; events recorded after Close are ignored.
+func (r *FileRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	if r.fp == nil {
+		return nil
+	}
+	err := r.fp.Close()
+	r.fp = nil
+	return err
+}
+
+// QueryErrors reads the records written by a FileRecorder at path, including
+// the rotated files, oldest first.
+//
+// Only records at or after since are returned, and only records with one of
+// codes if any are given. Lines that aren't valid records are skipped, for
+// example the last line if the program crashed while writing it.
+func QueryErrors(path string, since time.Time, codes ...int) ([]ErrorRecord, error) {
+	files := []string{path}
+	for i := 1; ; i++ {
+		p := path + "." + strconv.Itoa(i)
+		if _, err := os.Stat(p); err != nil {
+			break
+		}
+		files = append([]string{p}, files...)
+	}
+
+	var recs []ErrorRecord
+	for _, p := range files {
+		fp, err := os.Open(p)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("guru.QueryErrors: %w", err)
+		}
+		scan := bufio.NewScanner(fp)
+		scan.Buffer(nil, 1<<20)
+		for scan.Scan() {
+			var rec ErrorRecord
+			if json.Unmarshal(scan.Bytes(), &rec) != nil {
+				continue
+			}
+			if rec.Time.Before(since) || !matchCode(rec.Code, codes) {
+				continue
+			}
+			recs = append(recs, rec)
+		}
+		err = scan.Err()
+		fp.Close()
+		if err != nil {
+			return nil, fmt.Errorf("guru.QueryErrors: %w", err)
+		}
+	}
+	return recs, nil
+}
+
+func matchCode(code int, codes []int) bool {
+	if len(codes) == 0 {
+		return true
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
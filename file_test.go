@@ -0,0 +1,75 @@
+package guru
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileRecorder(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "errors.log")
+		t0   = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		i    = 0
+	)
+	now = func() time.Time { i++; return t0.Add(time.Duration(i) * time.Minute) }
+	defer func() { now = time.Now }()
+
+	r, err := NewFileRecorder(path, 150, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	SetRecorder(r)
+	defer SetRecorder(nil)
+
+	for c := 1; c <= 6; c++ {
+		New(c%3, "err")
+	}
+	r.Close()
+	New(9, "after close")
+
+	// Every record is >75 bytes, so there is one per file.
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		if _, err := os.Stat(p); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("too many files kept")
+	}
+
+	codes := func(recs []ErrorRecord) []int {
+		var c []int
+		for _, r := range recs {
+			c = append(c, r.Code)
+		}
+		return c
+	}
+	tests := []struct {
+		since time.Time
+		codes []int
+		want  []int
+	}{
+		{time.Time{}, nil, []int{1, 2, 0}},
+		{t0.Add(5 * time.Minute), nil, []int{2, 0}},
+		{time.Time{}, []int{0, 1}, []int{1, 0}},
+		{t0.Add(time.Hour), nil, nil},
+	}
+	for _, tt := range tests {
+		recs, err := QueryErrors(path, tt.since, tt.codes...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := codes(recs); !reflect.DeepEqual(out, tt.want) {
+			t.Errorf("\nout:  %v\nwant: %v\n", out, tt.want)
+		}
+	}
+
+	recs, err := QueryErrors(filepath.Join(t.TempDir(), "nonexistent"), time.Time{})
+	if err != nil || recs != nil {
+		t.Error(recs, err)
+	}
+}
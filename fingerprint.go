@@ -0,0 +1,67 @@
+package guru
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Variable parts of messages, in the order they're replaced.
+var fingerprintVars = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|` + "`[^`]*`"), "<str>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b(?:[0-9a-f]*[0-9][0-9a-f]*[a-f]|[0-9a-f]*[a-f][0-9a-f]*[0-9])[0-9a-f]*\b`), "<hex>"},
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?(?:[a-zµ]+)?\b`), "<n>"}, // Includes units: 5s, 1.5ms
+}
+
+// Fingerprint gets a fingerprint for err, so that identical failures can be
+// grouped together.
+//
+// The fingerprint is a hash of the codes and messages of all errors in the
+// chain, where the variable parts of messages (numbers, durations, quoted
+// strings, UUIDs, hexadecimal IDs, and timestamps) are ignored; so "user 42 not
+// found" and "user 666 not found" have the same fingerprint. Fields and stack
+// traces are not used. It will return an empty string if err is nil.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	b := new(strings.Builder)
+	fingerprint(b, toJSON(err))
+	h := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(h[:8])
+}
+
+func fingerprint(b *strings.Builder, j *jsonError) {
+	for ; j != nil; j = j.Wrapped {
+		if j.Code != nil {
+			b.WriteString(strconv.Itoa(int(*j.Code)))
+			if j.Subcode != 0 {
+				b.WriteString("." + strconv.Itoa(j.Subcode))
+			}
+		}
+		b.WriteString("\x00" + normalizeMessage(j.Message) + "\x00")
+		if len(j.Errors) > 0 {
+			b.WriteByte('[')
+			for _, e := range j.Errors {
+				fingerprint(b, e)
+				b.WriteByte('\x01')
+			}
+			b.WriteByte(']')
+		}
+	}
+}
+
+// normalizeMessage replaces the variable parts of msg with placeholders.
+func normalizeMessage(msg string) string {
+	for _, v := range fingerprintVars {
+		msg = v.re.ReplaceAllString(msg, v.repl)
+	}
+	return msg
+}
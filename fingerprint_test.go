@@ -0,0 +1,73 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		a, b error
+		same bool
+	}{
+		{New(1, "user 42 not found"), New(1, "user 666 not found"), true},
+		{New(1, "user 42 not found"), New(2, "user 42 not found"), false},
+		{New(1, "user 42 not found"), New(1, "group 42 not found"), false},
+		{NewSub(1, 1, "x"), NewSub(1, 2, "x"), false},
+		{Wrap(2, New(1, "x"), "y"), Wrap(2, New(1, "x"), "z"), false},
+		{Wrap(2, New(1, "x"), "y"), WithCode(2, New(1, "x: y")), false},
+		{
+			Errorf(1, `open "/tmp/a": at 2024-01-02T15:04:05Z, id 550e8400-e29b-41d4-a716-446655440000, tx deadbeef01`),
+			Errorf(1, `open "/tmp/b/c": at 2025-11-12 05:04:05.123+02:00, id 6ba7b810-9dad-11d1-80b4-00c04fd430c8, tx 0xff`),
+			true,
+		},
+		{WithFields(New(1, "x"), map[string]interface{}{"a": 1}), NewStack(1, "x"), true},
+		{fmt.Errorf("ctx %d: %w", 1, New(1, "x")), fmt.Errorf("ctx %d: %w", 2, New(1, "x")), true},
+		{errors.Join(New(1, "a"), New(2, "b")), errors.Join(New(2, "b"), New(1, "a")), false},
+		{errors.Join(New(1, "a 1"), New(2, "b")), errors.Join(New(1, "a 2"), New(2, "b")), true},
+		{errors.New("a"), errors.New("b"), false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			a, b := Fingerprint(tt.a), Fingerprint(tt.b)
+			if len(a) != 16 || len(b) != 16 {
+				t.Fatalf("wrong length: %q %q", a, b)
+			}
+			if (a == b) != tt.same {
+				t.Errorf("same=%t: %s %s", a == b, a, b)
+			}
+		})
+	}
+
+	if f := Fingerprint(nil); f != "" {
+		t.Errorf("nil: %q", f)
+	}
+	if a, b := Fingerprint(New(1, "x")), Fingerprint(New(1, "x")); a != b {
+		t.Error("not stable")
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"user 42 not found", "user <n> not found"},
+		{"took 1.5s", "took <n>"},
+		{"took 1.5 seconds, 42ms", "took <n> seconds, <n>"},
+		{"http2: no", "http2: no"},
+		{"key 'a\\'b' and `c`", "key <str> and <str>"},
+		{"at 10.0.0.1:80", "at <n>.<n>:<n>"},
+		{`open "/tmp/a": at 2024-01-02T15:04:05Z, id 550e8400-e29b-41d4-a716-446655440000, tx deadbeef01`,
+			"open <str>: at <time>, id <uuid>, tx <hex>"},
+		{`at 2025-11-12 05:04:05.123+02:00, tx 0xff`, "at <time>, tx <hex>"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := normalizeMessage(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
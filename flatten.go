@@ -0,0 +1,118 @@
+package guru
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Entry is one link in an error chain, as returned by Flatten.
+type Entry struct {
+	Code    int                    // Error code, or 0 if there is none.
+	HasCode bool                   // Error has a code; Code can be 0.
+	Subcode int                    // Subcode, or 0 if there is none.
+	Message string                 // Message added by this error, without the messages of errors it wraps.
+	Ops     []string               // Operations added with WithOp, outermost first.
+	Fields  map[string]interface{} // Fields added with WithFields.
+	Frame   runtime.Frame          // Location of the recorded call stack, if any.
+	Depth   int                    // Nesting level of errors that wrap more than one error.
+}
+
+// Flatten gets all errors in the chain of err as a list of entries, from the
+// outermost error to the innermost. This is useful for custom renderers.
+//
+// Annotations that don't change the message (fields, operations, stack traces)
+// are added to the entry of the error they annotate. Errors that wrap more
+// than one error (such as those created with errors.Join) are followed by the
+// entries of the errors they wrap, with Depth increased by one. It will return
+// nil if err is nil.
+func Flatten(err error) []Entry {
+	var entries []Entry
+	flatten(err, 0, &entries)
+	return entries
+}
+
+func flatten(err error, depth int, entries *[]Entry) {
+	cur := Entry{Depth: depth}
+	add := func() {
+		*entries = append(*entries, cur)
+		cur = Entry{Depth: depth}
+	}
+
+	for err != nil {
+		switch e := err.(type) {
+		case *withFields:
+			if cur.Fields == nil {
+				cur.Fields = make(map[string]interface{}, len(e.fields))
+			}
+			for k, v := range e.fields {
+				if _, ok := cur.Fields[k]; !ok {
+					cur.Fields[k] = v
+				}
+			}
+			err = e.inner()
+		case *withStack:
+			if cur.Frame.PC == 0 && len(e.stack) > 0 {
+				cur.Frame, _ = runtime.CallersFrames(e.stack).Next()
+			}
+			err = e.error
+		case *withOp:
+			cur.Ops = append(cur.Ops, e.op)
+			err = e.error
+		case *withRetry, *withTimeout, *withPublic, *withSeverity, *withRequestID, *withDetail,
+			*withRelated, *stamped:
+			err = errors.Unwrap(err)
+
+		case constError:
+			cur.Code, cur.HasCode, cur.Message = e.code, true, e.msg
+			add()
+			return
+		case *withCode:
+			cur.Code, cur.HasCode, cur.Subcode = e.code, true, e.sub
+			if isLeaf(e.error) {
+				cur.Message = e.Error()
+				add()
+				return
+			}
+			add()
+			err = e.error
+		case *wrapped:
+			cur.Code, cur.HasCode, cur.Message = e.code, true, e.text()
+			add()
+			err = e.error
+		case *withNote:
+			cur.Message = e.msg
+			add()
+			err = e.error
+
+		default:
+			// The code of a Group is always the code of one of its errors.
+			if _, ok := err.(*Group); !ok {
+				if c, ok := codeOf(err); ok {
+					cur.Code, cur.HasCode = c, true
+				}
+			}
+			switch u := err.(type) {
+			case interface{ Unwrap() error }:
+				cur.Message = ownMessage(err, u.Unwrap())
+				add()
+				err = u.Unwrap()
+			case interface{ Unwrap() []error }:
+				errs := u.Unwrap()
+				if _, ok := err.(*Group); !ok {
+					if msg := err.Error(); msg != errors.Join(errs...).Error() {
+						cur.Message = msg
+					}
+				}
+				add()
+				for _, e := range errs {
+					flatten(e, depth+1, entries)
+				}
+				return
+			default:
+				cur.Message = err.Error()
+				add()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		in   error
+		want []Entry
+	}{
+		{nil, nil},
+		{errors.New("x"), []Entry{{Message: "x"}}},
+		{NewSub(1, 2, "x"), []Entry{{Code: 1, HasCode: true, Subcode: 2, Message: "x"}}},
+		{Const(1, "x"), []Entry{{Code: 1, HasCode: true, Message: "x"}}},
+		{New(0, "x"), []Entry{{Code: 0, HasCode: true, Message: "x"}}},
+		{WithOp(Wrap(2, WithFields(WithOp(New(1, "x"), "inner"), map[string]interface{}{"a": 1}), "y"), "outer"),
+			[]Entry{
+				{Code: 2, HasCode: true, Message: "y", Ops: []string{"outer"}},
+				{Code: 1, HasCode: true, Message: "x", Ops: []string{"inner"}, Fields: map[string]interface{}{"a": 1}},
+			}},
+		{Note(WithCode(3, fmt.Errorf("ctx: %w", MarkRetryable(errors.New("x")))), "note"),
+			[]Entry{{Message: "note"}, {Code: 3, HasCode: true}, {Message: "ctx"}, {Message: "x"}}},
+		{WithCode(2, errors.Join(New(1, "a"), fmt.Errorf("b: %w", errors.New("c")))),
+			[]Entry{{Code: 2, HasCode: true}, {}, {Depth: 1, Code: 1, HasCode: true, Message: "a"}, {Depth: 1, Message: "b"}, {Depth: 1, Message: "c"}}},
+		{Append(nil, New(1, "a"), New(2, "b")),
+			[]Entry{{}, {Depth: 1, Code: 1, HasCode: true, Message: "a"}, {Depth: 1, Code: 2, HasCode: true, Message: "b"}}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Flatten(tt.in)
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	out := Flatten(Wrap(2, NewStack(1, "x"), "y"))
+	if len(out) != 2 || out[0].Frame.PC != 0 || out[1].Frame.Function != "zgo.at/guru.TestFlatten" {
+		t.Errorf("frame: %#v", out)
+	}
+}
@@ -0,0 +1,91 @@
+package guru
+
+import (
+	"fmt"
+)
+
+// formatCode formats an error with a code, for use in the Format() method of
+// errors with a code. msg is the message added by the error, if any, and err is
+// the error it wraps. The name is looked up in reg, or DefaultRegistry if it's
+// nil.
+//
+// The verbs are:
+//
+//	%s    The message without the code.
+//	%q    Quoted message without the code.
+//	%v    The message with the code.
+//	%+v   Every error in the chain on its own line, with stack traces and
+//	      fields (if any). The code is followed by the name from the
+//	      registry, if there is one. Errors that wrap more than one error
+//	      are printed as a tree, like Tree does.
+func formatCode(s fmt.State, verb rune, reg *Registry, code interface{}, sub int, msg string, err error) {
+	c := fmt.Sprint(code)
+	if ic, ok := code.(int); ok {
+		c = fmtCode(ic)
+	}
+	if sub != 0 {
+		c += "." + fmtCode(sub)
+	}
+
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		if reg == nil {
+			reg = DefaultRegistry
+		}
+		if ic, ok := code.(int); ok {
+			if name := reg.Name(ic); name != "" {
+				c += " (" + name + ")"
+			}
+		}
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			if msg != "" {
+				fmt.Fprintf(s, "error %s: %s", c, msg)
+			} else {
+				fmt.Fprintf(s, "error %s", c)
+			}
+			writeBranches(s, u.Unwrap(), true, plusV)
+			return
+		}
+		if msg != "" {
+			fmt.Fprintf(s, "error %s: %s\n%+v", c, msg, err)
+		} else {
+			fmt.Fprintf(s, "error %s: %+v", c, err)
+		}
+	case verb == 's':
+		fmt.Fprint(s, plain(msg, err))
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", plain(msg, err))
+	default:
+		fmt.Fprintf(s, "error %s: %v", c, err)
+		if msg != "" {
+			fmt.Fprintf(s, ": %v", msg)
+		}
+	}
+}
+
+// plain returns the message of msg and err, without any codes.
+func plain(msg string, err error) string {
+	p := fmt.Sprintf("%s", err)
+	if msg != "" {
+		p += ": " + msg
+	}
+	return p
+}
+
+// formatInner formats err, for use in the Format() method of errors that
+// annotate err without changing the message. It reports if the %+v verb was
+// used, in which case the caller should write its annotation.
+func formatInner(s fmt.State, verb rune, err error) bool {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%+v", err)
+		return true
+	case verb == 's':
+		fmt.Fprintf(s, "%s", err)
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", err)
+	default:
+		fmt.Fprintf(s, "%v", err)
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+)
+
+// coderT is like coder, but for codes of any comparable type.
+type coderT[C comparable] interface {
+	Code() C
+}
+
+type withCodeT[C comparable] struct {
+	error
+	code C
+}
+
+func (e *withCodeT[C]) Unwrap() error { return e.error }
+func (e *withCodeT[C]) Code() C       { return e.code }
+func (e withCodeT[C]) Format(s fmt.State, verb rune) {
+	formatCode(s, verb, nil, e.code, 0, "", e.error)
+}
+
+type wrappedT[C comparable] struct {
+	msg  string
+	code C
+	error
+}
+
+func (e *wrappedT[C]) Error() string { return e.msg }
+func (e *wrappedT[C]) Unwrap() error { return e.error }
+func (e *wrappedT[C]) Code() C       { return e.code }
+func (e wrappedT[C]) Format(s fmt.State, verb rune) {
+	formatCode(s, verb, nil, e.code, 0, e.msg, e.error)
+}
+
+// NewT is like New, but accepts a code of any comparable type, such as a
+// string or a typed constant.
+//
+// The code can be retrieved with CodeT; Code will only see it if C is int.
+func NewT[C comparable](code C, msg string) error {
+	return &withCodeT[C]{
+		error: errors.New(msg),
+		code:  code,
+	}
+}
+
+// ErrorfT is like Errorf, but accepts a code of any comparable type.
+func ErrorfT[C comparable](code C, format string, args ...interface{}) error {
+	return &withCodeT[C]{
+		error: fmt.Errorf(format, args...),
+		code:  code,
+	}
+}
+
+// WithCodeT is like WithCode, but accepts a code of any comparable type.
+func WithCodeT[C comparable](code C, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withCodeT[C]{
+		error: err,
+		code:  code,
+	}
+}
+
+// WrapT is like Wrap, but accepts a code of any comparable type.
+func WrapT[C comparable](code C, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &wrappedT[C]{
+		msg:   msg,
+		code:  code,
+		error: err,
+	}
+}
+
+// CodeT extracts the highest-level error code of type C from the error or the
+// errors it wraps. The second return value reports if a code was found.
+//
+// Codes of other types are skipped, so if the chain contains both string and
+// int codes then CodeT[string] and CodeT[int] will both find a code.
+func CodeT[C comparable](err error) (C, bool) {
+	var (
+		code  C
+		found bool
+	)
+	walk(err, func(err error) bool {
+		if sc, ok := err.(coderT[C]); ok {
+			code, found = sc.Code(), true
+		}
+		return !found
+	})
+	return code, found
+}
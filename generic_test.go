@@ -0,0 +1,76 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var _ error = &withCodeT[string]{}
+var _ coderT[string] = &withCodeT[string]{}
+var _ coder = &withCodeT[int]{}
+
+var _ error = &wrappedT[string]{}
+var _ coderT[string] = &wrappedT[string]{}
+
+type testStatus int
+
+func TestFormatT(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{NewT("billing.invoice_missing", "oh noes"), "error billing.invoice_missing: oh noes"},
+		{ErrorfT(testStatus(42), "oh %s", "noes"), "error 42: oh noes"},
+		{WithCodeT("x", errors.New("oh noes")), "error x: oh noes"},
+		{WrapT("x", errors.New("oh noes"), "ctx"), "error x: oh noes: ctx"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := fmt.Sprintf("%v", tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeT(t *testing.T) {
+	tests := []struct {
+		in        error
+		want      string
+		wantFound bool
+	}{
+		{nil, "", false},
+		{errors.New("foo"), "", false},
+		{New(42, "foo"), "", false},
+		{NewT("a", "foo"), "a", true},
+		{WrapT("b", NewT("a", "foo"), "bar"), "b", true},
+		{Wrap(42, NewT("a", "foo"), "bar"), "a", true},
+		{NewT(testStatus(1), "foo"), "", false},
+		{fmt.Errorf("%w", WithCodeT("a", errors.New("foo"))), "a", true},
+		{errors.Join(New(1, "x"), WithCodeT("a", errors.New("foo"))), "a", true},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, found := CodeT[string](tt.in)
+			if out != tt.want || found != tt.wantFound {
+				t.Errorf("\nout:  %#v %t\nwant: %#v %t\n", out, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+
+	t.Run("typed", func(t *testing.T) {
+		out, found := CodeT[testStatus](Wrap(42, NewT(testStatus(2), "foo"), "bar"))
+		if out != 2 || !found {
+			t.Errorf("%v %t", out, found)
+		}
+	})
+	t.Run("int", func(t *testing.T) {
+		if out := Code(NewT(42, "foo")); out != 42 {
+			t.Errorf("%v", out)
+		}
+	})
+}
@@ -1,4 +1,4 @@
 module zgo.at/guru
 
-go 1.13
+go 1.23
 
@@ -0,0 +1,266 @@
+package guru
+
+import (
+	"encoding/gob"
+)
+
+// The error types are registered with encoding/gob, so that errors from this
+// package can be encoded in interface values (such as an error field in a
+// struct). They're encoded like MarshalJSON does, so the errors they wrap will
+// be preserved even if they're not registered with gob.
+//
+// Note that net/rpc always sends the error returned from a method as a string;
+// to preserve the code it needs to be part of the reply.
+func init() {
+	gob.Register(constError{})
+	gob.Register(&withCode{})
+	gob.Register(&wrapped{})
+	gob.Register(&withFields{})
+	gob.Register(&withStack{})
+	gob.Register(&withRetry{})
+	gob.Register(&withTimeout{})
+	gob.Register(&withPublic{})
+	gob.Register(&withSeverity{})
+	gob.Register(&withRequestID{})
+	gob.Register(&withNote{})
+	gob.Register(&withOp{})
+	gob.Register(&withDetail{})
+	gob.Register(&withRelated{})
+	gob.Register(&stamped{})
+	gob.Register(&decoded{})
+	gob.Register(&decodedJoin{})
+}
+
+func (e constError) GobEncode() ([]byte, error)     { return MarshalJSON(e) }
+func (e *withCode) GobEncode() ([]byte, error)      { return MarshalJSON(e) }
+func (e *wrapped) GobEncode() ([]byte, error)       { return MarshalJSON(e) }
+func (e *withFields) GobEncode() ([]byte, error)    { return MarshalJSON(e) }
+func (e *withStack) GobEncode() ([]byte, error)     { return MarshalJSON(e) }
+func (e *withRetry) GobEncode() ([]byte, error)     { return MarshalJSON(e) }
+func (e *withTimeout) GobEncode() ([]byte, error)   { return MarshalJSON(e) }
+func (e *withPublic) GobEncode() ([]byte, error)    { return MarshalJSON(e) }
+func (e *withSeverity) GobEncode() ([]byte, error)  { return MarshalJSON(e) }
+func (e *withRequestID) GobEncode() ([]byte, error) { return MarshalJSON(e) }
+func (e *withNote) GobEncode() ([]byte, error)      { return MarshalJSON(e) }
+func (e *withOp) GobEncode() ([]byte, error)        { return MarshalJSON(e) }
+func (e *withDetail) GobEncode() ([]byte, error)    { return MarshalJSON(e) }
+func (e *withRelated) GobEncode() ([]byte, error)   { return MarshalJSON(e) }
+func (e *stamped) GobEncode() ([]byte, error)       { return MarshalJSON(e) }
+func (e *decoded) GobEncode() ([]byte, error)       { return MarshalJSON(e) }
+func (e *decodedJoin) GobEncode() ([]byte, error)   { return MarshalJSON(e) }
+
+func (e *constError) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	*e = constError{code: Code(err), msg: err.Error()}
+	return nil
+}
+
+func (e *withCode) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withCode); ok {
+		*e = *w
+		return nil
+	}
+	*e = withCode{error: err, code: Code(err)}
+	return nil
+}
+
+func (e *wrapped) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	switch w := err.(type) {
+	case *wrapped:
+		*e = *w
+	case *withCode: // Wrap() with an empty message.
+		*e = wrapped{error: w.error, code: w.code}
+	default:
+		*e = wrapped{error: err, code: Code(err)}
+	}
+	return nil
+}
+
+func (e *withFields) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withFields); ok {
+		*e = *w
+		return nil
+	}
+	*e = withFields{error: err}
+	return nil
+}
+
+func (e *withStack) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	*e = withStack{error: err}
+	return nil
+}
+
+func (e *withRetry) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withRetry); ok {
+		*e = *w
+		return nil
+	}
+	*e = withRetry{error: err}
+	return nil
+}
+
+func (e *withTimeout) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withTimeout); ok {
+		*e = *w
+		return nil
+	}
+	*e = withTimeout{error: err}
+	return nil
+}
+
+func (e *withPublic) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withPublic); ok {
+		*e = *w
+		return nil
+	}
+	*e = withPublic{error: err}
+	return nil
+}
+
+func (e *withSeverity) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withSeverity); ok {
+		*e = *w
+		return nil
+	}
+	*e = withSeverity{error: err}
+	return nil
+}
+
+func (e *withRequestID) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withRequestID); ok {
+		*e = *w
+		return nil
+	}
+	*e = withRequestID{error: err}
+	return nil
+}
+
+func (e *withNote) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if d, ok := err.(*decoded); ok {
+		*e = withNote{error: d.err, msg: d.msg}
+		return nil
+	}
+	*e = withNote{error: err}
+	return nil
+}
+
+func (e *withOp) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withOp); ok {
+		*e = *w
+		return nil
+	}
+	*e = withOp{error: err}
+	return nil
+}
+
+func (e *withDetail) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withDetail); ok {
+		*e = *w
+		return nil
+	}
+	*e = withDetail{error: err}
+	return nil
+}
+
+func (e *withRelated) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*withRelated); ok {
+		*e = *w
+		return nil
+	}
+	*e = withRelated{error: err}
+	return nil
+}
+
+func (e *stamped) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*stamped); ok {
+		*e = *w
+		return nil
+	}
+	*e = stamped{error: err}
+	return nil
+}
+
+func (e *decoded) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*decoded); ok {
+		*e = *w
+		return nil
+	}
+	*e = decoded{err: err}
+	return nil
+}
+
+func (e *decodedJoin) GobDecode(data []byte) error {
+	err, jErr := FromJSON(data)
+	if jErr != nil {
+		return jErr
+	}
+	if w, ok := err.(*decodedJoin); ok {
+		*e = *w
+		return nil
+	}
+	*e = decodedJoin{errs: []error{err}}
+	return nil
+}
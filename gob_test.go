@@ -0,0 +1,74 @@
+package guru
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var _ gob.GobEncoder = &withCode{}
+var _ gob.GobDecoder = &withCode{}
+
+func TestGob(t *testing.T) {
+	type reply struct{ Err error }
+
+	tests := []error{
+		New(42, "oh noes"),
+		NewSub(42, 3, "oh noes"),
+		NewStack(42, "oh noes"),
+		Wrap(42, errors.New("oh noes"), "ctx"),
+		Wrap(42, errors.New("oh noes"), ""),
+		WithCode(666, Wrap(42, New(1, "oh noes"), "ctx")),
+		WithFields(New(1, "oh noes"), map[string]interface{}{"a": "b"}),
+		WithStack(WithFields(New(1, "oh noes"), map[string]interface{}{"a": "b"})),
+		MarkRetryable(New(1, "oh noes")),
+		WithTimeout(New(1, "oh noes")),
+		WithPublic(New(1, "oh noes"), "public"),
+		WithSeverity(New(1, "oh noes"), LevelDebug),
+		WithRequestID(New(1, "oh noes"), "abc"),
+		Note(New(1, "oh noes"), "ctx"),
+		WithOp(WithOp(New(1, "oh noes"), "inner"), "outer"),
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := gob.NewEncoder(buf).Encode(reply{tt}); err != nil {
+				t.Fatal(err)
+			}
+
+			var out reply
+			if err := gob.NewDecoder(buf).Decode(&out); err != nil {
+				t.Fatal(err)
+			}
+
+			if a, b := fmt.Sprintf("%v", out.Err), fmt.Sprintf("%v", tt); a != b {
+				t.Errorf("message\nout:  %q\nwant: %q", a, b)
+			}
+			if a, b := Codes(out.Err), Codes(tt); !reflect.DeepEqual(a, b) {
+				t.Errorf("codes\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := fmt.Sprint(Fields(out.Err)), fmt.Sprint(Fields(tt)); a != b {
+				t.Errorf("fields\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := Severity(out.Err), Severity(tt); a != b {
+				t.Errorf("severity\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := Public(out.Err), Public(tt); a != b {
+				t.Errorf("public\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := Ops(out.Err), Ops(tt); !reflect.DeepEqual(a, b) {
+				t.Errorf("ops\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := RequestID(out.Err), RequestID(tt); a != b {
+				t.Errorf("request ID\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := IsTimeout(out.Err), IsTimeout(tt); a != b {
+				t.Errorf("timeout\nout:  %v\nwant: %v", a, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package guru
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy determines which code a Group reports.
+type Policy int
+
+// Policies for Group.
+const (
+	FirstCode    Policy = iota // Code of the first error with a code.
+	HighestCode                // Highest code.
+	FrequentCode               // Most frequent code; the first one wins on ties.
+)
+
+// Group is an error that collects multiple errors, for example to report all
+// failures of a batch job.
+//
+// The Code of a Group is the code of one of its errors, chosen by the Policy.
+// The zero value is an empty group using the FirstCode policy.
+type Group struct {
+	Policy Policy
+	errs   []error
+	msg    *memo
+}
+
+// Append adds all non-nil errors to the group.
+func (g *Group) Append(errs ...error) {
+	for _, err := range errs {
+		if err != nil {
+			g.errs = append(g.errs, err)
+			g.msg = new(memo)
+		}
+	}
+}
+
+// Len returns the number of errors in the group.
+func (g *Group) Len() int { return len(g.errs) }
+
+// ErrorOrNil returns the group, or nil if it has no errors.
+func (g *Group) ErrorOrNil() error {
+	if g == nil || len(g.errs) == 0 {
+		return nil
+	}
+	return g
+}
+
+func (g *Group) Unwrap() []error { return g.errs }
+
+// Error gets the messages of all errors, on their own line. The message is
+// cached until the next call to Append.
+func (g *Group) Error() string {
+	return g.msg.get(func() string {
+		msgs := make([]string, 0, len(g.errs))
+		for _, err := range g.errs {
+			msgs = append(msgs, err.Error())
+		}
+		return strings.Join(msgs, "\n")
+	})
+}
+
+func (g Group) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		writeBranches(s, g.errs, false, plusV)
+		return
+	}
+	for i, err := range g.errs {
+		if i > 0 {
+			fmt.Fprint(s, "\n")
+		}
+		formatInner(s, verb, err)
+	}
+}
+
+// Code gets the code of the group according to the Policy, using Code() on
+// every error in the group. It will return 0 if none of the errors have a code.
+func (g *Group) Code() int {
+	var (
+		code  int
+		found bool
+		order []int
+		count map[int]int
+	)
+	for _, err := range g.errs {
+		if !hasCode(err) {
+			continue
+		}
+		c := Code(err)
+		switch g.Policy {
+		case FirstCode:
+			return c
+		case HighestCode:
+			if !found || c > code {
+				code = c
+			}
+		case FrequentCode:
+			if count == nil {
+				count = make(map[int]int)
+			}
+			if count[c] == 0 {
+				order = append(order, c)
+			}
+			count[c]++
+		}
+		found = true
+	}
+	for i, c := range order {
+		if i == 0 || count[c] > count[code] {
+			code = c
+		}
+	}
+	return code
+}
+
+// Append adds errs to err, returning a *Group.
+//
+// If err is a *Group then errs are added to it, otherwise a new group is
+// created with err and errs. nil errors are skipped, and it will return nil if
+// all errors are nil.
+func Append(err error, errs ...error) error {
+	g, ok := err.(*Group)
+	if !ok {
+		g = &Group{}
+		g.Append(err)
+	}
+	g.Append(errs...)
+	return g.ErrorOrNil()
+}
+
+// hasCode reports if any error in the chain implements the coder interface.
+func hasCode(err error) bool {
+	switch err.(type) {
+	case nil:
+		return false
+	case *withCode, *wrapped, constError:
+		return true
+	}
+	has := false
+	walk(err, func(err error) bool {
+		_, has = codeOf(err)
+		return !has
+	})
+	return has
+}
@@ -0,0 +1,104 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var _ error = &Group{}
+var _ coder = &Group{}
+
+func TestGroup(t *testing.T) {
+	errs := []error{
+		errors.New("a"),
+		New(2, "b"),
+		New(5, "c"),
+		nil,
+		fmt.Errorf("x: %w", New(5, "d")),
+		New(2, "e"),
+		New(3, "f"),
+	}
+
+	tests := []struct {
+		policy Policy
+		errs   []error
+		want   int
+	}{
+		{FirstCode, nil, 0},
+		{FirstCode, errs[:1], 0},
+		{FirstCode, errs, 2},
+		{HighestCode, errs, 5},
+		{FrequentCode, errs, 2},
+		{FrequentCode, errs[:5], 5},
+		{HighestCode, []error{New(-2, "a"), New(-1, "b")}, -1},
+		{FrequentCode, []error{New(3, "a"), New(0, "b")}, 3},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			g := Group{Policy: tt.policy}
+			g.Append(tt.errs...)
+			out := Code(&g)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	if err := Append(nil, nil, nil); err != nil {
+		t.Fatalf("not nil: %v", err)
+	}
+
+	var err error
+	err = Append(err, New(1, "a"))
+	err = Append(err, nil, New(2, "b"), errors.New("c"))
+
+	g, ok := err.(*Group)
+	if !ok {
+		t.Fatalf("not a group: %T", err)
+	}
+	if g.Len() != 3 {
+		t.Errorf("len: %d", g.Len())
+	}
+	if out, want := err.Error(), "a\nb\nc"; out != want {
+		t.Errorf("\nout:  %q\nwant: %q", out, want)
+	}
+	if out, want := fmt.Sprintf("%v", err), "error 1: a\nerror 2: b\nc"; out != want {
+		t.Errorf("\nout:  %q\nwant: %q", out, want)
+	}
+	if out, want := Codes(err), []int{1, 2}; !reflect.DeepEqual(out, want) {
+		t.Errorf("\nout:  %v\nwant: %v", out, want)
+	}
+	if !errors.Is(err, CodeError(2)) {
+		t.Error("errors.Is")
+	}
+
+	err = Append(New(3, "x"), err)
+	if out, want := fmt.Sprintf("%v", err), "error 3: x\nerror 1: a\nerror 2: b\nc"; out != want {
+		t.Errorf("\nout:  %q\nwant: %q", out, want)
+	}
+
+	var empty Group
+	if empty.ErrorOrNil() != nil {
+		t.Error("ErrorOrNil")
+	}
+}
+
+func TestGroupErrorCache(t *testing.T) {
+	var g Group
+	if g.Error() != "" {
+		t.Errorf("%q", g.Error())
+	}
+	g.Append(errors.New("a"))
+	if g.Error() != "a" {
+		t.Errorf("%q", g.Error())
+	}
+	g.Append(errors.New("b"))
+	if g.Error() != "a\nb" {
+		t.Errorf("%q", g.Error())
+	}
+}
@@ -4,6 +4,7 @@ package guru
 import (
 	"errors"
 	"fmt"
+	"sync/atomic"
 )
 
 // coder is the main interface to errors in this package.
@@ -11,45 +12,161 @@ type coder interface {
 	Code() int
 }
 
+// subcoder is implemented by errors with a subcode.
+type subcoder interface {
+	Subcode() int
+}
+
 type withCode struct {
 	error
 	code int
+	sub  int
+	reg  *Registry // Registry it was created with; nil for DefaultRegistry.
+
+	// For templates: fields of the errors wrapping it, and the cached message.
+	outer map[string]interface{}
+	tm    *tmplMemo
+}
+
+// template gets the message with the template for the code applied, reporting
+// false if there is none.
+func (e *withCode) template() (string, bool) {
+	if !hasTemplates.Load() {
+		return "", false
+	}
+	return e.tm.get(func() (string, bool) {
+		return templated(e.reg, e.code, e.error.Error(), e.error, e.outer)
+	})
 }
 
-func (e *withCode) Unwrap() error                { return e.error }
-func (e *withCode) Code() int                    { return e.code }
-func (e withCode) Format(s fmt.State, verb rune) { fmt.Fprintf(s, "error %v: %v", e.code, e.error) }
+func (e *withCode) Error() string {
+	if msg, ok := e.template(); ok {
+		return msg
+	}
+	return e.error.Error()
+}
+func (e *withCode) Unwrap() error { return e.error }
+func (e *withCode) Code() int     { return e.code }
+func (e *withCode) Subcode() int  { return e.sub }
+func (e *withCode) Is(target error) bool {
+	c, ok := target.(CodeError)
+	return ok && int(c) == e.code
+}
+func (e withCode) Format(s fmt.State, verb rune) {
+	if msg, ok := e.template(); ok {
+		if verb == 'v' && s.Flag('+') && !isLeaf(e.error) {
+			formatCode(s, verb, e.reg, e.code, e.sub, msg, e.error)
+			return
+		}
+		formatCode(s, verb, e.reg, e.code, e.sub, "", plainError(msg))
+		return
+	}
+	formatCode(s, verb, e.reg, e.code, e.sub, "", e.error)
+}
 
 type wrapped struct {
 	msg  string
+	lazy *lazyMsg // Used instead of msg if set.
 	code int
+	reg  *Registry // Registry it was created with; nil for DefaultRegistry.
 	error
+
+	// For templates, as in withCode.
+	outer map[string]interface{}
+	tm    *tmplMemo
+}
+
+func (e *wrapped) message() string {
+	if e.lazy != nil {
+		return e.lazy.Error()
+	}
+	return e.msg
 }
 
-func (e *wrapped) Error() string { return e.msg }
+// text gets the message with the template for the code applied, if any.
+func (e *wrapped) text() string {
+	if !hasTemplates.Load() {
+		return e.message()
+	}
+	msg, ok := e.tm.get(func() (string, bool) {
+		return templated(e.reg, e.code, e.message(), e.error, e.outer)
+	})
+	if !ok {
+		return e.message()
+	}
+	return msg
+}
+
+func (e *wrapped) Error() string { return e.text() }
 func (e *wrapped) Unwrap() error { return e.error }
 func (e *wrapped) Code() int     { return e.code }
+func (e *wrapped) Is(target error) bool {
+	c, ok := target.(CodeError)
+	return ok && int(c) == e.code
+}
 func (e wrapped) Format(s fmt.State, verb rune) {
-	fmt.Fprintf(s, "error %v: %v", e.code, e.error)
-	if e.msg != "" {
-		fmt.Fprintf(s, ": %v", e.msg)
-	}
+	formatCode(s, verb, e.reg, e.code, 0, e.text(), e.error)
 }
 
+// CodeError is an error code that can be used as the target for errors.Is; it
+// matches any error in the chain with that code:
+//
+//	if errors.Is(err, guru.CodeError(404)) {
+//		...
+//	}
+type CodeError int
+
+func (c CodeError) Error() string { return "error " + fmtCode(int(c)) }
+func (c CodeError) Code() int     { return int(c) }
+
 // New returns a new error message with an error code.
 func New(code int, msg string) error {
-	return &withCode{
+	return created(KindNew, code, msg, nil, &withCode{
 		error: errors.New(msg),
 		code:  code,
-	}
+	})
+}
+
+// NewSub returns a new error message with an error code and subcode, for
+// example to identify the subsystem and the failure within it.
+func NewSub(code, subcode int, msg string) error {
+	return created(KindNew, code, msg, nil, &withCode{
+		error: errors.New(msg),
+		code:  code,
+		sub:   subcode,
+	})
 }
 
 // Errorf returns a new error message with an error code.
+//
+// The %w verb wraps errors like fmt.Errorf does, including multiple %w verbs,
+// so errors.Is, errors.As, Codes, etc. will see them:
+//
+//	err := guru.Errorf(4012, "charge card: %w", io.EOF)
+//	errors.Is(err, io.EOF)  // true
+//
+// Hooks see this as a KindWrap event if there is a %w verb, with the wrapped
+// error (or an error wrapping all of them for multiple %w verbs) as the cause.
 func Errorf(code int, format string, args ...interface{}) error {
-	return &withCode{
-		error: fmt.Errorf(format, args...),
-		code:  code,
+	return errorf(nil, code, format, args)
+}
+
+// errorf creates an error for Errorf with the registry reg. It must be called
+// directly from the exported function, so the hooks get the right caller.
+func errorf(reg *Registry, code int, format string, args []interface{}) error {
+	e := fmt.Errorf(format, args...)
+	kind, cause := KindNew, error(nil)
+	switch u := e.(type) {
+	case interface{ Unwrap() error }:
+		kind, cause = KindWrap, u.Unwrap()
+	case interface{ Unwrap() []error }:
+		kind, cause = KindWrap, e
 	}
+	return createdSkip(1, kind, code, e.Error(), cause, &withCode{
+		error: e,
+		code:  code,
+		reg:   reg,
+	})
 }
 
 // WithCode wraps an existing error with the provided error code. It will return
@@ -58,10 +175,10 @@ func WithCode(code int, err error) error {
 	if err == nil {
 		return nil
 	}
-	return &withCode{
+	return created(KindWrap, code, "", err, &withCode{
 		error: err,
 		code:  code,
-	}
+	})
 }
 
 // Wrap returns an error annotating err with an error code, and the supplied
@@ -70,11 +187,29 @@ func Wrap(code int, err error, msg string) error {
 	if err == nil {
 		return nil
 	}
-	return &wrapped{
+	return created(KindWrap, code, msg, err, &wrapped{
 		msg:   msg,
 		code:  code,
 		error: err,
+	})
+}
+
+// WrapKeep is like Wrap, but keeps the code of err: it uses Code(err) as the
+// code. If err has no code then it's annotated with Note instead. It will
+// return nil if err is nil.
+func WrapKeep(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	if !hasCode(err) {
+		return Note(err, msg)
 	}
+	code := Code(err)
+	return created(KindWrap, code, msg, err, &wrapped{
+		msg:   msg,
+		code:  code,
+		error: err,
+	})
 }
 
 // Wrapf returns an error annotating err with an error code, and the format
@@ -83,24 +218,235 @@ func Wrapf(code int, err error, msg string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	return &wrapped{
-		msg:   fmt.Sprintf(msg, args...),
+	m := fmt.Sprintf(msg, args...)
+	return created(KindWrap, code, m, err, &wrapped{
+		msg:   m,
 		code:  code,
 		error: err,
+	})
+}
+
+// walk calls fn for err and every error it wraps, until fn returns false.
+//
+// Errors with an Unwrap() []error method (such as those created with
+// errors.Join) are walked depth-first, in the order they're returned, which is
+// the same order as errors.Is() and errors.As() use. It reports whether the
+// entire tree was walked.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if !fn(err) {
+			return false
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if !walk(e, fn) {
+					return false
+				}
+			}
+			return true
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// CodeSearch sets which code Code reports if there is more than one code in
+// the chain.
+type CodeSearch int32
+
+// Code searches.
+const (
+	SearchOutermost CodeSearch = iota // Highest-level code (the default).
+	SearchInnermost                   // Lowest-level code, as with RootCode.
+)
+
+var codeSearch atomic.Int32
+
+// SetCodeSearch sets which code Code, Subcode, and Is use if there is more
+// than one code in the chain. Everything that uses Code (such as HTTPStatus
+// and Category) is affected.
+func SetCodeSearch(s CodeSearch) { codeSearch.Store(int32(s)) }
+
+func innermost() bool { return CodeSearch(codeSearch.Load()) == SearchInnermost }
+
+var defaultCode atomic.Int64
+
+// SetDefaultCode sets the code that Code and RootCode return for errors
+// without a code, instead of 0. The registry lookups such as HTTPStatus and
+// Public use the registration for this code for errors without a code.
+//
+// This doesn't make errors have a code: HasCode, Is, and Codes still report
+// the codes actually in the chain, and CodeOrDefault still returns its
+// default. It will return 0 for a nil error regardless.
+func SetDefaultCode(code int) { defaultCode.Store(int64(code)) }
+
+// mappedCode gets the code to look up in a registry for err: Code(err), or the
+// code set with SetDefaultCode if err has no code. It reports false if err is
+// nil, or if it has no code and there is no default code.
+func mappedCode(err error) (int, bool) {
+	if !hasCode(err) {
+		d := int(defaultCode.Load())
+		return d, err != nil && d != 0
 	}
+	return Code(err), true
 }
 
 // Code extracts the highest-level error code from the error or the errors it
-// wraps. It will return 0 if the error does not implement the coder interface.
+// wraps. It will return 0 (or the code set with SetDefaultCode) if none of the
+// errors implement the coder interface, or 0 if err is nil.
+//
+// Errors without a code are skipped, so the code is found even if there are
+// wrappers such as fmt.Errorf("%w") in between. For errors that wrap more than
+// one error (such as those created with errors.Join) the first code found in a
+// depth-first walk is used.
+//
+// The lowest-level code is used instead if SetCodeSearch(SearchInnermost) was
+// called.
 func Code(err error) int {
-	for {
-		if sc, ok := err.(coder); ok {
-			return sc.Code()
+	if innermost() {
+		return RootCode(err)
+	}
+
+	// Fast path for the common case where the outermost error is from this
+	// package, avoiding the interface assertion and walk.
+	switch e := err.(type) {
+	case nil:
+		return 0
+	case *withCode:
+		return e.code
+	case *wrapped:
+		return e.code
+	case constError:
+		return e.code
+	case *withFields:
+		if c, ok := e.error.(*withCode); ok {
+			return c.code
 		}
-		err = errors.Unwrap(err)
-		if err == nil {
-			break
+	case *withStack:
+		if c, ok := e.error.(*withCode); ok {
+			return c.code
 		}
+	case *stamped:
+		return Code(e.error)
+	}
+
+	code, found := 0, false
+	walk(err, func(err error) bool {
+		code, found = codeOf(err)
+		return !found
+	})
+	if !found {
+		return int(defaultCode.Load())
 	}
-	return 0
+	return code
+}
+
+// HasCode reports if err or any of the errors it wraps has an error code. This
+// can be used to distinguish "no code" from the code 0.
+func HasCode(err error) bool { return hasCode(err) }
+
+// CodeOrDefault is like Code, but returns def if none of the errors have a
+// code.
+func CodeOrDefault(err error, def int) int {
+	if !hasCode(err) {
+		return def
+	}
+	return Code(err)
+}
+
+// Codes extracts all error codes from the error and the errors it wraps, from
+// the outermost to the innermost error. It will return nil if none of the
+// errors implement the coder interface.
+//
+// The code of a Group is not included, as it's always the code of one of the
+// errors in the group.
+func Codes(err error) []int {
+	var codes []int
+	walk(err, func(err error) bool {
+		if _, ok := err.(*Group); ok {
+			return true
+		}
+		if c, ok := codeOf(err); ok {
+			codes = append(codes, c)
+		}
+		return true
+	})
+	return codes
+}
+
+// RootCode extracts the lowest-level error code from the error or the errors
+// it wraps; this is usually the code of the original error. It will return 0
+// (or the code set with SetDefaultCode) if none of the errors implement the
+// coder interface, or 0 if err is nil.
+//
+// For errors that wrap more than one error (such as those created with
+// errors.Join) the last code found in a depth-first walk is used.
+func RootCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	code, found := 0, false
+	walk(err, func(err error) bool {
+		if c, ok := codeOf(err); ok {
+			code, found = c, true
+		}
+		return true
+	})
+	if !found {
+		return int(defaultCode.Load())
+	}
+	return code
+}
+
+// Subcode extracts the subcode from the error that Code() would get the code
+// from. It will return 0 if that error has no subcode.
+func Subcode(err error) int {
+	inner := innermost()
+	sub := 0
+	walk(err, func(err error) bool {
+		if _, ok := codeOf(err); ok {
+			sub = 0
+			if sc, ok := err.(subcoder); ok {
+				sub = sc.Subcode()
+			}
+			return inner
+		}
+		return true
+	})
+	return sub
+}
+
+// GuruString formats the code and subcode of err as a classic Amiga Guru
+// Meditation, such as:
+//
+//	Guru Meditation #8000000B.00000003
+//
+// It will return an empty string if err is nil.
+func GuruString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf("Guru Meditation #%08X.%08X", uint32(Code(err)), uint32(Subcode(err)))
+}
+
+// Is reports if the highest-level error code in the chain (as returned by
+// Code) is code.
+func Is(err error, code int) bool {
+	return hasCode(err) && Code(err) == code
+}
+
+// Has reports if any error in the chain has the error code code.
+func Has(err error, code int) bool {
+	has := false
+	walk(err, func(err error) bool {
+		if c, ok := codeOf(err); ok && c == code {
+			has = true
+		}
+		return !has
+	})
+	return has
 }
@@ -3,6 +3,7 @@ package guru
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -40,8 +41,33 @@ func TestFormatWithStatus(t *testing.T) {
 				code:  42,
 			},
 			"%s",
+			"oh noes",
+		},
+		{
+			withCode{
+				error: errors.New("oh noes"),
+				code:  42,
+			},
+			"%q",
+			`"oh noes"`,
+		},
+		{
+			withCode{
+				error: errors.New("oh noes"),
+				code:  42,
+			},
+			"%+v",
 			"error 42: oh noes",
 		},
+		{
+			withCode{
+				error: errors.New("oh noes"),
+				code:  42,
+				sub:   3,
+			},
+			"%v",
+			"error 42.3: oh noes",
+		},
 	}
 
 	for i, tt := range tests {
@@ -82,7 +108,52 @@ func TestFormatWrapped(t *testing.T) {
 				code:  42,
 			},
 			"%s",
-			"error 42: oh noes",
+			"oh noes",
+		},
+		{
+			wrapped{
+				msg:   "ctx",
+				error: errors.New("oh noes"),
+				code:  42,
+			},
+			"%s",
+			"oh noes: ctx",
+		},
+		{
+			wrapped{
+				msg:   "ctx",
+				error: errors.New("oh noes"),
+				code:  42,
+			},
+			"%q",
+			`"oh noes: ctx"`,
+		},
+		{
+			wrapped{
+				msg:   "ctx",
+				error: New(1, "oh noes"),
+				code:  42,
+			},
+			"%v",
+			"error 42: error 1: oh noes: ctx",
+		},
+		{
+			wrapped{
+				msg:   "ctx",
+				error: New(1, "oh noes"),
+				code:  42,
+			},
+			"%s",
+			"oh noes: ctx",
+		},
+		{
+			wrapped{
+				msg:   "ctx",
+				error: Wrap(1, errors.New("oh noes"), "more ctx"),
+				code:  42,
+			},
+			"%+v",
+			"error 42: ctx\nerror 1: more ctx\noh noes",
 		},
 	}
 
@@ -106,6 +177,14 @@ func TestStatusCode(t *testing.T) {
 		{Wrap(666, New(42, "foo"), "bar"), 666},
 		{fmt.Errorf("%w", Wrap(666, New(42, "foo"), "bar")), 666},
 		{fmt.Errorf("%w", fmt.Errorf("%w", Wrap(666, New(42, "foo"), "bar"))), 666},
+		{errors.Join(errors.New("foo"), New(42, "foo"), New(666, "bar")), 42},
+		{fmt.Errorf("%w %w", errors.New("foo"), Wrap(666, New(42, "foo"), "bar")), 666},
+		{errors.Join(errors.New("foo"), errors.New("bar")), 0},
+		{WithFields(New(42, "foo"), nil), 42},
+		{WithFields(Wrap(666, New(42, "foo"), "bar"), nil), 666},
+		{NewStack(42, "foo"), 42},
+		{WithStack(WithFields(New(42, "foo"), nil)), 42},
+		{nil, 0},
 	}
 
 	for i, tt := range tests {
@@ -117,3 +196,358 @@ func TestStatusCode(t *testing.T) {
 		})
 	}
 }
+
+func TestCodes(t *testing.T) {
+	tests := []struct {
+		in   error
+		want []int
+	}{
+		{nil, nil},
+		{errors.New("foo"), nil},
+		{New(42, "foo"), []int{42}},
+		{Wrap(666, New(42, "foo"), "bar"), []int{666, 42}},
+		{WithCode(1, fmt.Errorf("%w", Wrap(666, New(42, "foo"), "bar"))), []int{1, 666, 42}},
+		{WithCode(1, errors.Join(Wrap(666, New(42, "foo"), "bar"), New(2, "x"))), []int{1, 666, 42, 2}},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Codes(tt.in)
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootCode(t *testing.T) {
+	tests := []struct {
+		in   error
+		want int
+	}{
+		{nil, 0},
+		{errors.New("foo"), 0},
+		{New(42, "foo"), 42},
+		{Wrap(666, New(42, "foo"), "bar"), 42},
+		{Wrap(666, errors.New("foo"), "bar"), 666},
+		{WithCode(1, fmt.Errorf("%w", Wrap(666, New(42, "foo"), "bar"))), 42},
+		{WithCode(1, errors.Join(Wrap(666, New(42, "foo"), "bar"), New(2, "x"))), 2},
+		{WithCode(1, errors.Join(New(2, "x"), errors.New("y"))), 2},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := RootCode(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubcode(t *testing.T) {
+	tests := []struct {
+		in       error
+		wantCode int
+		wantSub  int
+	}{
+		{nil, 0, 0},
+		{errors.New("foo"), 0, 0},
+		{New(42, "foo"), 42, 0},
+		{NewSub(3, 17, "foo"), 3, 17},
+		{fmt.Errorf("%w", NewSub(3, 17, "foo")), 3, 17},
+		{Wrap(666, NewSub(3, 17, "foo"), "bar"), 666, 0},
+		{WithCode(6, NewSub(3, 17, "foo")), 6, 0},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			code, sub := Code(tt.in), Subcode(tt.in)
+			if code != tt.wantCode || sub != tt.wantSub {
+				t.Errorf("\nout:  %d.%d\nwant: %d.%d\n", code, sub, tt.wantCode, tt.wantSub)
+			}
+		})
+	}
+}
+
+func TestGuruString(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("foo"), "Guru Meditation #00000000.00000000"},
+		{New(42, "foo"), "Guru Meditation #0000002A.00000000"},
+		{NewSub(0x8000000B, 3, "foo"), "Guru Meditation #8000000B.00000003"},
+		{NewSub(-1, 3, "foo"), "Guru Meditation #FFFFFFFF.00000003"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := GuruString(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHas(t *testing.T) {
+	tests := []struct {
+		in      error
+		code    int
+		wantIs  bool
+		wantHas bool
+	}{
+		{nil, 0, false, false},
+		{errors.New("foo"), 0, false, false},
+		{New(42, "foo"), 42, true, true},
+		{New(42, "foo"), 43, false, false},
+		{New(0, "foo"), 0, true, true},
+		{Wrap(666, New(42, "foo"), "bar"), 666, true, true},
+		{Wrap(666, New(42, "foo"), "bar"), 42, false, true},
+		{fmt.Errorf("%w", Wrap(666, New(42, "foo"), "bar")), 42, false, true},
+		{errors.Join(errors.New("x"), New(1, "foo"), New(42, "foo")), 42, false, true},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			is, has := Is(tt.in, tt.code), Has(tt.in, tt.code)
+			if is != tt.wantIs || has != tt.wantHas {
+				t.Errorf("\nout:  %t %t\nwant: %t %t\n", is, has, tt.wantIs, tt.wantHas)
+			}
+		})
+	}
+}
+
+func TestCodeError(t *testing.T) {
+	tests := []struct {
+		in     error
+		target error
+		want   bool
+	}{
+		{nil, CodeError(0), false},
+		{errors.New("foo"), CodeError(0), false},
+		{New(42, "foo"), CodeError(42), true},
+		{New(42, "foo"), CodeError(43), false},
+		{Wrap(666, New(42, "foo"), "bar"), CodeError(666), true},
+		{Wrap(666, New(42, "foo"), "bar"), CodeError(42), true},
+		{fmt.Errorf("%w", WithCode(42, errors.New("foo"))), CodeError(42), true},
+		{errors.Join(errors.New("x"), New(42, "foo")), CodeError(42), true},
+		{CodeError(42), CodeError(42), true},
+		{fmt.Errorf("x: %w", CodeError(42)), CodeError(42), true},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := errors.Is(tt.in, tt.target)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+var benchCode int
+
+func BenchmarkCode(b *testing.B) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"New", New(1, "x")},
+		{"Wrap", Wrap(2, New(1, "x"), "y")},
+		{"WithFields", WithFields(New(1, "x"), map[string]interface{}{"a": 1})},
+		{"NewStack", NewStack(1, "x")},
+		{"Errorf", fmt.Errorf("x: %w", New(1, "x"))},
+		{"NoCode", errors.New("x")},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				benchCode = Code(tt.err)
+			}
+		})
+	}
+}
+
+func TestErrorfWrap(t *testing.T) {
+	var (
+		inner = New(2, "x")
+		other = errors.New("y")
+		ev    Event
+	)
+	remove := AddHook(func(e Event) { ev = e })
+	defer remove()
+
+	tests := []struct {
+		in        error
+		wantIs    []error
+		wantCodes []int
+		wantKind  EventKind
+		wantV     string
+	}{
+		{Errorf(1, "ctx %d", 42), nil, []int{1}, KindNew, "error 1: ctx 42"},
+		{Errorf(1, "ctx: %w", inner), []error{inner}, []int{1, 2}, KindWrap, "error 1: ctx: error 2: x"},
+		{Errorf(1, "ctx: %w; %w", inner, other), []error{inner, other}, []int{1, 2}, KindWrap,
+			"error 1: ctx: error 2: x; y"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			for _, e := range tt.wantIs {
+				if !errors.Is(tt.in, e) {
+					t.Errorf("not %v", e)
+				}
+			}
+			if c := Codes(tt.in); !reflect.DeepEqual(c, tt.wantCodes) {
+				t.Errorf("codes\nout:  %v\nwant: %v\n", c, tt.wantCodes)
+			}
+			if out := fmt.Sprintf("%v", tt.in); out != tt.wantV {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.wantV)
+			}
+		})
+	}
+
+	Errorf(1, "ctx: %w", inner)
+	if ev.Kind != KindWrap || ev.Cause != inner {
+		t.Errorf("event: %#v", ev)
+	}
+	Errorf(1, "ctx")
+	if ev.Kind != KindNew || ev.Cause != nil {
+		t.Errorf("event: %#v", ev)
+	}
+}
+
+func TestWrapKeep(t *testing.T) {
+	tests := []struct {
+		in        error
+		wantCodes []int
+		want      string
+	}{
+		{WrapKeep(nil, "x"), nil, "<nil>"},
+		{WrapKeep(errors.New("x"), "y"), nil, "y: x"},
+		{WrapKeep(New(42, "x"), "y"), []int{42, 42}, "error 42: error 42: x: y"},
+		{WrapKeep(Wrap(2, New(1, "x"), "y"), "z"), []int{2, 2, 1}, "error 2: error 2: error 1: x: y: z"},
+		{WrapKeep(fmt.Errorf("y: %w", New(1, "x")), "z"), []int{1, 1}, "error 1: y: error 1: x: z"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if c := Codes(tt.in); !reflect.DeepEqual(c, tt.wantCodes) {
+				t.Errorf("codes\nout:  %v\nwant: %v\n", c, tt.wantCodes)
+			}
+			if out := fmt.Sprintf("%v", tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeOrDefault(t *testing.T) {
+	tests := []struct {
+		in      error
+		want    int
+		wantHas bool
+	}{
+		{nil, 500, false},
+		{errors.New("x"), 500, false},
+		{New(0, "x"), 0, true},
+		{New(42, "x"), 42, true},
+		{fmt.Errorf("x: %w", New(42, "x")), 42, true},
+		{errors.Join(errors.New("x"), New(42, "x")), 42, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := CodeOrDefault(tt.in, 500); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if has := HasCode(tt.in); has != tt.wantHas {
+				t.Errorf("HasCode\nout:  %t\nwant: %t\n", has, tt.wantHas)
+			}
+		})
+	}
+}
+
+func TestSetCodeSearch(t *testing.T) {
+	t.Cleanup(func() { SetCodeSearch(SearchOutermost) })
+
+	err := fmt.Errorf("a: %w", Wrap(3, fmt.Errorf("b: %w", fmt.Errorf("c: %w", NewSub(2, 1, "x"))), "y"))
+	tests := []struct {
+		search   CodeSearch
+		wantCode int
+		wantSub  int
+	}{
+		{SearchOutermost, 3, 0},
+		{SearchInnermost, 2, 1},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			SetCodeSearch(tt.search)
+			if c, s := Code(err), Subcode(err); c != tt.wantCode || s != tt.wantSub {
+				t.Errorf("\nout:  %d.%d\nwant: %d.%d\n", c, s, tt.wantCode, tt.wantSub)
+			}
+			if !Is(err, tt.wantCode) {
+				t.Error("Is false")
+			}
+			if RootCode(err) != 2 {
+				t.Errorf("RootCode: %d", RootCode(err))
+			}
+		})
+	}
+}
+
+func TestSetDefaultCode(t *testing.T) {
+	resetRegistry(t)
+	RegisterHTTPStatus(9999, 503)
+	RegisterMessage(9999, "unknown error")
+	RegisterExitCode(9999, 3)
+	SetDefaultCode(9999)
+	t.Cleanup(func() { SetDefaultCode(0) })
+
+	tests := []struct {
+		in         error
+		wantCode   int
+		wantRoot   int
+		wantHas    bool
+		wantStatus int
+		wantPublic string
+		wantExit   int
+	}{
+		{nil, 0, 0, false, 200, "", 0},
+		{errors.New("x"), 9999, 9999, false, 503, "unknown error", 3},
+		{fmt.Errorf("y: %w", errors.New("x")), 9999, 9999, false, 503, "unknown error", 3},
+		{New(404, "x"), 404, 404, true, 404, "internal error", 1},
+		{New(0, "x"), 0, 0, true, 500, "internal error", 1},
+		{Append(nil, errors.New("x"), errors.New("y")), 9999, 9999, false, 503, "unknown error", 3},
+		{Append(nil, errors.New("x"), New(404, "y")), 404, 404, true, 404, "internal error", 1},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if c := Code(tt.in); c != tt.wantCode {
+				t.Errorf("Code\nout:  %d\nwant: %d\n", c, tt.wantCode)
+			}
+			if c := RootCode(tt.in); c != tt.wantRoot {
+				t.Errorf("RootCode\nout:  %d\nwant: %d\n", c, tt.wantRoot)
+			}
+			if h := HasCode(tt.in); h != tt.wantHas {
+				t.Errorf("HasCode\nout:  %t\nwant: %t\n", h, tt.wantHas)
+			}
+			if s := HTTPStatus(tt.in); s != tt.wantStatus {
+				t.Errorf("HTTPStatus\nout:  %d\nwant: %d\n", s, tt.wantStatus)
+			}
+			if p := Public(tt.in); p != tt.wantPublic {
+				t.Errorf("Public\nout:  %q\nwant: %q\n", p, tt.wantPublic)
+			}
+			if e := ExitCode(tt.in); e != tt.wantExit {
+				t.Errorf("ExitCode\nout:  %d\nwant: %d\n", e, tt.wantExit)
+			}
+		})
+	}
+
+	if Is(errors.New("x"), 9999) {
+		t.Error("Is true for error without code")
+	}
+	if c := CodeOrDefault(errors.New("x"), 42); c != 42 {
+		t.Errorf("CodeOrDefault: %d", c)
+	}
+}
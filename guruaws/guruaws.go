@@ -0,0 +1,161 @@
+// Package guruaws assigns guru codes to errors from the AWS SDK.
+//
+// Errors from both the v2 SDK (smithy.APIError) and the v1 SDK (awserr.Error)
+// are recognized without importing them.
+package guruaws
+
+import (
+	"reflect"
+
+	"zgo.at/guru"
+)
+
+// Table maps AWS error codes such as "NoSuchKey" to guru codes.
+type Table map[string]int
+
+// DefaultTable is the table used by the Wrap function, and for AWS codes that
+// aren't in a Table.
+var DefaultTable = Table{
+	"NoSuchKey":                       404,
+	"NoSuchBucket":                    404,
+	"NotFound":                        404,
+	"NotFoundException":               404,
+	"ResourceNotFoundException":       404,
+	"AccessDenied":                    403,
+	"AccessDeniedException":           403,
+	"UnauthorizedOperation":           403,
+	"UnrecognizedClientException":     401,
+	"ExpiredToken":                    401,
+	"ExpiredTokenException":           401,
+	"ValidationError":                 400,
+	"ValidationException":             400,
+	"InvalidParameterValue":           400,
+	"ConditionalCheckFailedException": 409,
+	"ResourceInUseException":          409,
+	"AlreadyExistsException":          409,
+	"ResourceAlreadyExistsException":  409,
+}
+
+// Throttle is the set of AWS error codes that indicate the request was
+// throttled; these get the code 429 unless they're in the table, and are always
+// retryable.
+var Throttle = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"LimitExceededException":                 true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// faultServer is smithy.FaultServer.
+const faultServer = 1
+
+// fault gets the result of the ErrorFault() method of smithy.APIError; this
+// returns smithy.ErrorFault rather than an int, so it needs reflection.
+func fault(err error) int64 {
+	m := reflect.ValueOf(err).MethodByName("ErrorFault")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return 0
+	}
+	switch out := m.Call(nil)[0]; out.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return out.Int()
+	}
+	return 0
+}
+
+// Wrap annotates err with the guru code for its AWS error code:
+//
+//	out, err := s3client.GetObject(ctx, input)
+//	if err != nil {
+//		return guruaws.Wrap(err) // guru.Code(err) == 404 for NoSuchKey
+//	}
+//
+// AWS codes that aren't in t or DefaultTable use the HTTP status of the
+// response as the code, if there is one. Throttling errors and server errors
+// (a 5xx status or a server fault) are marked as retryable.
+//
+// err is returned as-is if it's nil, if it's not an AWS error, or if there's no
+// code for it.
+func (t Table) Wrap(err error) error {
+	var (
+		awsCode        = ErrorCode(err)
+		status, server = response(err)
+	)
+	if awsCode == "" && status == 0 {
+		return err
+	}
+
+	code, ok := t[awsCode]
+	if !ok {
+		code, ok = DefaultTable[awsCode]
+	}
+	switch {
+	case ok:
+	case Throttle[awsCode]:
+		code = 429
+	case status > 0:
+		code = status
+	default:
+		return err
+	}
+
+	err = guru.WithCode(code, err)
+	if Throttle[awsCode] || server {
+		err = guru.MarkRetryable(err)
+	}
+	return err
+}
+
+// Wrap is like Table.Wrap, using DefaultTable.
+func Wrap(err error) error { return DefaultTable.Wrap(err) }
+
+// ErrorCode gets the AWS error code of the first AWS error in the chain, such
+// as "NoSuchKey". It will return an empty string if there's no AWS error.
+func ErrorCode(err error) string {
+	var code string
+	guru.Walk(err, func(err error) bool {
+		switch e := err.(type) {
+		case interface{ ErrorCode() string }: // smithy.APIError
+			code = e.ErrorCode()
+		case interface {
+			Code() string
+			OrigErr() error
+		}: // awserr.Error
+			code = e.Code()
+		}
+		return code == ""
+	})
+	return code
+}
+
+// IsThrottle reports if err is an AWS throttling error.
+func IsThrottle(err error) bool { return Throttle[ErrorCode(err)] }
+
+// response gets the HTTP status and if it's a server error.
+func response(err error) (status int, server bool) {
+	guru.Walk(err, func(err error) bool {
+		if status == 0 {
+			switch e := err.(type) {
+			case interface{ HTTPStatusCode() int }: // awshttp.ResponseError
+				status = e.HTTPStatusCode()
+			case interface{ StatusCode() int }: // awserr.RequestFailure
+				status = e.StatusCode()
+			}
+		}
+		if !server {
+			server = fault(err) == faultServer
+		}
+		return true
+	})
+	return status, server || status >= 500
+}
@@ -0,0 +1,85 @@
+package guruaws
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+// Same shape as the SDK errors.
+type (
+	errorFault int
+	apiError   struct {
+		code  string
+		fault errorFault
+	}
+	responseError struct {
+		status int
+		err    error
+	}
+	awsErr struct {
+		code   string
+		status int
+	}
+)
+
+func (e *apiError) Error() string            { return "api error " + e.code }
+func (e *apiError) ErrorCode() string        { return e.code }
+func (e *apiError) ErrorMessage() string     { return "" }
+func (e *apiError) ErrorFault() errorFault   { return e.fault }
+func (e *responseError) Error() string       { return fmt.Sprintf("status %d: %s", e.status, e.err) }
+func (e *responseError) Unwrap() error       { return e.err }
+func (e *responseError) HTTPStatusCode() int { return e.status }
+func (e *awsErr) Error() string              { return e.code }
+func (e *awsErr) Code() string               { return e.code }
+func (e *awsErr) Message() string            { return "" }
+func (e *awsErr) OrigErr() error             { return nil }
+func (e *awsErr) StatusCode() int            { return e.status }
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in        error
+		table     Table
+		wantAWS   string
+		wantCode  int
+		wantRetry bool
+	}{
+		{nil, nil, "", 0, false},
+		{errors.New("x"), nil, "", 0, false},
+
+		{&apiError{code: "NoSuchKey"}, nil, "NoSuchKey", 404, false},
+		{&responseError{404, &apiError{code: "NoSuchKey"}}, nil, "NoSuchKey", 404, false},
+		{&responseError{400, &apiError{code: "ThrottlingException"}}, nil, "ThrottlingException", 429, true},
+		{&responseError{500, &apiError{code: "InternalError", fault: 1}}, nil, "InternalError", 500, true},
+		{&apiError{code: "InternalError", fault: 1}, nil, "InternalError", 0, false},
+		{&responseError{418, &apiError{code: "Teapot", fault: 2}}, nil, "Teapot", 418, false},
+		{fmt.Errorf("get: %w", &apiError{code: "AccessDenied"}), nil, "AccessDenied", 403, false},
+
+		{&awsErr{code: "ResourceNotFoundException", status: 400}, nil, "ResourceNotFoundException", 404, false},
+		{&awsErr{code: "SlowDown", status: 503}, nil, "SlowDown", 429, true},
+		{&awsErr{code: "ServiceUnavailable", status: 503}, nil, "ServiceUnavailable", 503, true},
+
+		{&apiError{code: "NoSuchKey"}, Table{"NoSuchKey": 4012}, "NoSuchKey", 4012, false},
+		{&apiError{code: "AccessDenied"}, Table{"NoSuchKey": 4012}, "AccessDenied", 403, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if c := ErrorCode(tt.in); c != tt.wantAWS {
+				t.Errorf("ErrorCode\nout:  %q\nwant: %q\n", c, tt.wantAWS)
+			}
+
+			out := tt.table.Wrap(tt.in)
+			if c := guru.Code(out); c != tt.wantCode {
+				t.Errorf("code\nout:  %d\nwant: %d\n", c, tt.wantCode)
+			}
+			if r := guru.Retryable(out); r != tt.wantRetry {
+				t.Errorf("retryable\nout:  %t\nwant: %t\n", r, tt.wantRetry)
+			}
+			if tt.wantCode == 0 && out != tt.in {
+				t.Error("not returned as-is")
+			}
+		})
+	}
+}
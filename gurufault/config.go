@@ -0,0 +1,69 @@
+package gurufault
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Configure enables the faults in s, which is a comma-separated list of
+// injection points and when they fail:
+//
+//	storage.read            Every call.
+//	storage.read=0.05       With a probability of 5%.
+//	storage.read=#3         Only the third call.
+//
+// A probability can be followed by "/seed" to set the seed, e.g.
+// "storage.read=0.05/42". Nothing is changed if there is an error.
+func Configure(s string) error {
+	var (
+		names  []string
+		faults []Fault
+	)
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		name, when, _ := strings.Cut(spec, "=")
+		if name == "" {
+			return fmt.Errorf("gurufault.Configure: no injection point in %q", spec)
+		}
+		f, err := parseFault(when)
+		if err != nil {
+			return fmt.Errorf("gurufault.Configure: %q: %w", spec, err)
+		}
+		names, faults = append(names, name), append(faults, f)
+	}
+	for i := range names {
+		Enable(names[i], faults[i])
+	}
+	return nil
+}
+
+func parseFault(s string) (Fault, error) {
+	var f Fault
+	switch {
+	case s == "":
+	case strings.HasPrefix(s, "#"):
+		n, err := strconv.Atoi(s[1:])
+		if err != nil || n < 1 {
+			return f, fmt.Errorf("invalid call number: %q", s[1:])
+		}
+		f.Nth = n
+	default:
+		prob, seed, hasSeed := strings.Cut(s, "/")
+		p, err := strconv.ParseFloat(prob, 64)
+		if err != nil || p <= 0 || p > 1 {
+			return f, fmt.Errorf("invalid probability: %q", prob)
+		}
+		f.Probability = p
+		if hasSeed {
+			f.Seed, err = strconv.ParseUint(seed, 10, 64)
+			if err != nil {
+				return f, fmt.Errorf("invalid seed: %q", seed)
+			}
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,50 @@
+package gurufault
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(Reset)
+
+	tests := []struct {
+		in      string
+		want    map[string]Fault
+		wantErr string
+	}{
+		{"", map[string]Fault{}, ""},
+		{"a", map[string]Fault{"a": {}}, ""},
+		{" a=0.5 , b=#3,c=0.1/42", map[string]Fault{
+			"a": {Probability: 0.5},
+			"b": {Nth: 3},
+			"c": {Probability: 0.1, Seed: 42},
+		}, ""},
+		{"a=#0", nil, `gurufault.Configure: "a=#0": invalid call number: "0"`},
+		{"a=0", nil, `gurufault.Configure: "a=0": invalid probability: "0"`},
+		{"a=2", nil, `gurufault.Configure: "a=2": invalid probability: "2"`},
+		{"a=0.1/x", nil, `gurufault.Configure: "a=0.1/x": invalid seed: "x"`},
+		{"b,=1", nil, `gurufault.Configure: no injection point in "=1"`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			Reset()
+			err := Configure(tt.in)
+			if (err == nil && tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Fatalf("\nout:  %v\nwant: %v\n", err, tt.wantErr)
+			}
+
+			out := make(map[string]Fault)
+			for k, p := range points {
+				out[k] = p.Fault
+			}
+			if tt.want == nil {
+				tt.want = map[string]Fault{}
+			}
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
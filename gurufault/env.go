@@ -0,0 +1,14 @@
+//go:build gurufault
+
+package gurufault
+
+import (
+	"fmt"
+	"os"
+)
+
+func init() {
+	if err := Configure(os.Getenv("GURUFAULT")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Package gurufault injects errors with a code, to test error handling.
+//
+// Code under test calls Maybe at the points where it can fail, which returns
+// nil unless a fault is enabled for that point:
+//
+//	func read(key string) ([]byte, error) {
+//		if err := gurufault.Maybe("storage.read", 5003); err != nil {
+//			return nil, err
+//		}
+//		...
+//	}
+//
+// Tests then enable faults with Inject:
+//
+//	gurufault.Inject(t, "storage.read", gurufault.Fault{Nth: 2})
+//
+// Maybe is cheap if no faults are enabled. Programs built with the gurufault
+// build tag read faults from the GURUFAULT environment variable on startup
+// (see Configure), for example for chaos experiments.
+package gurufault
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+// Fault configures when Maybe returns an error.
+//
+// If Nth is set then only the Nth call fails, and if Probability is set then
+// every call fails with that probability. Every call fails if neither is set.
+type Fault struct {
+	Nth         int     // Fail on the Nth call, starting at 1.
+	Probability float64 // Chance of failing, from 0 to 1.
+	Seed        uint64  // Seed for Probability, so that runs are reproducible.
+	Message     string  // Error message; default is "injected fault at <point>".
+}
+
+type point struct {
+	Fault
+	calls int
+	rand  *rand.Rand
+}
+
+var (
+	mu      sync.Mutex
+	points  = make(map[string]*point)
+	enabled atomic.Bool
+)
+
+// Enable enables the fault f for the injection point name, replacing any
+// previous fault for it.
+func Enable(name string, f Fault) {
+	mu.Lock()
+	defer mu.Unlock()
+	points[name] = &point{Fault: f, rand: rand.New(rand.NewPCG(f.Seed, 0))}
+	enabled.Store(true)
+}
+
+// Disable disables the fault for the injection point name.
+func Disable(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(points, name)
+	enabled.Store(len(points) > 0)
+}
+
+// Reset disables all faults.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	points = make(map[string]*point)
+	enabled.Store(false)
+}
+
+// Inject enables the fault f for the injection point name until the end of
+// the test.
+func Inject(t testing.TB, name string, f Fault) {
+	Enable(name, f)
+	t.Cleanup(func() { Disable(name) })
+}
+
+// Calls gets the number of times Maybe was called for the injection point
+// name since the fault was enabled, or 0 if it's not enabled.
+func Calls(name string) int {
+	mu.Lock()
+	defer mu.Unlock()
+	if p, ok := points[name]; ok {
+		return p.calls
+	}
+	return 0
+}
+
+// Maybe returns an error with the code code if a fault is enabled for the
+// injection point name and it should fail according to the Fault, or nil
+// otherwise.
+//
+// The error has the field "fault" set to name.
+func Maybe(name string, code int) error {
+	if !enabled.Load() {
+		return nil
+	}
+
+	mu.Lock()
+	p, ok := points[name]
+	if !ok {
+		mu.Unlock()
+		return nil
+	}
+	p.calls++
+	fail := true
+	switch {
+	case p.Nth > 0:
+		fail = p.calls == p.Nth
+	case p.Probability > 0:
+		fail = p.rand.Float64() < p.Probability
+	}
+	msg := p.Message
+	mu.Unlock()
+
+	if !fail {
+		return nil
+	}
+	if msg == "" {
+		msg = "injected fault at " + name
+	}
+	return guru.E(code, guru.Msg(msg), guru.Field("fault", name), guru.CallerSkip(1))
+}
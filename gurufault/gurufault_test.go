@@ -0,0 +1,119 @@
+package gurufault
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"zgo.at/guru"
+	"zgo.at/guru/gurutest"
+)
+
+// calls gets which of the first n calls to Maybe failed.
+func calls(n int) []int {
+	var failed []int
+	for i := 1; i <= n; i++ {
+		if Maybe("test", 5003) != nil {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+func TestMaybe(t *testing.T) {
+	if err := Maybe("test", 5003); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		f    Fault
+		want []int
+	}{
+		{Fault{}, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{Fault{Nth: 3}, []int{3}},
+		{Fault{Probability: 0.3, Seed: 1}, calls0(Fault{Probability: 0.3, Seed: 1})},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			Inject(t, "test", tt.f)
+			out := calls(10)
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if c := Calls("test"); c != 10 {
+				t.Errorf("Calls: %d", c)
+			}
+		})
+	}
+	if Calls("test") != 0 || Maybe("test", 5003) != nil {
+		t.Error("not disabled after test")
+	}
+}
+
+// calls0 runs calls with a fresh fault, to check that the same seed gives the
+// same results.
+func calls0(f Fault) []int {
+	Enable("test", f)
+	defer Disable("test")
+	return calls(10)
+}
+
+func TestProbability(t *testing.T) {
+	Inject(t, "test", Fault{Probability: 0.25, Seed: 42})
+	n := len(calls(10000))
+	if n < 2000 || n > 3000 {
+		t.Errorf("%d failures", n)
+	}
+}
+
+func TestError(t *testing.T) {
+	Inject(t, "test", Fault{})
+	Inject(t, "msg", Fault{Message: "disk on fire"})
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Maybe("test", 5003), "error 5003: injected fault at test"},
+		{Maybe("msg", 1), "error 1: disk on fire"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := fmt.Sprintf("%v", tt.err); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	err := Maybe("test", 5003)
+	if !guru.Is(err, 5003) || guru.Fields(err)["fault"] != "test" {
+		t.Errorf("%+v", err)
+	}
+
+	Reset()
+	if Maybe("test", 5003) != nil || Maybe("msg", 1) != nil {
+		t.Error("not reset")
+	}
+}
+
+func TestCaller(t *testing.T) {
+	Inject(t, "test", Fault{})
+	var ev guru.Event
+	t.Cleanup(guru.AddHook(func(e guru.Event) { ev = e }))
+
+	Maybe("test", 5003)
+	if !strings.HasSuffix(ev.Caller.File, "gurufault_test.go") || ev.Caller.Function != "zgo.at/guru/gurufault.TestCaller" {
+		t.Errorf("wrong caller: %#v", ev.Caller)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	Inject(t, "test", Fault{})
+	rec := gurutest.Capture(t)
+
+	Maybe("test", 5003)
+	if n := len(rec.WithField("fault", "test")); n != 1 {
+		t.Errorf("%d events", n)
+	}
+}
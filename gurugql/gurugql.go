@@ -0,0 +1,62 @@
+// Package gurugql converts guru errors to GraphQL errors with the code in the
+// extensions.
+//
+// The errors implement Extensions() map[string]interface{}, which is used by
+// graph-gophers/graphql-go and gqlgen to fill the "extensions" member:
+//
+//	func (r *resolver) Invoice(ctx context.Context, id string) (*Invoice, error) {
+//		inv, err := findInvoice(id)
+//		return inv, gurugql.Error(err)
+//	}
+package gurugql
+
+import (
+	"zgo.at/guru"
+)
+
+// Error converts err to a GraphQL error. The message is guru.Public(err), and
+// the extensions are from Extensions. The original error is available with
+// errors.Unwrap, errors.Is, etc. It will return nil if err is nil.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &gqlError{err: err, msg: guru.Public(err), ext: Extensions(err)}
+}
+
+type gqlError struct {
+	err error
+	msg string
+	ext map[string]interface{}
+}
+
+func (e *gqlError) Error() string                      { return e.msg }
+func (e *gqlError) Unwrap() error                      { return e.err }
+func (e *gqlError) Extensions() map[string]interface{} { return e.ext }
+
+// Extensions gets the GraphQL error extensions for err:
+//
+//	code       guru.Code(), if there is a code.
+//	category   guru.Category(), if it's not empty.
+//	requestID  guru.RequestID(), if it's not empty.
+//
+// It will return nil if none of them are set.
+func Extensions(err error) map[string]interface{} {
+	var ext map[string]interface{}
+	set := func(k string, v interface{}) {
+		if ext == nil {
+			ext = make(map[string]interface{}, 3)
+		}
+		ext[k] = v
+	}
+	if len(guru.Codes(err)) > 0 {
+		set("code", guru.Code(err))
+	}
+	if cat := guru.Category(err); cat != "" {
+		set("category", cat)
+	}
+	if id := guru.RequestID(err); id != "" {
+		set("requestID", id)
+	}
+	return ext
+}
@@ -0,0 +1,48 @@
+package gurugql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+func TestError(t *testing.T) {
+	reg := guru.DefaultRegistry
+	guru.DefaultRegistry = &guru.Registry{}
+	t.Cleanup(func() { guru.DefaultRegistry = reg })
+	guru.RegisterCategory(4000, 4999, "billing")
+	guru.RegisterMessage(4012, "invoice not found")
+
+	tests := []struct {
+		in      error
+		wantMsg string
+		wantExt map[string]interface{}
+	}{
+		{errors.New("x"), "internal error", nil},
+		{guru.New(42, "x"), "internal error", map[string]interface{}{"code": 42}},
+		{guru.WithRequestID(guru.New(4012, "x"), "abc"), "invoice not found",
+			map[string]interface{}{"code": 4012, "category": "billing", "requestID": "abc"}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Error(tt.in)
+			if out.Error() != tt.wantMsg {
+				t.Errorf("message\nout:  %#v\nwant: %#v\n", out.Error(), tt.wantMsg)
+			}
+			ext := out.(interface{ Extensions() map[string]interface{} }).Extensions()
+			if !reflect.DeepEqual(ext, tt.wantExt) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", ext, tt.wantExt)
+			}
+			if !errors.Is(out, tt.in) {
+				t.Error("doesn't wrap the original error")
+			}
+		})
+	}
+
+	if Error(nil) != nil {
+		t.Error("not nil")
+	}
+}
@@ -0,0 +1,18 @@
+module zgo.at/guru/gurugrpc
+
+go 1.23
+
+require (
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157
+	google.golang.org/grpc v1.65.0
+	zgo.at/guru v0.0.0
+)
+
+require (
+	golang.org/x/net v0.25.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	golang.org/x/text v0.15.0 // indirect
+	google.golang.org/protobuf v1.34.1 // indirect
+)
+
+replace zgo.at/guru => ../
@@ -0,0 +1,134 @@
+// Package gurugrpc converts between guru errors and gRPC statuses.
+package gurugrpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"zgo.at/guru"
+)
+
+// Domain is the domain of the errdetails.ErrorInfo detail used to store the
+// guru code in a status.
+const Domain = "zgo.at/guru"
+
+// ToStatus converts err to a gRPC status.
+//
+// The gRPC code is derived from guru.HTTPStatus(), and the guru code, subcode,
+// and fields are stored in an errdetails.ErrorInfo detail, so they can be
+// retrieved with FromStatus on the other end. Fields are stored as
+// "field.name" in the metadata, formatted with %v. The status message is the
+// error message without codes.
+//
+// Errors without a code are converted with status.Convert(). It will return
+// nil if err is nil.
+func ToStatus(err error) *status.Status { return toStatus(err, GRPCCode(err)) }
+
+func toStatus(err error, grpcCode codes.Code) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if !hasCode(err) {
+		return status.Convert(err)
+	}
+
+	code := guru.Code(err)
+	st := status.New(grpcCode, fmt.Sprintf("%s", err))
+	md := map[string]string{"code": strconv.Itoa(code)}
+	if sub := guru.Subcode(err); sub != 0 {
+		md["subcode"] = strconv.Itoa(sub)
+	}
+	for k, v := range guru.Fields(err) {
+		md["field."+k] = fmt.Sprintf("%v", v)
+	}
+	withDetails, dErr := st.WithDetails(&errdetails.ErrorInfo{
+		Domain:   Domain,
+		Reason:   strconv.Itoa(code),
+		Metadata: md,
+	})
+	if dErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// FromStatus converts a gRPC status created with ToStatus back to a guru
+// error, with the same code, message, and fields. The field values are
+// always strings.
+//
+// Statuses without a guru code are converted with st.Err(), and it will
+// return nil if st is nil or has the OK code.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != Domain {
+			continue
+		}
+		code, err := strconv.Atoi(info.Metadata["code"])
+		if err != nil {
+			continue
+		}
+		sub, _ := strconv.Atoi(info.Metadata["subcode"])
+		err = guru.NewSub(code, sub, st.Message())
+
+		var fields map[string]interface{}
+		for k, v := range info.Metadata {
+			if name, ok := strings.CutPrefix(k, "field."); ok {
+				if fields == nil {
+					fields = make(map[string]interface{})
+				}
+				fields[name] = v
+			}
+		}
+		if fields != nil {
+			err = guru.WithFields(err, fields)
+		}
+		return err
+	}
+	return st.Err()
+}
+
+// GRPCCode gets the gRPC code for err, based on guru.HTTPStatus().
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	switch guru.HTTPStatus(err) {
+	case 400:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 409:
+		return codes.AlreadyExists
+	case 412:
+		return codes.FailedPrecondition
+	case 429:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case 501:
+		return codes.Unimplemented
+	case 503:
+		return codes.Unavailable
+	case 504:
+		return codes.DeadlineExceeded
+	case 500:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+func hasCode(err error) bool {
+	return len(guru.Codes(err)) > 0
+}
@@ -0,0 +1,46 @@
+package gurugrpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"zgo.at/guru"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		in       error
+		wantCode codes.Code
+		wantMsg  string
+		want     string
+	}{
+		{nil, codes.OK, "", "<nil>"},
+		{errors.New("oh noes"), codes.Unknown, "oh noes", "rpc error: code = Unknown desc = oh noes"},
+		{guru.New(404, "oh noes"), codes.NotFound, "oh noes", "error 404: oh noes"},
+		{guru.Wrap(42, errors.New("oh noes"), "ctx"), codes.Internal, "oh noes: ctx", "error 42: oh noes: ctx"},
+		{guru.NewSub(42, 3, "oh noes"), codes.Internal, "oh noes", "error 42.3: oh noes"},
+		{status.Error(codes.Aborted, "oh noes"), codes.Aborted, "oh noes", "rpc error: code = Aborted desc = oh noes"},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			st := ToStatus(tt.in)
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg {
+				t.Errorf("\nout:  %s %q\nwant: %s %q", st.Code(), st.Message(), tt.wantCode, tt.wantMsg)
+			}
+
+			// Round-trip through the wire format.
+			st = status.FromProto(st.Proto())
+			back := FromStatus(st)
+			if out := fmt.Sprintf("%v", back); out != tt.want {
+				t.Errorf("\nout:  %q\nwant: %q", out, tt.want)
+			}
+			if guru.Code(back) != guru.Code(tt.in) || guru.Subcode(back) != guru.Subcode(tt.in) {
+				t.Errorf("code: %d.%d", guru.Code(back), guru.Subcode(back))
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package gurugrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"zgo.at/guru"
+)
+
+// Table maps guru codes to gRPC codes. Codes that aren't in the table use
+// GRPCCode.
+type Table map[int]codes.Code
+
+// Code gets the gRPC code for err.
+func (t Table) Code(err error) codes.Code {
+	if hasCode(err) {
+		if c, ok := t[guru.Code(err)]; ok {
+			return c
+		}
+	}
+	return GRPCCode(err)
+}
+
+// ToStatus is like the ToStatus function, but uses the gRPC code from the
+// table.
+func (t Table) ToStatus(err error) *status.Status { return toStatus(err, t.Code(err)) }
+
+// UnaryServerInterceptor converts errors returned from handlers to a gRPC
+// status with t.ToStatus, so handlers can return guru errors:
+//
+//	grpc.NewServer(grpc.UnaryInterceptor(gurugrpc.UnaryServerInterceptor(gurugrpc.Table{
+//		4012: codes.NotFound,
+//	})))
+//
+// The table may be nil.
+func UnaryServerInterceptor(t Table) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, t.ToStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor is like UnaryServerInterceptor, but for streaming
+// RPCs.
+func StreamServerInterceptor(t Table) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return t.ToStatus(err).Err()
+		}
+		return nil
+	}
+}
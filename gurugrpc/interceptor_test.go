@@ -0,0 +1,58 @@
+package gurugrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"zgo.at/guru"
+)
+
+func TestInterceptor(t *testing.T) {
+	table := Table{4012: codes.NotFound}
+	tests := []struct {
+		in         error
+		wantCode   codes.Code
+		wantFields map[string]interface{}
+	}{
+		{nil, codes.OK, nil},
+		{errors.New("x"), codes.Unknown, nil},
+		{guru.New(4012, "x"), codes.NotFound, nil},
+		{guru.New(403, "x"), codes.PermissionDenied, nil},
+		{guru.WithFields(guru.New(4012, "x"), map[string]interface{}{"id": 42, "pw": guru.Secret("hunter2")}),
+			codes.NotFound, map[string]interface{}{"id": "42", "pw": "[REDACTED]"}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			unary := UnaryServerInterceptor(table)
+			_, uErr := unary(context.Background(), nil, &grpc.UnaryServerInfo{},
+				func(context.Context, interface{}) (interface{}, error) { return nil, tt.in })
+			stream := StreamServerInterceptor(table)
+			sErr := stream(nil, nil, &grpc.StreamServerInfo{},
+				func(interface{}, grpc.ServerStream) error { return tt.in })
+
+			for _, err := range []error{uErr, sErr} {
+				st := status.Convert(err)
+				if st.Code() != tt.wantCode {
+					t.Errorf("\nout:  %s\nwant: %s\n", st.Code(), tt.wantCode)
+				}
+				back := FromStatus(status.FromProto(st.Proto()))
+				if guru.Code(back) != guru.Code(tt.in) {
+					t.Errorf("code: %d", guru.Code(back))
+				}
+				if f := guru.Fields(back); fmt.Sprint(f) != fmt.Sprint(tt.wantFields) {
+					t.Errorf("fields\nout:  %#v\nwant: %#v\n", f, tt.wantFields)
+				}
+			}
+		})
+	}
+
+	var nilTable Table
+	if c := nilTable.Code(guru.New(404, "x")); c != codes.NotFound {
+		t.Errorf("nil table: %s", c)
+	}
+}
@@ -0,0 +1,77 @@
+package guruhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"zgo.at/guru"
+)
+
+type debugEntry struct {
+	Time    time.Time              `json:"time"`
+	Code    int                    `json:"code"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+	Stack   []string               `json:"stack,omitempty"`
+}
+
+var debugPage = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Recent errors</title>
+<style>td, th { text-align: left; vertical-align: top; padding: .2em .5em; } pre { margin: 0; }</style>
+</head><body>
+<h1>Recent errors</h1>
+<table>
+<thead><tr><th>Time</th><th>Code</th><th>Message</th><th>Fields</th><th>Stack</th></tr></thead>
+<tbody>
+{{- range . }}
+<tr><td>{{ .Time.Format "2006-01-02 15:04:05.000" }}</td><td>{{ .Code }}</td><td>{{ .Message }}</td>
+<td>{{ range $k, $v := .Fields }}{{ $k }}={{ $v }}<br>{{ end }}</td>
+<td><pre>{{ range .Stack }}{{ . }}
+{{ end }}</pre></td></tr>
+{{- else }}
+<tr><td colspan="5">No errors.</td></tr>
+{{- end }}
+</tbody>
+</table>
+</body></html>
+`))
+
+// DebugHandler returns a handler that shows the errors recorded in ring, newest
+// first, like net/http/pprof does for profiles:
+//
+//	ring := guru.NewRing(100)
+//	guru.AddHook(ring.Record)
+//	http.Handle("/debug/errors", guruhttp.DebugHandler(ring))
+//
+// They're shown as a HTML page, or as JSON if the format query parameter is
+// "json" or the Accept header is application/json. The page may include
+// internal details, so it shouldn't be public.
+func DebugHandler(ring *guru.Ring) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			entries = ring.Entries()
+			list    = make([]debugEntry, 0, len(entries))
+		)
+		for _, e := range entries {
+			d := debugEntry{Time: e.Time, Code: e.Code, Message: e.Message, Fields: e.Fields}
+			for _, f := range e.Stack {
+				d.Stack = append(d.Stack, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+			}
+			list = append(list, d)
+		}
+
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "no-store")
+		if r.FormValue("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			json.NewEncoder(w).Encode(list)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		debugPage.Execute(w, list)
+	})
+}
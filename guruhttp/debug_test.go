@@ -0,0 +1,52 @@
+package guruhttp
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+func TestDebugHandler(t *testing.T) {
+	ring := guru.NewRing(10)
+	remove := guru.AddHook(ring.Record)
+	defer remove()
+	guru.New(4012, "<invoice> not found")
+	guru.Wrap(500, guru.NewStack(1, "x"), "y")
+
+	h := DebugHandler(ring)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/debug/errors?format=json", nil))
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type: %q", ct)
+	}
+	var out []debugEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 3 || out[0].Code != 500 || out[2].Code != 4012 || out[2].Message != "<invoice> not found" {
+		t.Errorf("%#v", out)
+	}
+	if len(out[0].Stack) < 2 || !strings.HasPrefix(out[0].Stack[0], "zgo.at/guru/guruhttp.TestDebugHandler ") {
+		t.Errorf("stack: %#v", out[0].Stack)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/debug/errors", nil))
+	if ct := rr.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type: %q", ct)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "<td>4012</td><td>&lt;invoice&gt; not found</td>") || strings.Contains(body, "No errors.") {
+		t.Error(body)
+	}
+
+	rr = httptest.NewRecorder()
+	DebugHandler(guru.NewRing(1)).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	if !strings.Contains(rr.Body.String(), "No errors.") {
+		t.Error(rr.Body.String())
+	}
+}
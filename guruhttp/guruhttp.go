@@ -0,0 +1,81 @@
+// Package guruhttp renders guru errors in net/http handlers.
+package guruhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"zgo.at/guru"
+)
+
+// HandlerFunc is a HTTP handler that can return an error, which is written
+// with WriteError:
+//
+//	http.Handle("/invoice", guruhttp.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//		inv, err := findInvoice(r.FormValue("id"))
+//		if err != nil {
+//			return guru.Wrap(4012, err, "find invoice")
+//		}
+//		return json.NewEncoder(w).Encode(inv)
+//	}))
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// ServeHTTP calls f, and writes the error with WriteError if it's not nil.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// Handler returns a http.Handler for f.
+func Handler(f func(http.ResponseWriter, *http.Request) error) http.Handler {
+	return HandlerFunc(f)
+}
+
+type errorJSON struct {
+	Code      *int   `json:"code,omitempty"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	ErrorID   string `json:"error_id,omitempty"`
+}
+
+// WriteError writes err as JSON with the status from guru.HTTPStatus:
+//
+//	{"code": 4012, "message": "invoice not found", "request_id": "01HXG3"}
+//
+// The message is guru.Public(err), so internal details are never sent. The
+// code is omitted for errors without a code. The request ID is guru.RequestID,
+// or the X-Request-ID header of r if it's empty and r is not nil; it's omitted
+// if both are empty.
+//
+// The error ID from guru.ID is added as "error_id" and written with
+// SetErrorID, if there is one.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	j := errorJSON{Message: guru.Public(err), RequestID: guru.RequestID(err), ErrorID: SetErrorID(w, err)}
+	if len(guru.Codes(err)) > 0 {
+		c := guru.Code(err)
+		j.Code = &c
+	}
+	if j.RequestID == "" && r != nil {
+		j.RequestID = r.Header.Get("X-Request-ID")
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(guru.HTTPStatus(err))
+	json.NewEncoder(w).Encode(j)
+}
+
+// SetErrorID sets the X-Error-ID header to guru.ID(err), so a user can report
+// the ID and it can be found in the logs. The header isn't set if err doesn't
+// have an ID. It returns the ID.
+//
+// This needs to be called before the header is written; WriteError,
+// WriteProblem, and WriteJSONAPI already call it.
+func SetErrorID(w http.ResponseWriter, err error) string {
+	id := guru.ID(err)
+	if id != "" {
+		w.Header().Set("X-Error-ID", id)
+	}
+	return id
+}
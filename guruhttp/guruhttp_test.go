@@ -0,0 +1,99 @@
+package guruhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+func TestHandler(t *testing.T) {
+	reg := guru.DefaultRegistry
+	guru.DefaultRegistry = &guru.Registry{}
+	t.Cleanup(func() { guru.DefaultRegistry = reg })
+	guru.RegisterHTTPStatus(4012, 404)
+	guru.RegisterMessage(4012, "invoice not found")
+
+	tests := []struct {
+		err        error
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{nil, "", 200, "ok"},
+		{errors.New("select failed"), "", 500, `{"message":"internal error"}`},
+		{guru.New(4012, "select failed"), "", 404, `{"code":4012,"message":"invoice not found"}`},
+		{guru.WithPublic(guru.New(403, "x"), "nope"), "", 403, `{"code":403,"message":"nope"}`},
+		{guru.WithRequestID(guru.New(4012, "x"), "abc"), "hdr", 404,
+			`{"code":4012,"message":"invoice not found","request_id":"abc"}`},
+		{guru.New(4012, "x"), "hdr", 404, `{"code":4012,"message":"invoice not found","request_id":"hdr"}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+				if tt.err == nil {
+					w.Write([]byte("ok"))
+				}
+				return tt.err
+			})
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Request-ID", tt.header)
+			}
+			h.ServeHTTP(rr, r)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status\nout:  %d\nwant: %d\n", rr.Code, tt.wantStatus)
+			}
+			if out := strings.TrimSpace(rr.Body.String()); out != tt.wantBody {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.wantBody)
+			}
+			if tt.err != nil && rr.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+				t.Errorf("Content-Type: %q", rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestErrorID(t *testing.T) {
+	id := "01HZY3MD8Y6SQF9QW1G1J0ZKXN"
+	err, jErr := guru.FromJSON([]byte(`{"code":404,"message":"x","id":"` + id + `"}`))
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+
+	for _, write := range []func(http.ResponseWriter, error){
+		func(w http.ResponseWriter, err error) { WriteError(w, nil, err) },
+		WriteProblem,
+		WriteJSONAPI,
+	} {
+		rr := httptest.NewRecorder()
+		write(rr, err)
+		if h := rr.Header().Get("X-Error-ID"); h != id {
+			t.Errorf("header\nout:  %q\nwant: %q\n", h, id)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	WriteError(rr, nil, err)
+	if out, want := strings.TrimSpace(rr.Body.String()), `{"code":404,"message":"internal error","error_id":"`+id+`"}`; out != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", out, want)
+	}
+	rr = httptest.NewRecorder()
+	WriteProblem(rr, err)
+	if !strings.Contains(rr.Body.String(), `"error_id":"`+id+`"`) {
+		t.Error(rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	SetErrorID(rr, errors.New("x"))
+	if _, ok := rr.Header()["X-Error-Id"]; ok {
+		t.Error("header set for error without ID")
+	}
+}
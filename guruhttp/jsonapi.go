@@ -0,0 +1,126 @@
+package guruhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"zgo.at/guru"
+)
+
+// JSONAPIError is an error object from the JSON:API specification.
+type JSONAPIError struct {
+	ID     string                 `json:"id,omitempty"`
+	Links  map[string]string      `json:"links,omitempty"`
+	Status string                 `json:"status"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title"`
+	Detail string                 `json:"detail,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPIErrors gets the JSON:API error objects for err.
+//
+// Errors that wrap more than one error without a code (such as a guru.Group
+// or errors.Join) get an object for every error they wrap; otherwise there is
+// just one object. The members are:
+//
+//	id      guru.RequestID().
+//	links   The URL registered with guru.RegisterHelpURL as "about".
+//	status  guru.HTTPStatus().
+//	code    guru.Code(), if there is a code.
+//	title   The name registered with guru.Register, or the HTTP status text if
+//	        there is none.
+//	detail  guru.Public().
+//	meta    The category and subcode if they're set.
+//
+// It will return nil if err is nil.
+func JSONAPIErrors(err error) []JSONAPIError {
+	if err == nil {
+		return nil
+	}
+	id := guru.RequestID(err)
+	errs := split(err)
+	objs := make([]JSONAPIError, 0, len(errs))
+	for _, e := range errs {
+		o := jsonapiError(e)
+		if o.ID == "" {
+			o.ID = id
+		}
+		objs = append(objs, o)
+	}
+	return objs
+}
+
+func jsonapiError(err error) JSONAPIError {
+	status := guru.HTTPStatus(err)
+	o := JSONAPIError{
+		ID:     guru.RequestID(err),
+		Status: strconv.Itoa(status),
+		Title:  http.StatusText(status),
+		Detail: guru.Public(err),
+	}
+	if len(guru.Codes(err)) == 0 {
+		return o
+	}
+
+	code := guru.Code(err)
+	o.Code = guru.FormatCode(code)
+	info, _ := guru.RegistryOf(err).Lookup(code)
+	if info.Name != "" {
+		o.Title = info.Name
+	}
+	if info.HelpURL != "" {
+		o.Links = map[string]string{"about": info.HelpURL}
+	}
+	if info.Category != "" {
+		o.Meta = map[string]interface{}{"category": info.Category}
+	}
+	if sub := guru.Subcode(err); sub != 0 {
+		if o.Meta == nil {
+			o.Meta = make(map[string]interface{})
+		}
+		o.Meta["subcode"] = sub
+	}
+	return o
+}
+
+// split gets all errors that err wraps if it wraps more than one error before
+// an error with a code, or err itself if it doesn't.
+func split(err error) []error {
+	for e := err; e != nil; {
+		if _, ok := e.(*guru.Group); !ok {
+			if _, ok := e.(interface{ Code() int }); ok {
+				return []error{err}
+			}
+		}
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			var errs []error
+			for _, m := range u.Unwrap() {
+				if m != nil {
+					errs = append(errs, split(m)...)
+				}
+			}
+			return errs
+		case interface{ Unwrap() error }:
+			e = u.Unwrap()
+		default:
+			return []error{err}
+		}
+	}
+	return []error{err}
+}
+
+// WriteJSONAPI writes err as a JSON:API document with the errors from
+// JSONAPIErrors. The HTTP status is guru.HTTPStatus(err), and the error ID is
+// written with SetErrorID.
+func WriteJSONAPI(w http.ResponseWriter, err error) {
+	SetErrorID(w, err)
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(guru.HTTPStatus(err))
+	json.NewEncoder(w).Encode(struct {
+		Errors []JSONAPIError `json:"errors"`
+	}{JSONAPIErrors(err)})
+}
@@ -0,0 +1,74 @@
+package guruhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+func TestWriteJSONAPI(t *testing.T) {
+	reg := guru.DefaultRegistry
+	guru.DefaultRegistry = &guru.Registry{}
+	t.Cleanup(func() { guru.DefaultRegistry = reg })
+	guru.Register(4012, "ErrInvoiceMissing", "")
+	guru.RegisterCategory(4000, 4999, "billing")
+	guru.RegisterHTTPStatus(4012, 404)
+	guru.RegisterMessage(4012, "invoice not found")
+	guru.RegisterHelpURL(4012, "https://example.com/errors/4012")
+	scoped := &guru.Registry{}
+	scoped.Register(4013, "ErrCardDeclined", "")
+	scoped.RegisterCategory(4000, 4999, "payments")
+	scoped.RegisterHTTPStatus(4013, 402)
+
+	tests := []struct {
+		in         error
+		wantStatus int
+		want       string
+	}{
+		{errors.New("x"), 500,
+			`{"errors":[{"status":"500","title":"Internal Server Error","detail":"internal error"}]}`},
+		{guru.WithRequestID(guru.NewSub(4012, 2, "x"), "abc"), 404,
+			`{"errors":[{"id":"abc","links":{"about":"https://example.com/errors/4012"},"status":"404","code":"4012","title":"ErrInvoiceMissing","detail":"invoice not found","meta":{"category":"billing","subcode":2}}]}`},
+		{scoped.New(4013, "x"), 402,
+			`{"errors":[{"status":"402","code":"4013","title":"ErrCardDeclined","detail":"internal error","meta":{"category":"payments"}}]}`},
+		{guru.WithRequestID(guru.Append(nil, guru.New(400, "a"), errors.Join(guru.New(403, "b"), errors.New("c"))), "abc"), 400,
+			`{"errors":[{"id":"abc","status":"400","code":"400","title":"Bad Request","detail":"internal error"},` +
+				`{"id":"abc","status":"403","code":"403","title":"Forbidden","detail":"internal error"},` +
+				`{"id":"abc","status":"500","title":"Internal Server Error","detail":"internal error"}]}`},
+		{guru.WithCode(400, errors.Join(guru.New(403, "b"), errors.New("c"))), 400,
+			`{"errors":[{"status":"400","code":"400","title":"Bad Request","detail":"internal error"}]}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			WriteJSONAPI(rr, tt.in)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status\nout:  %d\nwant: %d\n", rr.Code, tt.wantStatus)
+			}
+			if out := strings.TrimSpace(rr.Body.String()); out != tt.want {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/vnd.api+json" {
+				t.Errorf("Content-Type: %q", ct)
+			}
+		})
+	}
+
+	if JSONAPIErrors(nil) != nil {
+		t.Error("not nil")
+	}
+}
+
+func TestJSONAPICodeFormat(t *testing.T) {
+	t.Cleanup(func() { guru.SetCodeFormat(guru.CodeFormat{}) })
+	guru.SetCodeFormat(guru.CodeFormat{Base: 16})
+
+	errs := JSONAPIErrors(guru.New(404, "x"))
+	if len(errs) != 1 || errs[0].Code != "0x194" {
+		t.Errorf("\nout:  %#v", errs)
+	}
+}
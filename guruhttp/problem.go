@@ -0,0 +1,65 @@
+package guruhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"zgo.at/guru"
+)
+
+// Problem is a RFC 7807 problem details object.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Code      *int   `json:"code,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	ErrorID   string `json:"error_id,omitempty"`
+}
+
+// NewProblem gets the RFC 7807 problem details for err:
+//
+//	type        The URL registered with guru.RegisterHelpURL, or
+//	            "about:blank" if there is none.
+//	title       The name registered with guru.Register, or the HTTP status
+//	            text if there is none.
+//	status      guru.HTTPStatus().
+//	detail      guru.Public().
+//	code        guru.Code(), if there is a code.
+//	request_id  guru.RequestID(), if there is one.
+//	error_id    guru.ID(), if there is one.
+func NewProblem(err error) Problem {
+	status := guru.HTTPStatus(err)
+	p := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    guru.Public(err),
+		RequestID: guru.RequestID(err),
+		ErrorID:   guru.ID(err),
+	}
+	if len(guru.Codes(err)) > 0 {
+		c := guru.Code(err)
+		p.Code = &c
+		info, _ := guru.RegistryOf(err).Lookup(c)
+		if info.HelpURL != "" {
+			p.Type = info.HelpURL
+		}
+		if info.Name != "" {
+			p.Title = info.Name
+		}
+	}
+	return p
+}
+
+// WriteProblem writes err as an application/problem+json body, as described in
+// RFC 7807. See NewProblem for the members.
+func WriteProblem(w http.ResponseWriter, err error) {
+	p := NewProblem(err)
+	SetErrorID(w, err)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
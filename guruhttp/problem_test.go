@@ -0,0 +1,55 @@
+package guruhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+func TestWriteProblem(t *testing.T) {
+	reg := guru.DefaultRegistry
+	guru.DefaultRegistry = &guru.Registry{}
+	t.Cleanup(func() { guru.DefaultRegistry = reg })
+	guru.Register(4012, "ErrInvoiceMissing", "")
+	guru.RegisterHTTPStatus(4012, 404)
+	guru.RegisterMessage(4012, "invoice not found")
+	guru.RegisterHelpURL(4012, "https://example.com/errors/4012")
+	scoped := &guru.Registry{}
+	scoped.Register(4013, "ErrCardDeclined", "")
+	scoped.RegisterHTTPStatus(4013, 402)
+	scoped.RegisterHelpURL(4013, "https://example.com/errors/4013")
+
+	tests := []struct {
+		in         error
+		wantStatus int
+		want       string
+	}{
+		{errors.New("x"), 500,
+			`{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error"}`},
+		{guru.New(403, "x"), 403,
+			`{"type":"about:blank","title":"Forbidden","status":403,"detail":"internal error","code":403}`},
+		{guru.WithRequestID(guru.New(4012, "x"), "abc"), 404,
+			`{"type":"https://example.com/errors/4012","title":"ErrInvoiceMissing","status":404,"detail":"invoice not found","code":4012,"request_id":"abc"}`},
+		{scoped.New(4013, "x"), 402,
+			`{"type":"https://example.com/errors/4013","title":"ErrCardDeclined","status":402,"detail":"internal error","code":4013}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			WriteProblem(rr, tt.in)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status\nout:  %d\nwant: %d\n", rr.Code, tt.wantStatus)
+			}
+			if out := strings.TrimSpace(rr.Body.String()); out != tt.want {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type: %q", ct)
+			}
+		})
+	}
+}
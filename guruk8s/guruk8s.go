@@ -0,0 +1,161 @@
+// Package guruk8s converts between guru codes and the status reasons of the
+// Kubernetes API, such as the errors returned by client-go.
+//
+// Errors from k8s.io/apimachinery (*errors.StatusError, or anything else that
+// implements errors.APIStatus) are recognized without importing it.
+package guruk8s
+
+import (
+	"reflect"
+
+	"zgo.at/guru"
+)
+
+// Table maps status reasons such as "NotFound" to guru codes.
+type Table map[string]int
+
+// DefaultTable is the table used by the Wrap function, and for reasons that
+// aren't in a Table.
+var DefaultTable = Table{
+	"BadRequest":            400,
+	"Unauthorized":          401,
+	"Forbidden":             403,
+	"NotFound":              404,
+	"MethodNotAllowed":      405,
+	"NotAcceptable":         406,
+	"AlreadyExists":         409,
+	"Conflict":              409,
+	"Gone":                  410,
+	"Expired":               410,
+	"RequestEntityTooLarge": 413,
+	"UnsupportedMediaType":  415,
+	"Invalid":               422,
+	"TooManyRequests":       429,
+	"InternalError":         500,
+	"ServiceUnavailable":    503,
+	"Timeout":               504,
+	"ServerTimeout":         504,
+}
+
+// Retry is the set of reasons for which the request can be retried; errors with
+// these reasons are marked as retryable by Wrap.
+var Retry = map[string]bool{
+	"Conflict":           true,
+	"TooManyRequests":    true,
+	"ServiceUnavailable": true,
+	"Timeout":            true,
+	"ServerTimeout":      true,
+}
+
+// Wrap annotates err with the guru code for its status reason:
+//
+//	pod, err := client.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+//	if err != nil {
+//		return guruk8s.Wrap(err) // guru.Code(err) == 404 for NotFound
+//	}
+//
+// Reasons that aren't in t or DefaultTable use the HTTP code of the status, if
+// there is one.
+//
+// err is returned as-is if it's nil, if it's not a Kubernetes API error, or if
+// there's no code for it.
+func (t Table) Wrap(err error) error {
+	reason, status, ok := apiStatus(err)
+	if !ok {
+		return err
+	}
+
+	code, ok := t[reason]
+	if !ok {
+		code, ok = DefaultTable[reason]
+	}
+	switch {
+	case ok:
+	case status > 0:
+		code = int(status)
+	default:
+		return err
+	}
+
+	err = guru.WithCode(code, err)
+	if Retry[reason] {
+		err = guru.MarkRetryable(err)
+	}
+	return err
+}
+
+// Wrap is like Table.Wrap, using DefaultTable.
+func Wrap(err error) error { return DefaultTable.Wrap(err) }
+
+// ReasonOf gets the status reason of the first Kubernetes API error in the
+// chain, such as "NotFound". It will return an empty string if there's no API
+// error or if the reason is unknown.
+func ReasonOf(err error) string {
+	reason, _, _ := apiStatus(err)
+	return reason
+}
+
+// reasons maps HTTP status codes to the reasons the API server uses.
+var reasons = map[int]string{
+	400: "BadRequest",
+	401: "Unauthorized",
+	403: "Forbidden",
+	404: "NotFound",
+	405: "MethodNotAllowed",
+	406: "NotAcceptable",
+	409: "Conflict",
+	410: "Gone",
+	413: "RequestEntityTooLarge",
+	415: "UnsupportedMediaType",
+	422: "Invalid",
+	429: "TooManyRequests",
+	500: "InternalError",
+	503: "ServiceUnavailable",
+	504: "Timeout",
+}
+
+// Status gets the status reason and HTTP code to report err with, for example
+// from an aggregated API server or admission webhook:
+//
+//	reason, code := guruk8s.Status(err)
+//	return &apierrors.StatusError{ErrStatus: metav1.Status{
+//		Status:  metav1.StatusFailure,
+//		Reason:  metav1.StatusReason(reason),
+//		Code:    code,
+//		Message: guru.Public(err),
+//	}}
+//
+// The reason of the Kubernetes API error in the chain is used if there is one.
+// Otherwise the code is from guru.HTTPStatus, and the reason is the one the API
+// server uses for that code, or an empty string (StatusReasonUnknown) if there
+// isn't one. It will return "" and 200 if err is nil.
+func Status(err error) (reason string, code int32) {
+	if reason, status, ok := apiStatus(err); ok && reason != "" {
+		if status == 0 {
+			status = int32(guru.HTTPStatus(err))
+		}
+		return reason, status
+	}
+	status := guru.HTTPStatus(err)
+	return reasons[status], int32(status)
+}
+
+// apiStatus gets the Reason and Code from the Status() method of the first
+// errors.APIStatus in the chain. This returns a metav1.Status, so it needs
+// reflection.
+func apiStatus(err error) (reason string, code int32, found bool) {
+	guru.Walk(err, func(err error) bool {
+		m := reflect.ValueOf(err).MethodByName("Status")
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.Struct {
+			return true
+		}
+		st := m.Call(nil)[0]
+		r, c := st.FieldByName("Reason"), st.FieldByName("Code")
+		if !r.IsValid() || r.Kind() != reflect.String || !c.IsValid() || c.Kind() != reflect.Int32 {
+			return true
+		}
+		reason, code, found = r.String(), int32(c.Int()), true
+		return false
+	})
+	return reason, code, found
+}
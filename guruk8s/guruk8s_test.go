@@ -0,0 +1,91 @@
+package guruk8s
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+// Same shape as apierrors.StatusError.
+type (
+	statusReason string
+	status       struct {
+		Status  string
+		Message string
+		Reason  statusReason
+		Code    int32
+	}
+	statusError struct{ ErrStatus status }
+)
+
+func (e *statusError) Error() string  { return e.ErrStatus.Message }
+func (e *statusError) Status() status { return e.ErrStatus }
+
+func apiErr(reason string, code int32) error {
+	return &statusError{status{Status: "Failure", Message: "msg", Reason: statusReason(reason), Code: code}}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in         error
+		table      Table
+		wantReason string
+		wantCode   int
+		wantRetry  bool
+	}{
+		{nil, nil, "", 0, false},
+		{errors.New("x"), nil, "", 0, false},
+		{apiErr("NotFound", 404), nil, "NotFound", 404, false},
+		{apiErr("Conflict", 409), nil, "Conflict", 409, true},
+		{apiErr("AlreadyExists", 409), nil, "AlreadyExists", 409, false},
+		{apiErr("ServerTimeout", 500), nil, "ServerTimeout", 504, true},
+		{apiErr("", 418), nil, "", 418, false},
+		{apiErr("", 0), nil, "", 0, false},
+		{fmt.Errorf("get pod: %w", apiErr("Forbidden", 403)), nil, "Forbidden", 403, false},
+		{apiErr("NotFound", 404), Table{"NotFound": 4012}, "NotFound", 4012, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if r := ReasonOf(tt.in); r != tt.wantReason {
+				t.Errorf("reason\nout:  %q\nwant: %q\n", r, tt.wantReason)
+			}
+
+			out := tt.table.Wrap(tt.in)
+			if c := guru.Code(out); c != tt.wantCode {
+				t.Errorf("code\nout:  %d\nwant: %d\n", c, tt.wantCode)
+			}
+			if r := guru.Retryable(out); r != tt.wantRetry {
+				t.Errorf("retryable\nout:  %t\nwant: %t\n", r, tt.wantRetry)
+			}
+			if tt.wantCode == 0 && out != tt.in {
+				t.Error("not returned as-is")
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		in         error
+		wantReason string
+		wantCode   int32
+	}{
+		{nil, "", 200},
+		{errors.New("x"), "InternalError", 500},
+		{guru.New(404, "x"), "NotFound", 404},
+		{guru.New(4012, "x"), "InternalError", 500},
+		{guru.New(418, "x"), "", 418},
+		{guru.Wrap(500, apiErr("AlreadyExists", 409), "create"), "AlreadyExists", 409},
+		{apiErr("Conflict", 0), "Conflict", 500},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			r, c := Status(tt.in)
+			if r != tt.wantReason || c != tt.wantCode {
+				t.Errorf("\nout:  %q %d\nwant: %q %d\n", r, c, tt.wantReason, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package gurumetrics counts errors by code with Prometheus.
+package gurumetrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"zgo.at/guru"
+)
+
+// Options for NewCounter.
+type Options struct {
+	// Metric name; the default is "guru_errors_total".
+	Namespace, Subsystem, Name string
+
+	// Help text; the default is "Number of errors, by error code."
+	Help string
+
+	// Add a "category" label with guru.Category().
+	Category bool
+
+	// Add a "severity" label with guru.Severity().
+	Severity bool
+
+	// Registry to use for the category and severity; the default is the
+	// registry of the error (see guru.RegistryOf).
+	Registry *guru.Registry
+}
+
+// Counter counts errors with a CounterVec, labelled by the error code. Errors
+// without a code have an empty code label.
+//
+// It implements prometheus.Collector, so it needs to be registered:
+//
+//	errCount := gurumetrics.NewCounter(gurumetrics.Options{Category: true})
+//	prometheus.MustRegister(errCount)
+//
+// Errors can be counted as they're created with guru.AddHook, or explicitly
+// with Report:
+//
+//	guru.AddHook(errCount.Hook)
+//	errCount.Report(err)
+type Counter struct {
+	vec  *prometheus.CounterVec
+	opts Options
+}
+
+var _ prometheus.Collector = &Counter{}
+
+// NewCounter creates a new counter.
+func NewCounter(opts Options) *Counter {
+	if opts.Name == "" {
+		opts.Name = "guru_errors_total"
+	}
+	if opts.Help == "" {
+		opts.Help = "Number of errors, by error code."
+	}
+
+	labels := []string{"code"}
+	if opts.Category {
+		labels = append(labels, "category")
+	}
+	if opts.Severity {
+		labels = append(labels, "severity")
+	}
+	return &Counter{
+		opts: opts,
+		vec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Subsystem: opts.Subsystem,
+			Name:      opts.Name,
+			Help:      opts.Help,
+		}, labels),
+	}
+}
+
+// Report increments the counter for err. It does nothing if err is nil.
+func (c *Counter) Report(err error) {
+	if err == nil {
+		return
+	}
+	c.vec.WithLabelValues(c.labels(err)...).Inc()
+}
+
+// Hook increments the counter for the error in the event; use it with
+// guru.AddHook.
+//
+// Wrapping an error that already has a code isn't counted, so that an error
+// is only counted once when it's created and then wrapped.
+func (c *Counter) Hook(e guru.Event) {
+	if e.Kind == guru.KindWrap && len(guru.Codes(e.Cause)) > 0 {
+		return
+	}
+	c.Report(e.Err)
+}
+
+func (c *Counter) labels(err error) []string {
+	code := ""
+	if len(guru.Codes(err)) > 0 {
+		code = strconv.Itoa(guru.Code(err))
+	}
+	l := []string{code}
+	reg := c.opts.Registry
+	if reg == nil {
+		reg = guru.RegistryOf(err)
+	}
+	if c.opts.Category {
+		l = append(l, reg.Category(err))
+	}
+	if c.opts.Severity {
+		l = append(l, reg.Severity(err).String())
+	}
+	return l
+}
+
+// Describe implements prometheus.Collector.
+func (c *Counter) Describe(ch chan<- *prometheus.Desc) { c.vec.Describe(ch) }
+
+// Collect implements prometheus.Collector.
+func (c *Counter) Collect(ch chan<- prometheus.Metric) { c.vec.Collect(ch) }
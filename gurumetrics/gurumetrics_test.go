@@ -0,0 +1,91 @@
+package gurumetrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"zgo.at/guru"
+)
+
+func TestCounter(t *testing.T) {
+	reg := &guru.Registry{}
+	reg.RegisterCategory(4000, 4999, "billing")
+	reg.RegisterSeverity(4012, guru.LevelWarn)
+
+	c := NewCounter(Options{Namespace: "app", Category: true, Severity: true, Registry: reg})
+	pr := prometheus.NewPedanticRegistry()
+	pr.MustRegister(c)
+
+	c.Report(guru.New(4012, "x"))
+	c.Report(guru.Wrap(4012, errors.New("x"), "y"))
+	c.Report(guru.New(1, "x"))
+	c.Report(errors.New("x"))
+	c.Report(nil)
+
+	want := `
+# HELP app_guru_errors_total Number of errors, by error code.
+# TYPE app_guru_errors_total counter
+app_guru_errors_total{category="",code="",severity="error"} 1
+app_guru_errors_total{category="",code="1",severity="error"} 1
+app_guru_errors_total{category="billing",code="4012",severity="warn"} 2
+`
+	if err := testutil.GatherAndCompare(pr, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCounterScoped(t *testing.T) {
+	reg := &guru.Registry{}
+	reg.RegisterCategory(4000, 4999, "billing")
+
+	c := NewCounter(Options{Category: true})
+	c.Report(reg.New(4012, "x"))
+	c.Report(guru.New(4012, "x"))
+
+	want := `
+# HELP guru_errors_total Number of errors, by error code.
+# TYPE guru_errors_total counter
+guru_errors_total{category="",code="4012"} 1
+guru_errors_total{category="billing",code="4012"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCounterDefault(t *testing.T) {
+	c := NewCounter(Options{})
+	c.Report(guru.New(1, "x"))
+
+	want := `
+# HELP guru_errors_total Number of errors, by error code.
+# TYPE guru_errors_total counter
+guru_errors_total{code="1"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHook(t *testing.T) {
+	c := NewCounter(Options{})
+	remove := guru.AddHook(c.Hook)
+	defer remove()
+
+	err := guru.New(1, "x")
+	guru.Wrap(2, err, "y")
+	guru.Wrap(3, errors.New("x"), "y")
+
+	want := `
+# HELP guru_errors_total Number of errors, by error code.
+# TYPE guru_errors_total counter
+guru_errors_total{code="1"} 1
+guru_errors_total{code="3"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package guruotel records guru errors on OpenTelemetry spans.
+package guruotel
+
+import (
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"zgo.at/guru"
+)
+
+// Record records err on span with span.RecordError, adding the attributes
+// from Attributes, and sets the status of the span. It does nothing if err is
+// nil.
+//
+// The status is set to Error for errors with a HTTP status of 500 or higher
+// (see guru.HTTPStatus), which includes all errors without a code. Client
+// errors (4xx) don't change the status, as recommended by the semantic
+// conventions for server spans.
+func Record(span trace.Span, err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+	opts = append(opts, trace.WithAttributes(Attributes(err)...))
+	span.RecordError(err, opts...)
+	if guru.HTTPStatus(err) >= 500 {
+		span.SetStatus(codes.Error, fmt.Sprintf("%s", err))
+	}
+}
+
+// Attributes gets the attributes for err:
+//
+//	guru.code       guru.Code(), if there is a code.
+//	guru.subcode    guru.Subcode(), if it's not 0.
+//	guru.category   guru.Category(), if it's not empty.
+//	guru.request_id guru.RequestID(), if it's not empty.
+//	guru.field.*    Every field in guru.Fields().
+//
+// It will return nil if err is nil.
+func Attributes(err error) []attribute.KeyValue {
+	if err == nil {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	if len(guru.Codes(err)) > 0 {
+		attrs = append(attrs, attribute.Int("guru.code", guru.Code(err)))
+		if sub := guru.Subcode(err); sub != 0 {
+			attrs = append(attrs, attribute.Int("guru.subcode", sub))
+		}
+	}
+	if cat := guru.Category(err); cat != "" {
+		attrs = append(attrs, attribute.String("guru.category", cat))
+	}
+	if id := guru.RequestID(err); id != "" {
+		attrs = append(attrs, attribute.String("guru.request_id", id))
+	}
+
+	f := guru.Fields(err)
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, attr("guru.field."+k, f[k]))
+	}
+	return attrs
+}
+
+func attr(k string, v interface{}) attribute.KeyValue {
+	switch vv := v.(type) {
+	case string:
+		return attribute.String(k, vv)
+	case bool:
+		return attribute.Bool(k, vv)
+	case int:
+		return attribute.Int(k, vv)
+	case int64:
+		return attribute.Int64(k, vv)
+	case float64:
+		return attribute.Float64(k, vv)
+	case []string:
+		return attribute.StringSlice(k, vv)
+	case fmt.Stringer:
+		return attribute.Stringer(k, vv)
+	}
+	return attribute.String(k, fmt.Sprint(v))
+}
@@ -0,0 +1,87 @@
+package guruotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"zgo.at/guru"
+)
+
+func TestRecord(t *testing.T) {
+	reg := guru.DefaultRegistry
+	guru.DefaultRegistry = &guru.Registry{}
+	t.Cleanup(func() { guru.DefaultRegistry = reg })
+	guru.RegisterCategory(4000, 4999, "billing")
+	guru.RegisterHTTPStatus(4012, 404)
+
+	tests := []struct {
+		in         error
+		wantAttrs  []attribute.KeyValue
+		wantStatus codes.Code
+	}{
+		{errors.New("oh noes"), nil, codes.Error},
+		{guru.New(4012, "x"), []attribute.KeyValue{
+			attribute.Int("guru.code", 4012),
+			attribute.String("guru.category", "billing"),
+		}, codes.Unset},
+		{guru.WithRequestID(guru.New(4012, "x"), "abc"), []attribute.KeyValue{
+			attribute.Int("guru.code", 4012),
+			attribute.String("guru.category", "billing"),
+			attribute.String("guru.request_id", "abc"),
+		}, codes.Unset},
+		{guru.WithFields(guru.NewSub(500, 2, "x"), map[string]interface{}{
+			"s": "str", "i": 1, "b": true, "o": []int{1}, "g": guru.Secret("x"),
+		}), []attribute.KeyValue{
+			attribute.Int("guru.code", 500),
+			attribute.Int("guru.subcode", 2),
+			attribute.Bool("guru.field.b", true),
+			attribute.String("guru.field.g", "[REDACTED]"),
+			attribute.Int("guru.field.i", 1),
+			attribute.String("guru.field.o", "[1]"),
+			attribute.String("guru.field.s", "str"),
+		}, codes.Error},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			rec := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+			_, span := tp.Tracer("test").Start(context.Background(), "span")
+			Record(span, tt.in)
+			Record(span, nil)
+			span.End()
+
+			spans := rec.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("%d spans", len(spans))
+			}
+			s := spans[0]
+			if s.Status().Code != tt.wantStatus {
+				t.Errorf("status: %v", s.Status())
+			}
+			if len(s.Events()) != 1 {
+				t.Fatalf("%d events", len(s.Events()))
+			}
+
+			var attrs []attribute.KeyValue
+			for _, a := range s.Events()[0].Attributes {
+				if a.Key != "exception.type" && a.Key != "exception.message" {
+					attrs = append(attrs, a)
+				}
+			}
+			if !reflect.DeepEqual(attrs, tt.wantAttrs) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", attrs, tt.wantAttrs)
+			}
+		})
+	}
+
+	if Attributes(nil) != nil {
+		t.Error("not nil")
+	}
+}
@@ -0,0 +1,15 @@
+module zgo.at/guru/gurusentry
+
+go 1.23.0
+
+require (
+	github.com/getsentry/sentry-go v0.42.0
+	zgo.at/guru v0.0.0
+)
+
+require (
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
+
+replace zgo.at/guru => ../
@@ -0,0 +1,90 @@
+// Package gurusentry converts guru errors to Sentry events.
+package gurusentry
+
+import (
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"zgo.at/guru"
+)
+
+// Event converts err to a Sentry event. It will return nil if err is nil.
+//
+// The event has:
+//
+//   - The code in the guru.code tag, the category in guru.category, and
+//     guru.RequestID() in guru.request_id.
+//   - The fields in the "guru.fields" context.
+//   - guru.Fingerprint() as the fingerprint, so events are grouped by the
+//     codes and messages rather than the stack trace.
+//   - The level from guru.Severity().
+//   - An exception with the stack trace from guru.StackTrace(), if there is
+//     one. The exception type is the registered name for the code, or "error
+//     N" if there is none.
+func Event(err error) *sentry.Event {
+	if err == nil {
+		return nil
+	}
+
+	ev := sentry.NewEvent()
+	ev.Level = level(guru.Severity(err))
+	ev.Message = fmt.Sprintf("%s", err)
+	ev.Fingerprint = []string{guru.Fingerprint(err)}
+
+	typ := fmt.Sprintf("%T", err)
+	if len(guru.Codes(err)) > 0 {
+		code := guru.Code(err)
+		ev.Tags["guru.code"] = guru.FormatCode(code)
+		if cat := guru.Category(err); cat != "" {
+			ev.Tags["guru.category"] = cat
+		}
+		typ = guru.Name(code)
+		if typ == "" {
+			typ = "error " + guru.FormatCode(code)
+		}
+	}
+	if id := guru.RequestID(err); id != "" {
+		ev.Tags["guru.request_id"] = id
+	}
+	if f := guru.Fields(err); len(f) > 0 {
+		ev.Contexts["guru.fields"] = f
+	}
+
+	exc := sentry.Exception{Type: typ, Value: ev.Message}
+	if st := guru.StackTrace(err); len(st) > 0 {
+		// Sentry wants the frames with the oldest call first.
+		frames := make([]sentry.Frame, 0, len(st))
+		for i := len(st) - 1; i >= 0; i-- {
+			frames = append(frames, sentry.NewFrame(st[i]))
+		}
+		exc.Stacktrace = &sentry.Stacktrace{Frames: frames}
+	}
+	ev.Exception = []sentry.Exception{exc}
+	return ev
+}
+
+// Capture sends err to Sentry with the hub, or sentry.CurrentHub() if hub is
+// nil. It does nothing if err is nil.
+func Capture(hub *sentry.Hub, err error) *sentry.EventID {
+	if err == nil {
+		return nil
+	}
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return hub.CaptureEvent(Event(err))
+}
+
+func level(l guru.Level) sentry.Level {
+	switch l {
+	case guru.LevelDebug:
+		return sentry.LevelDebug
+	case guru.LevelInfo:
+		return sentry.LevelInfo
+	case guru.LevelWarn:
+		return sentry.LevelWarning
+	case guru.LevelFatal:
+		return sentry.LevelFatal
+	}
+	return sentry.LevelError
+}
@@ -0,0 +1,102 @@
+package gurusentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"zgo.at/guru"
+)
+
+func TestEvent(t *testing.T) {
+	reg := guru.DefaultRegistry
+	guru.DefaultRegistry = &guru.Registry{}
+	t.Cleanup(func() { guru.DefaultRegistry = reg })
+	guru.Register(4012, "ErrInvoiceMissing", "")
+	guru.RegisterCategory(4000, 4999, "billing")
+
+	tests := []struct {
+		in        error
+		wantTags  map[string]string
+		wantType  string
+		wantLevel sentry.Level
+	}{
+		{errors.New("oh noes"), map[string]string{}, "*errors.errorString", sentry.LevelError},
+		{guru.New(42, "oh noes"), map[string]string{"guru.code": "42"}, "error 42", sentry.LevelError},
+		{guru.WithSeverity(guru.New(4012, "oh noes"), guru.LevelWarn),
+			map[string]string{"guru.code": "4012", "guru.category": "billing"}, "ErrInvoiceMissing", sentry.LevelWarning},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			ev := Event(tt.in)
+			if !reflect.DeepEqual(ev.Tags, tt.wantTags) {
+				t.Errorf("tags\nout:  %#v\nwant: %#v\n", ev.Tags, tt.wantTags)
+			}
+			if len(ev.Exception) != 1 || ev.Exception[0].Type != tt.wantType || ev.Exception[0].Value != "oh noes" {
+				t.Errorf("exception: %#v", ev.Exception)
+			}
+			if ev.Level != tt.wantLevel {
+				t.Errorf("level: %v", ev.Level)
+			}
+			if len(ev.Fingerprint) != 1 || ev.Fingerprint[0] != guru.Fingerprint(tt.in) {
+				t.Errorf("fingerprint: %v", ev.Fingerprint)
+			}
+			if ev.Exception[0].Stacktrace != nil {
+				t.Error("has stack trace")
+			}
+		})
+	}
+
+	ev := Event(guru.WithRequestID(guru.New(42, "oh noes"), "abc"))
+	if ev.Tags["guru.request_id"] != "abc" || ev.Message != "oh noes (request_id: abc)" {
+		t.Errorf("request ID: %#v %q", ev.Tags, ev.Message)
+	}
+
+	ev = Event(guru.WithFields(guru.NewStack(1, "x"), map[string]interface{}{"a": 1}))
+	if !reflect.DeepEqual(ev.Contexts["guru.fields"], sentry.Context{"a": 1}) {
+		t.Errorf("contexts: %#v", ev.Contexts)
+	}
+	st := ev.Exception[0].Stacktrace
+	if st == nil || len(st.Frames) == 0 || !strings.HasSuffix(st.Frames[len(st.Frames)-1].Function, "TestEvent") {
+		t.Errorf("stack: %#v", st)
+	}
+
+	if Event(nil) != nil || Capture(nil, nil) != nil {
+		t.Error("not nil")
+	}
+}
+
+func TestEventCodeFormat(t *testing.T) {
+	t.Cleanup(func() { guru.SetCodeFormat(guru.CodeFormat{}) })
+	guru.SetCodeFormat(guru.CodeFormat{Base: 16})
+
+	ev := Event(guru.New(42, "oh noes"))
+	if ev.Tags["guru.code"] != "0x2A" || ev.Exception[0].Type != "error 0x2A" {
+		t.Errorf("\nout:  %#v %q", ev.Tags, ev.Exception[0].Type)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	tr := &transport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: tr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Capture(sentry.NewHub(client, sentry.NewScope()), guru.New(42, "oh noes"))
+	if len(tr.events) != 1 || tr.events[0].Tags["guru.code"] != "42" {
+		t.Errorf("%#v", tr.events)
+	}
+}
+
+type transport struct{ events []*sentry.Event }
+
+func (t *transport) Flush(time.Duration) bool              { return true }
+func (t *transport) FlushWithContext(context.Context) bool { return true }
+func (t *transport) Configure(sentry.ClientOptions)        {}
+func (t *transport) SendEvent(e *sentry.Event)             { t.events = append(t.events, e) }
+func (t *transport) Close()                                {}
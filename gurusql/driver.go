@@ -0,0 +1,117 @@
+package gurusql
+
+import (
+	"reflect"
+	"strings"
+)
+
+// driverClass gets the class of an error from one of the drivers, without
+// unwrapping it.
+func driverClass(err error) Class {
+	// lib/pq and pgx.
+	if s, ok := err.(interface{ SQLState() string }); ok {
+		return postgresClass(s.SQLState())
+	}
+
+	// The MySQL and SQLite drivers don't have methods to get the error code, so
+	// look at the struct fields.
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return Unknown
+	}
+
+	// go-sql-driver/mysql: MySQLError{Number uint16; SQLState [5]byte; ...}
+	if n, ok := field(v, "Number", reflect.Uint16); ok {
+		if _, ok := field(v, "SQLState", reflect.Array); ok {
+			return mysqlClass(n.Uint())
+		}
+	}
+
+	// mattn/go-sqlite3: Error{Code ErrNo; ExtendedCode ErrNoExtended; ...}
+	if c, ok := field(v, "Code", reflect.Int); ok {
+		if x, ok := field(v, "ExtendedCode", reflect.Int); ok {
+			return sqliteClass(c.Int(), x.Int())
+		}
+	}
+	return Unknown
+}
+
+func field(v reflect.Value, name string, kind reflect.Kind) (reflect.Value, bool) {
+	f := v.FieldByName(name)
+	return f, f.IsValid() && f.Kind() == kind
+}
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+func postgresClass(state string) Class {
+	switch state {
+	case "23505":
+		return UniqueViolation
+	case "23503":
+		return ForeignKeyViolation
+	case "23502":
+		return NotNullViolation
+	case "23514":
+		return CheckViolation
+	case "40001":
+		return SerializationFailure
+	case "40P01":
+		return Deadlock
+	case "55P03":
+		return LockTimeout
+	case "57014":
+		return Canceled
+	case "57P01", "57P02", "57P03":
+		return ConnectionLost
+	}
+	if strings.HasPrefix(state, "08") {
+		return ConnectionLost
+	}
+	return Unknown
+}
+
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+func mysqlClass(n uint64) Class {
+	switch n {
+	case 1062, 1586:
+		return UniqueViolation
+	case 1216, 1217, 1451, 1452:
+		return ForeignKeyViolation
+	case 1048:
+		return NotNullViolation
+	case 3819:
+		return CheckViolation
+	case 1213:
+		return Deadlock
+	case 1205:
+		return LockTimeout
+	case 1317, 3024:
+		return Canceled
+	case 2006, 2013:
+		return ConnectionLost
+	}
+	return Unknown
+}
+
+// https://www.sqlite.org/rescode.html
+func sqliteClass(code, extended int64) Class {
+	switch extended {
+	case 1555, 2067: // SQLITE_CONSTRAINT_PRIMARYKEY, SQLITE_CONSTRAINT_UNIQUE
+		return UniqueViolation
+	case 787: // SQLITE_CONSTRAINT_FOREIGNKEY
+		return ForeignKeyViolation
+	case 1299: // SQLITE_CONSTRAINT_NOTNULL
+		return NotNullViolation
+	case 275: // SQLITE_CONSTRAINT_CHECK
+		return CheckViolation
+	}
+	switch code {
+	case 5, 6: // SQLITE_BUSY, SQLITE_LOCKED
+		return LockTimeout
+	case 9: // SQLITE_INTERRUPT
+		return Canceled
+	}
+	return Unknown
+}
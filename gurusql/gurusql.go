@@ -0,0 +1,135 @@
+// Package gurusql assigns guru codes to database errors.
+//
+// Errors from the Postgres (lib/pq, pgx), MySQL (go-sql-driver/mysql), and
+// SQLite (mattn/go-sqlite3) drivers are recognized without importing them, as
+// well as sql.ErrNoRows and driver.ErrBadConn.
+package gurusql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"zgo.at/guru"
+)
+
+// Class is a driver-independent class of database error.
+type Class int
+
+// Classes of database errors.
+const (
+	Unknown              Class = iota
+	NoRows                     // sql.ErrNoRows.
+	UniqueViolation            // Duplicate key.
+	ForeignKeyViolation        // Missing or referenced row.
+	NotNullViolation           // NULL in a NOT NULL column.
+	CheckViolation             // CHECK constraint failed.
+	SerializationFailure       // Transaction couldn't be serialized; retryable.
+	Deadlock                   // Deadlock detected; retryable.
+	LockTimeout                // Lock not available or database busy; retryable.
+	ConnectionLost             // Connection failed or was closed; retryable.
+	Canceled                   // Query was canceled or timed out.
+)
+
+var names = map[Class]string{
+	Unknown:              "Unknown",
+	NoRows:               "NoRows",
+	UniqueViolation:      "UniqueViolation",
+	ForeignKeyViolation:  "ForeignKeyViolation",
+	NotNullViolation:     "NotNullViolation",
+	CheckViolation:       "CheckViolation",
+	SerializationFailure: "SerializationFailure",
+	Deadlock:             "Deadlock",
+	LockTimeout:          "LockTimeout",
+	ConnectionLost:       "ConnectionLost",
+	Canceled:             "Canceled",
+}
+
+func (c Class) String() string {
+	if n, ok := names[c]; ok {
+		return n
+	}
+	return "Unknown"
+}
+
+// Retryable reports if the operation can be retried for this class of error.
+func (c Class) Retryable() bool {
+	switch c {
+	case SerializationFailure, Deadlock, LockTimeout, ConnectionLost:
+		return true
+	}
+	return false
+}
+
+// Table maps classes to guru codes.
+type Table map[Class]int
+
+// DefaultTable is the table used by the Wrap function, and for classes that
+// aren't in a Table.
+var DefaultTable = Table{
+	NoRows:               404,
+	UniqueViolation:      409,
+	ForeignKeyViolation:  409,
+	NotNullViolation:     400,
+	CheckViolation:       400,
+	SerializationFailure: 409,
+	Deadlock:             409,
+	LockTimeout:          503,
+	ConnectionLost:       503,
+	Canceled:             499,
+}
+
+// Wrap annotates err with the code for its class, and marks it as retryable if
+// the class is retryable:
+//
+//	_, err := db.ExecContext(ctx, `insert into users (email) values ($1)`, email)
+//	if err != nil {
+//		return gurusql.Wrap(err) // guru.Code(err) == 409
+//	}
+//
+// err is returned as-is if it's nil or if the class is Unknown.
+func (t Table) Wrap(err error) error {
+	c := ClassOf(err)
+	if c == Unknown {
+		return err
+	}
+	code, ok := t[c]
+	if !ok {
+		code, ok = DefaultTable[c]
+		if !ok {
+			return err
+		}
+	}
+	err = guru.WithCode(code, err)
+	if c.Retryable() {
+		err = guru.MarkRetryable(err)
+	}
+	return err
+}
+
+// Wrap is like Table.Wrap, using DefaultTable.
+func Wrap(err error) error { return DefaultTable.Wrap(err) }
+
+// ClassOf gets the class of err, or Unknown if it's not a recognized database
+// error. The first error in the chain that's recognized is used.
+func ClassOf(err error) Class {
+	switch {
+	case err == nil:
+		return Unknown
+	case errors.Is(err, sql.ErrNoRows):
+		return NoRows
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return ConnectionLost
+	}
+
+	c := Unknown
+	guru.Walk(err, func(err error) bool {
+		c = driverClass(err)
+		return c == Unknown
+	})
+	if c == Unknown && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return Canceled
+	}
+	return c
+}
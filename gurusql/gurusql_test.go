@@ -0,0 +1,87 @@
+package gurusql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+// Same shape as the driver errors.
+type (
+	pgError    struct{ Code string }
+	mysqlError struct {
+		Number   uint16
+		SQLState [5]byte
+		Message  string
+	}
+	sqliteErrNo    int
+	sqliteExtended int
+	sqliteError    struct {
+		Code         sqliteErrNo
+		ExtendedCode sqliteExtended
+	}
+)
+
+func (e *pgError) Error() string    { return "pq: " + e.Code }
+func (e *pgError) SQLState() string { return e.Code }
+func (e *mysqlError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+func (e sqliteError) Error() string { return fmt.Sprintf("sqlite %d", e.ExtendedCode) }
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in        error
+		table     Table
+		wantClass Class
+		wantCode  int
+		wantRetry bool
+	}{
+		{nil, nil, Unknown, 0, false},
+		{errors.New("x"), nil, Unknown, 0, false},
+		{sql.ErrNoRows, nil, NoRows, 404, false},
+		{fmt.Errorf("query: %w", driver.ErrBadConn), nil, ConnectionLost, 503, true},
+		{context.Canceled, nil, Canceled, 499, false},
+
+		{&pgError{"23505"}, nil, UniqueViolation, 409, false},
+		{&pgError{"40001"}, nil, SerializationFailure, 409, true},
+		{&pgError{"08006"}, nil, ConnectionLost, 503, true},
+		{&pgError{"42P01"}, nil, Unknown, 0, false},
+		{fmt.Errorf("insert: %w", &pgError{"23503"}), nil, ForeignKeyViolation, 409, false},
+
+		{&mysqlError{Number: 1062}, nil, UniqueViolation, 409, false},
+		{&mysqlError{Number: 1213}, nil, Deadlock, 409, true},
+		{&mysqlError{Number: 1146}, nil, Unknown, 0, false},
+
+		{sqliteError{Code: 19, ExtendedCode: 2067}, nil, UniqueViolation, 409, false},
+		{sqliteError{Code: 19, ExtendedCode: 1299}, nil, NotNullViolation, 400, false},
+		{sqliteError{Code: 5, ExtendedCode: 261}, nil, LockTimeout, 503, true},
+
+		{&pgError{"23505"}, Table{UniqueViolation: 4012}, UniqueViolation, 4012, false},
+		{&pgError{"23514"}, Table{UniqueViolation: 4012}, CheckViolation, 400, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if c := ClassOf(tt.in); c != tt.wantClass {
+				t.Errorf("class\nout:  %s\nwant: %s\n", c, tt.wantClass)
+			}
+
+			out := tt.table.Wrap(tt.in)
+			if c := guru.Code(out); c != tt.wantCode {
+				t.Errorf("code\nout:  %d\nwant: %d\n", c, tt.wantCode)
+			}
+			if r := guru.Retryable(out); r != tt.wantRetry {
+				t.Errorf("retryable\nout:  %t\nwant: %t\n", r, tt.wantRetry)
+			}
+			if tt.wantClass == Unknown && out != tt.in {
+				t.Error("not returned as-is")
+			}
+			if tt.in != nil && !errors.Is(out, tt.in) {
+				t.Error("errors.Is false")
+			}
+		})
+	}
+}
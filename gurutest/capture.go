@@ -0,0 +1,101 @@
+package gurutest
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+// Recorder records the errors created with the guru package; see Capture.
+type Recorder struct {
+	mu     sync.Mutex
+	events []guru.Event
+}
+
+// Capture records every error created or wrapped with a code (as reported to
+// hooks added with guru.AddHook) until the end of the test. This includes
+// errors that are discarded by the code under test:
+//
+//	rec := gurutest.Capture(t)
+//	importFile("testdata/invalid.csv")
+//	if n := rec.Count(4012); n != 2 {
+//		t.Errorf("want two invalid rows, got %d", n)
+//	}
+//
+// Hooks are global, so errors created in other goroutines (including parallel
+// tests) are recorded too.
+func Capture(t testing.TB) *Recorder {
+	r := new(Recorder)
+	t.Cleanup(guru.AddHook(r.Record))
+	return r
+}
+
+// Record records ev.
+func (r *Recorder) Record(ev guru.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+// Reset removes all recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// Events gets all recorded events, in the order the errors were created.
+func (r *Recorder) Events() []guru.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]guru.Event(nil), r.events...)
+}
+
+// Errors gets all recorded errors.
+func (r *Recorder) Errors() []error {
+	ev := r.Events()
+	errs := make([]error, 0, len(ev))
+	for _, e := range ev {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// Codes gets the codes of all recorded errors.
+func (r *Recorder) Codes() []int {
+	ev := r.Events()
+	codes := make([]int, 0, len(ev))
+	for _, e := range ev {
+		codes = append(codes, e.Code)
+	}
+	return codes
+}
+
+// Find gets the events for errors with the code code.
+func (r *Recorder) Find(code int) []guru.Event {
+	return r.filter(func(ev guru.Event) bool { return ev.Code == code })
+}
+
+// Count gets the number of errors with the code code.
+func (r *Recorder) Count(code int) int { return len(r.Find(code)) }
+
+// WithField gets the events for errors that had the field key set to value
+// when they were created.
+func (r *Recorder) WithField(key string, value interface{}) []guru.Event {
+	return r.filter(func(ev guru.Event) bool {
+		v, ok := ev.Fields[key]
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+func (r *Recorder) filter(fn func(guru.Event) bool) []guru.Event {
+	var found []guru.Event
+	for _, ev := range r.Events() {
+		if fn(ev) {
+			found = append(found, ev)
+		}
+	}
+	return found
+}
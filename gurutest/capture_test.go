@@ -0,0 +1,49 @@
+package gurutest
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+func TestCapture(t *testing.T) {
+	var rec *Recorder
+	t.Run("", func(t *testing.T) {
+		rec = Capture(t)
+
+		_ = guru.New(4012, "x")
+		_ = guru.Wrap(500, guru.WithFields(io.EOF, map[string]interface{}{"id": 3}), "y")
+		_ = guru.WithCode(4012, io.EOF)
+		_ = guru.WithField(guru.New(1, "z"), "id", 3) // Fields added after creation.
+	})
+	_ = guru.New(2, "after the test")
+
+	if out, want := rec.Codes(), []int{4012, 500, 4012, 1}; !reflect.DeepEqual(out, want) {
+		t.Errorf("Codes\nout:  %#v\nwant: %#v\n", out, want)
+	}
+	if out := rec.Count(4012); out != 2 {
+		t.Errorf("Count: %d", out)
+	}
+	if out := rec.Count(404); out != 0 {
+		t.Errorf("Count: %d", out)
+	}
+	if out := len(rec.Errors()); out != 4 {
+		t.Errorf("Errors: %d", out)
+	}
+
+	f := rec.WithField("id", 3)
+	if len(f) != 1 || f[0].Code != 500 || fmt.Sprint(f[0].Err) != "error 500: EOF: y" {
+		t.Errorf("WithField: %#v", f)
+	}
+	if f := rec.WithField("id", "3"); f != nil {
+		t.Errorf("WithField: %#v", f)
+	}
+
+	rec.Reset()
+	if out := rec.Events(); len(out) != 0 {
+		t.Errorf("Reset: %#v", out)
+	}
+}
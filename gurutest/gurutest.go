@@ -0,0 +1,88 @@
+// Package gurutest provides helpers to test errors with codes.
+package gurutest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+// AssertCode reports an error if the code of err (as returned by guru.Code) is
+// not code. The failure message includes the full chain of err with all the
+// codes. It reports whether the code matched.
+func AssertCode(t testing.TB, err error, code int) bool {
+	t.Helper()
+	if err != nil && guru.HasCode(err) && guru.Code(err) == code {
+		return true
+	}
+	t.Errorf("gurutest: wrong code\nhave: %s\nwant: %s%s", have(err), codeName(code), chain(err))
+	return false
+}
+
+// RequireCode is like AssertCode, but stops the test on failure.
+func RequireCode(t testing.TB, err error, code int) {
+	t.Helper()
+	if !AssertCode(t, err, code) {
+		t.FailNow()
+	}
+}
+
+// AssertHas reports an error if none of the errors in the chain of err have
+// the code code, as with guru.Has. It reports whether the code was found.
+func AssertHas(t testing.TB, err error, code int) bool {
+	t.Helper()
+	if guru.Has(err, code) {
+		return true
+	}
+	t.Errorf("gurutest: code not in chain\nhave: %s\nwant: %s%s", haveAll(err), codeName(code), chain(err))
+	return false
+}
+
+// RequireHas is like AssertHas, but stops the test on failure.
+func RequireHas(t testing.TB, err error, code int) {
+	t.Helper()
+	if !AssertHas(t, err, code) {
+		t.FailNow()
+	}
+}
+
+func have(err error) string {
+	switch {
+	case err == nil:
+		return "nil error"
+	case !guru.HasCode(err):
+		return "no code"
+	}
+	return codeName(guru.Code(err))
+}
+
+func haveAll(err error) string {
+	codes := guru.Codes(err)
+	switch {
+	case err == nil:
+		return "nil error"
+	case len(codes) == 0:
+		return "no code"
+	}
+	s := make([]string, 0, len(codes))
+	for _, c := range codes {
+		s = append(s, codeName(c))
+	}
+	return strings.Join(s, ", ")
+}
+
+func codeName(code int) string {
+	if n := guru.Name(code); n != "" {
+		return fmt.Sprintf("%d (%s)", code, n)
+	}
+	return fmt.Sprint(code)
+}
+
+func chain(err error) string {
+	if err == nil {
+		return ""
+	}
+	return "\nchain:\n    " + strings.ReplaceAll(guru.Tree(err), "\n", "\n    ")
+}
@@ -0,0 +1,92 @@
+package gurutest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+type fakeT struct {
+	testing.TB
+	msg    string
+	failed bool
+}
+
+func (t *fakeT) Helper()                           {}
+func (t *fakeT) FailNow()                          { t.failed = true }
+func (t *fakeT) Errorf(f string, a ...interface{}) { t.msg = fmt.Sprintf(f, a...) }
+
+func TestAssert(t *testing.T) {
+	err := guru.Wrap(4012, guru.WithCode(500, io.EOF), "charge card")
+
+	tests := []struct {
+		fn   func(testing.TB, error, int) bool
+		err  error
+		code int
+		want string
+	}{
+		{AssertCode, err, 4012, ""},
+		{AssertCode, err, 500, "gurutest: wrong code\nhave: 4012\nwant: 500\nchain:\n    error 4012: charge card\n    error 500: EOF"},
+		{AssertCode, io.EOF, 500, "gurutest: wrong code\nhave: no code\nwant: 500\nchain:\n    EOF"},
+		{AssertCode, nil, 0, "gurutest: wrong code\nhave: nil error\nwant: 0"},
+		{AssertCode, guru.New(0, "x"), 0, ""},
+
+		{AssertHas, err, 500, ""},
+		{AssertHas, err, 404, "gurutest: code not in chain\nhave: 4012, 500\nwant: 404\nchain:\n    error 4012: charge card\n    error 500: EOF"},
+		{AssertHas, errors.New("x"), 404, "gurutest: code not in chain\nhave: no code\nwant: 404\nchain:\n    x"},
+		{AssertHas, nil, 404, "gurutest: code not in chain\nhave: nil error\nwant: 404"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			ft := new(fakeT)
+			ok := tt.fn(ft, tt.err, tt.code)
+			if ok != (tt.want == "") {
+				t.Errorf("ok is %t", ok)
+			}
+			if ft.msg != tt.want {
+				t.Errorf("\nout:  %q\nwant: %q\n", ft.msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	err := guru.Wrap(4012, guru.WithCode(500, io.EOF), "charge card")
+
+	tests := []struct {
+		fn         func(testing.TB, error, int)
+		code       int
+		wantFailed bool
+	}{
+		{RequireCode, 4012, false},
+		{RequireCode, 500, true},
+		{RequireHas, 500, false},
+		{RequireHas, 404, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			ft := new(fakeT)
+			tt.fn(ft, err, tt.code)
+			if ft.failed != tt.wantFailed {
+				t.Errorf("\nout:  %t\nwant: %t\n", ft.failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	old := guru.DefaultRegistry
+	guru.DefaultRegistry = &guru.Registry{}
+	t.Cleanup(func() { guru.DefaultRegistry = old })
+	guru.Register(4012, "ErrInvoiceMissing", "")
+
+	ft := new(fakeT)
+	AssertCode(ft, guru.New(4012, "x"), 404)
+	want := "gurutest: wrong code\nhave: 4012 (ErrInvoiceMissing)\nwant: 404\nchain:\n    error 4012: x"
+	if ft.msg != want {
+		t.Errorf("\nout:  %q\nwant: %q\n", ft.msg, want)
+	}
+}
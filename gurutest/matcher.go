@@ -0,0 +1,45 @@
+package gurutest
+
+import (
+	"fmt"
+
+	"zgo.at/guru"
+)
+
+// Matcher matches errors with a code. It implements the gomock.Matcher
+// interface, and Match can be used with testify's mock.MatchedBy:
+//
+//	m.EXPECT().Report(gurutest.Code(4012))
+//	m.On("Report", mock.MatchedBy(gurutest.Code(4012).Match))
+type Matcher struct {
+	code int
+	has  bool
+}
+
+// Code matches errors where guru.Code is code.
+func Code(code int) Matcher { return Matcher{code: code} }
+
+// Has matches errors where any error in the chain has the code code, as with
+// guru.Has.
+func Has(code int) Matcher { return Matcher{code: code, has: true} }
+
+// Match reports whether err matches.
+func (m Matcher) Match(err error) bool {
+	if m.has {
+		return guru.Has(err, m.code)
+	}
+	return guru.Is(err, m.code)
+}
+
+// Matches reports whether x is an error that matches.
+func (m Matcher) Matches(x interface{}) bool {
+	err, ok := x.(error)
+	return ok && m.Match(err)
+}
+
+func (m Matcher) String() string {
+	if m.has {
+		return fmt.Sprintf("is an error with code %d in the chain", m.code)
+	}
+	return fmt.Sprintf("is an error with code %d", m.code)
+}
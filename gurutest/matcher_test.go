@@ -0,0 +1,38 @@
+package gurutest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"zgo.at/guru"
+)
+
+func TestMatcher(t *testing.T) {
+	err := guru.Wrap(4012, guru.WithCode(500, io.EOF), "charge card")
+
+	tests := []struct {
+		m          Matcher
+		in         interface{}
+		want       bool
+		wantString string
+	}{
+		{Code(4012), err, true, "is an error with code 4012"},
+		{Code(500), err, false, "is an error with code 500"},
+		{Code(0), io.EOF, false, "is an error with code 0"},
+		{Code(4012), 4012, false, "is an error with code 4012"},
+		{Code(4012), nil, false, "is an error with code 4012"},
+		{Has(500), err, true, "is an error with code 500 in the chain"},
+		{Has(404), err, false, "is an error with code 404 in the chain"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := tt.m.Matches(tt.in); out != tt.want {
+				t.Errorf("\nout:  %t\nwant: %t\n", out, tt.want)
+			}
+			if out := tt.m.String(); out != tt.wantString {
+				t.Errorf("\nout:  %q\nwant: %q\n", out, tt.wantString)
+			}
+		})
+	}
+}
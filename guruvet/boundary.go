@@ -0,0 +1,385 @@
+package guruvet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Boundary checks that errors returned from exported functions have a code.
+//
+// It reports returning an error from an exported function or method that isn't
+// created or wrapped by the guru package, such as errors.New(), or the error
+// of a call to a function from another package:
+//
+//	func Get(id int) (*Thing, error) {
+//		row, err := db.Query(id)
+//		if err != nil {
+//			return nil, err  // Reported; use guru.Wrap().
+//		}
+//	}
+//
+// Errors returned by functions that always return a coded error (also from
+// other packages checked by Boundary) and sentinel errors created with the
+// guru package are fine; so is fmt.Errorf() if one of the arguments has a
+// code. Anything else (such as interface method calls and function
+// parameters) is reported. Variables are coded only if every value assigned to
+// them is, regardless of the order of the assignments.
+//
+// The -pkgs flag sets the packages to report errors in, as a comma-separated
+// list of import paths; a path ending in "/..." also matches all packages
+// below it. All packages are checked if it's empty. Main packages and test
+// files are never checked.
+var Boundary = &analysis.Analyzer{
+	Name:      "guruboundary",
+	Doc:       "check that errors returned from exported functions have a code",
+	URL:       "https://pkg.go.dev/zgo.at/guru/guruvet",
+	Run:       runBoundary,
+	FactTypes: []analysis.Fact{new(codedFact)},
+}
+
+var boundaryPkgs string
+
+func init() {
+	Boundary.Flags.StringVar(&boundaryPkgs, "pkgs", "", "comma-separated list of packages to check; all packages if empty")
+}
+
+// codedFact is exported for exported functions that always return coded
+// errors, and exported package-level variables that are coded errors.
+type codedFact struct{}
+
+func (*codedFact) AFact()         {}
+func (*codedFact) String() string { return "coded" }
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// boundary holds the state for checking a single package.
+type boundary struct {
+	pass *analysis.Pass
+
+	// Functions and their declarations; coded is the current assumption for
+	// functions with an error result, which starts as true for all of them.
+	decls map[*types.Func]*ast.FuncDecl
+	coded map[*types.Func]bool
+
+	// Expressions assigned to variables; nil if the variable is a parameter or
+	// is assigned to in a way that isn't tracked (such as a range loop).
+	assigns map[*types.Var][]ast.Expr
+	visit   map[*types.Var]bool
+}
+
+func runBoundary(pass *analysis.Pass) (interface{}, error) {
+	if pass.Pkg.Name() == "main" {
+		return nil, nil
+	}
+
+	b := &boundary{
+		pass:    pass,
+		decls:   make(map[*types.Func]*ast.FuncDecl),
+		coded:   make(map[*types.Func]bool),
+		assigns: make(map[*types.Var][]ast.Expr),
+		visit:   make(map[*types.Var]bool),
+	}
+	for _, f := range pass.Files {
+		if strings.HasSuffix(pass.Fset.Position(f.Pos()).Filename, "_test.go") {
+			continue
+		}
+		b.collect(f)
+	}
+
+	// Functions that return an error from a function that's not coded aren't
+	// coded either; iterate until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for fn, decl := range b.decls {
+			if b.coded[fn] && len(b.uncoded(fn, decl)) > 0 {
+				b.coded[fn], changed = false, true
+			}
+		}
+	}
+
+	for fn, ok := range b.coded {
+		if ok && fn.Exported() {
+			pass.ExportObjectFact(fn, new(codedFact))
+		}
+	}
+	for v := range b.assigns {
+		if v.Exported() && v.Parent() == pass.Pkg.Scope() && b.codedVar(v) {
+			pass.ExportObjectFact(v, new(codedFact))
+		}
+	}
+
+	if !checkPkg(pass.Pkg.Path()) {
+		return nil, nil
+	}
+	for fn, decl := range b.decls {
+		if !boundaryFunc(fn) {
+			continue
+		}
+		for _, n := range b.uncoded(fn, decl) {
+			pass.Reportf(n.Pos(), "%s returns an error that isn't created or wrapped by guru", fn.Name())
+		}
+	}
+	return nil, nil
+}
+
+// checkPkg reports if errors in the package path should be reported.
+func checkPkg(path string) bool {
+	if boundaryPkgs == "" {
+		return true
+	}
+	for _, p := range strings.Split(boundaryPkgs, ",") {
+		p = strings.TrimSpace(p)
+		if p == path {
+			return true
+		}
+		if pre, ok := strings.CutSuffix(p, "/..."); ok && (path == pre || strings.HasPrefix(path, pre+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// boundaryFunc reports if fn is an exported function, or an exported method on
+// an exported type.
+func boundaryFunc(fn *types.Func) bool {
+	if !fn.Exported() {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return true
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Exported()
+}
+
+// collect records the functions and all assignments to variables in f.
+func (b *boundary) collect(f *ast.File) {
+	info := b.pass.TypesInfo
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			fn, ok := info.Defs[n.Name].(*types.Func)
+			if !ok || n.Body == nil {
+				return true
+			}
+			sig := fn.Type().(*types.Signature)
+			b.params(sig.Params(), false)
+			if sig.Recv() != nil {
+				b.assigns[sig.Recv()] = nil
+			}
+			b.params(sig.Results(), true)
+			if errorResults(sig) != nil {
+				b.decls[fn], b.coded[fn] = n, true
+			}
+		case *ast.FuncLit:
+			sig, ok := info.TypeOf(n).(*types.Signature)
+			if ok {
+				b.params(sig.Params(), false)
+				b.params(sig.Results(), true)
+			}
+		case *ast.ValueSpec:
+			for i, id := range n.Names {
+				v, ok := info.Defs[id].(*types.Var)
+				if !ok {
+					continue
+				}
+				switch {
+				case len(n.Values) == 0:
+					b.assign(v, nil)
+				case len(n.Values) == len(n.Names):
+					b.assign(v, n.Values[i])
+				default:
+					b.assign(v, n.Values[0])
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				id, ok := ast.Unparen(lhs).(*ast.Ident)
+				if !ok {
+					continue
+				}
+				v, ok := info.ObjectOf(id).(*types.Var)
+				if !ok {
+					continue
+				}
+				switch {
+				case n.Tok != token.ASSIGN && n.Tok != token.DEFINE:
+					b.untracked(v)
+				case len(n.Rhs) == len(n.Lhs):
+					b.assign(v, n.Rhs[i])
+				default:
+					b.assign(v, n.Rhs[0])
+				}
+			}
+		case *ast.RangeStmt:
+			for _, e := range []ast.Expr{n.Key, n.Value} {
+				if id, ok := e.(*ast.Ident); ok {
+					if v, ok := info.ObjectOf(id).(*types.Var); ok {
+						b.untracked(v)
+					}
+				}
+			}
+		case *ast.UnaryExpr:
+			// Taking the address allows modifying it in ways that aren't
+			// tracked.
+			if id, ok := ast.Unparen(n.X).(*ast.Ident); ok && n.Op == token.AND {
+				if v, ok := info.ObjectOf(id).(*types.Var); ok {
+					b.untracked(v)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// params records parameters as untracked, and named results as being nil.
+func (b *boundary) params(t *types.Tuple, results bool) {
+	for i := 0; i < t.Len(); i++ {
+		if results {
+			b.assign(t.At(i), nil)
+		} else {
+			b.untracked(t.At(i))
+		}
+	}
+}
+
+// assign records that e is assigned to v; a nil e is the zero value.
+func (b *boundary) assign(v *types.Var, e ast.Expr) {
+	if a, ok := b.assigns[v]; ok && a == nil {
+		return // Untracked.
+	}
+	if e == nil {
+		e = ast.NewIdent("nil")
+	}
+	b.assigns[v] = append(b.assigns[v], e)
+}
+
+func (b *boundary) untracked(v *types.Var) { b.assigns[v] = nil }
+
+// errorResults gets the indexes of the error results of sig.
+func errorResults(sig *types.Signature) []int {
+	var idx []int
+	for i := 0; i < sig.Results().Len(); i++ {
+		if types.Identical(sig.Results().At(i).Type(), errorType) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// uncoded gets the returned errors in decl that aren't coded.
+func (b *boundary) uncoded(fn *types.Func, decl *ast.FuncDecl) []ast.Node {
+	var (
+		sig = fn.Type().(*types.Signature)
+		idx = errorResults(sig)
+		bad []ast.Node
+	)
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			switch {
+			case len(n.Results) == 0: // Named results.
+				for _, i := range idx {
+					if !b.codedVar(sig.Results().At(i)) {
+						bad = append(bad, n)
+						break
+					}
+				}
+			case len(n.Results) < sig.Results().Len(): // return f()
+				if !b.codedCall(n.Results[0]) {
+					bad = append(bad, n.Results[0])
+				}
+			default:
+				for _, i := range idx {
+					if !b.codedExpr(n.Results[i]) {
+						bad = append(bad, n.Results[i])
+					}
+				}
+			}
+		}
+		return true
+	})
+	return bad
+}
+
+// codedExpr reports if e is an error with a code, or nil.
+func (b *boundary) codedExpr(e ast.Expr) bool {
+	info := b.pass.TypesInfo
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		switch obj := info.ObjectOf(e).(type) {
+		case nil, *types.Nil: // nil, or the zero value from assign().
+			return true
+		case *types.Var:
+			return b.codedVar(obj)
+		}
+	case *ast.SelectorExpr:
+		v, ok := info.ObjectOf(e.Sel).(*types.Var)
+		return ok && b.codedVar(v)
+	case *ast.CallExpr:
+		return b.codedCall(e)
+	}
+	return false
+}
+
+// codedCall reports if e is a call to a function from the guru package or a
+// function that only returns coded errors, or to fmt.Errorf with an argument
+// that has a code.
+func (b *boundary) codedCall(e ast.Expr) bool {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn := typeutil.StaticCallee(b.pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil {
+		return false
+	}
+	switch {
+	case fn.Pkg().Path() == guruPath:
+		return true
+	case fn.Pkg().Path() == "fmt" && fn.Name() == "Errorf":
+		for _, a := range call.Args[1:] {
+			if types.Identical(b.pass.TypesInfo.TypeOf(a), errorType) && b.codedExpr(a) {
+				return true
+			}
+		}
+		return false
+	case fn.Pkg() == b.pass.Pkg:
+		return b.coded[fn.Origin()]
+	}
+	return b.pass.ImportObjectFact(fn.Origin(), new(codedFact))
+}
+
+// codedVar reports if all values assigned to v are coded.
+func (b *boundary) codedVar(v *types.Var) bool {
+	if v.Pkg() != b.pass.Pkg {
+		return b.pass.ImportObjectFact(v, new(codedFact))
+	}
+	a, ok := b.assigns[v]
+	if !ok || a == nil {
+		return false
+	}
+	if b.visit[v] {
+		return true // The other assignments decide.
+	}
+	b.visit[v] = true
+	defer delete(b.visit, v)
+	for _, e := range a {
+		if !b.codedExpr(e) {
+			return false
+		}
+	}
+	return true
+}
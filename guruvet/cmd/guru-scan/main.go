@@ -0,0 +1,58 @@
+// Command guru-scan writes a JSON catalog of all calls to zgo.at/guru
+// functions that accept an error code, with the code, message, and position.
+//
+// Usage:
+//
+//	guru-scan [-o file] [packages]
+//
+// The packages default to ./... For example, with go:generate:
+//
+//	//go:generate go run zgo.at/guru/guruvet/cmd/guru-scan -o errors.json ./...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"zgo.at/guru/guruvet"
+)
+
+func main() {
+	out := flag.String("o", "", "write to this file instead of stdout")
+	flag.Parse()
+
+	if err := run(*out, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "guru-scan:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, patterns []string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	sites, err := guruvet.Scan(dir, patterns...)
+	if err != nil {
+		return err
+	}
+	if sites == nil {
+		sites = []guruvet.Site{}
+	}
+
+	j, err := json.MarshalIndent(sites, "", "\t")
+	if err != nil {
+		return err
+	}
+	j = append(j, '\n')
+	if out == "" {
+		_, err = os.Stdout.Write(j)
+		return err
+	}
+	return os.WriteFile(out, j, 0o644)
+}
@@ -0,0 +1,16 @@
+// Command guruvet checks the error codes passed to zgo.at/guru, and that
+// errors returned from exported functions have a code; it's intended to be run
+// with go vet:
+//
+//	go vet -vettool=$(which guruvet) ./...
+//
+// Use -guruboundary=false to disable the second check, or
+// -guruboundary.pkgs to set the packages it's reported in.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+	"zgo.at/guru/guruvet"
+)
+
+func main() { unitchecker.Main(guruvet.Analyzer, guruvet.Boundary) }
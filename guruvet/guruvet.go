@@ -0,0 +1,189 @@
+// Package guruvet provides analyzers to check the use of the guru package.
+//
+// Analyzer checks the error codes passed to the guru package, and reports:
+//
+//   - Code 0, which is indistinguishable from "no code".
+//   - The same code used with different constant messages in a package.
+//   - Codes outside the range set with the -range flag (e.g. -range=1000-9999).
+//
+// Only constant codes are checked.
+//
+// Boundary reports errors without a code that are returned from exported
+// functions. Use them with go vet:
+//
+//	go install zgo.at/guru/guruvet/cmd/guruvet@latest
+//	go vet -vettool=$(which guruvet) ./...
+package guruvet
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const guruPath = "zgo.at/guru"
+
+// Analyzer checks the error codes passed to the guru package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "guru",
+	Doc:      "check error codes passed to zgo.at/guru",
+	URL:      "https://pkg.go.dev/zgo.at/guru/guruvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var codeRange rangeFlag
+
+func init() {
+	Analyzer.Flags.Var(&codeRange, "range", "allowed range of error codes, as min-max (inclusive)")
+}
+
+type rangeFlag struct {
+	set      bool
+	min, max int64
+}
+
+func (r *rangeFlag) String() string {
+	if !r.set {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", r.min, r.max)
+}
+
+func (r *rangeFlag) Set(v string) error {
+	if v == "" {
+		return errors.New("must be min-max")
+	}
+	// Skip the first character, so that negative minimums work.
+	l, h, ok := strings.Cut(v[1:], "-")
+	if !ok {
+		return errors.New("must be min-max")
+	}
+	lo, err := strconv.ParseInt(v[:1]+l, 10, 64)
+	if err != nil {
+		return err
+	}
+	hi, err := strconv.ParseInt(h, 10, 64)
+	if err != nil {
+		return err
+	}
+	if lo > hi {
+		return fmt.Errorf("min %d is larger than max %d", lo, hi)
+	}
+	*r = rangeFlag{set: true, min: lo, max: hi}
+	return nil
+}
+
+// funcs lists the functions in the guru package (and methods on Registry) that
+// accept a code as the first argument, and the index of the message argument
+// (-1 if there is none).
+var funcs = map[string]int{
+	"New":      1,
+	"NewSub":   2,
+	"NewStack": 1,
+	"Errorf":   1,
+	"Wrap":     2,
+	"Wrapf":    2,
+	"WithCode": -1,
+	"E":        -1,
+	"Const":    1,
+
+	"NewFromRegistry": -1,
+	"Errorb":          -1,
+	"ErrorfLazy":      1,
+	"WrapfLazy":       2,
+}
+
+type use struct {
+	pos token.Pos
+	msg string
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	seen := make(map[int64]use)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		name, msgArg, ok := guruFunc(pass.TypesInfo, call)
+		if !ok || len(call.Args) == 0 {
+			return
+		}
+
+		code, ok := constInt(pass.TypesInfo, call.Args[0])
+		if !ok {
+			return
+		}
+		if code == 0 {
+			pass.Reportf(call.Args[0].Pos(), "guru.%s called with code 0, which is the same as no code", name)
+		} else if codeRange.set && (code < codeRange.min || code > codeRange.max) {
+			pass.Reportf(call.Args[0].Pos(), "guru.%s called with code %d, which is outside the range %s",
+				name, code, codeRange.String())
+		}
+
+		if msgArg < 0 || msgArg >= len(call.Args) {
+			return
+		}
+		msg, ok := constString(pass.TypesInfo, call.Args[msgArg])
+		if !ok {
+			return
+		}
+		if prev, ok := seen[code]; ok && prev.msg != msg {
+			pass.Reportf(call.Args[0].Pos(), "code %d is also used for a different message at %s",
+				code, pass.Fset.Position(prev.pos))
+			return
+		}
+		if _, ok := seen[code]; !ok {
+			seen[code] = use{pos: call.Args[0].Pos(), msg: msg}
+		}
+	})
+	return nil, nil
+}
+
+// guruFunc reports if call is a call to one of the funcs, returning the name
+// and index of the message argument.
+func guruFunc(info *types.Info, call *ast.CallExpr) (string, int, bool) {
+	fn := typeutil.StaticCallee(info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != guruPath {
+		return "", 0, false
+	}
+	// Methods on other types, such as CodeBlock (which accepts an offset) and
+	// ErrorCode (which doesn't accept a code), have the same names.
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if n, ok := t.(*types.Named); !ok || n.Obj().Name() != "Registry" {
+			return "", 0, false
+		}
+	}
+	msgArg, ok := funcs[fn.Name()]
+	return fn.Name(), msgArg, ok
+}
+
+func constInt(info *types.Info, e ast.Expr) (int64, bool) {
+	tv, ok := info.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Int64Val(tv.Value)
+}
+
+func constString(info *types.Info, e ast.Expr) (string, bool) {
+	tv, ok := info.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
@@ -0,0 +1,75 @@
+package guruvet
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	if err := Analyzer.Flags.Set("range", "1-9999"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { codeRange = rangeFlag{} })
+
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestRangeFlag(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"1-9999", "1-9999", false},
+		{"-10--5", "-10--5", false},
+		{"-10-5", "-10-5", false},
+		{"", "", true},
+		{"5", "", true},
+		{"5-1", "", true},
+		{"a-b", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var r rangeFlag
+			err := r.Set(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err: %v", err)
+			}
+			if r.String() != tt.want {
+				t.Errorf("\nout:  %q\nwant: %q", r.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Boundary, "b", "c")
+}
+
+func TestBoundaryPkgs(t *testing.T) {
+	if err := Boundary.Flags.Set("pkgs", "x/..., c"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { boundaryPkgs = "" })
+
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"c", true},
+		{"cc", false},
+		{"x", true},
+		{"x/y", true},
+		{"xy", false},
+		{"b", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if out := checkPkg(tt.in); out != tt.want {
+				t.Errorf("\nout:  %t\nwant: %t", out, tt.want)
+			}
+		})
+	}
+}
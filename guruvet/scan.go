@@ -0,0 +1,107 @@
+package guruvet
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Site is a call to one of the guru functions that accepts a code.
+type Site struct {
+	Code    *int64 `json:"code"`              // nil if it's not a constant.
+	Func    string `json:"func"`              // Function name, e.g. "Wrap".
+	Message string `json:"message,omitempty"` // Message or format string.
+	Expr    string `json:"expr,omitempty"`    // Code expression if it's not a constant.
+	Pos     string `json:"pos"`               // As file:line.
+
+	file string
+	line int
+}
+
+// Scan finds every call to one of the guru functions that accept a code in
+// the packages matching patterns, including test files.
+//
+// The sites are sorted by code, with non-constant codes last. Paths are
+// relative to dir if possible.
+func Scan(dir string, patterns ...string) ([]Site, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Dir:   dir,
+		Tests: true,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports |
+			packages.NeedDeps,
+	}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("guruvet.Scan: %d errors while loading packages", n)
+	}
+
+	var (
+		sites []Site
+		seen  = make(map[string]struct{})
+	)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Syntax {
+			ast.Inspect(f, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				name, msgArg, ok := guruFunc(pkg.TypesInfo, call)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+
+				pos := pkg.Fset.Position(call.Pos())
+				// Packages with tests are loaded twice.
+				key := fmt.Sprintf("%s:%d:%d", pos.Filename, pos.Line, pos.Column)
+				if _, ok := seen[key]; ok {
+					return true
+				}
+				seen[key] = struct{}{}
+
+				file := pos.Filename
+				if rel, err := filepath.Rel(dir, file); err == nil {
+					file = rel
+				}
+				s := Site{Func: name, file: file, line: pos.Line, Pos: fmt.Sprintf("%s:%d", file, pos.Line)}
+				if code, ok := constInt(pkg.TypesInfo, call.Args[0]); ok {
+					s.Code = &code
+				} else {
+					b := new(bytes.Buffer)
+					printer.Fprint(b, pkg.Fset, call.Args[0])
+					s.Expr = b.String()
+				}
+				if msgArg >= 0 && msgArg < len(call.Args) {
+					s.Message, _ = constString(pkg.TypesInfo, call.Args[msgArg])
+				}
+				sites = append(sites, s)
+				return true
+			})
+		}
+	}
+
+	sort.Slice(sites, func(i, j int) bool {
+		a, b := sites[i], sites[j]
+		switch {
+		case a.Code == nil || b.Code == nil:
+			if (a.Code == nil) != (b.Code == nil) {
+				return b.Code == nil
+			}
+		case *a.Code != *b.Code:
+			return *a.Code < *b.Code
+		}
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		return a.line < b.line
+	})
+	return sites, nil
+}
@@ -0,0 +1,70 @@
+package guruvet
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	stubs, err := os.ReadFile("testdata/src/zgo.at/guru/guru.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755)
+		err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("guru/go.mod", "module zgo.at/guru\n")
+	write("guru/guru.go", string(stubs))
+	write("go.mod", "module example.com/scan\n\ngo 1.20\n\n"+
+		"require zgo.at/guru v0.0.0\n\nreplace zgo.at/guru => ./guru\n")
+	write("a.go", `package scan
+
+import "zgo.at/guru"
+
+const codeDB = 500
+
+var code = 1
+
+func f(err error) {
+	guru.Wrap(codeDB, err, "db")
+	guru.New(code, "var")
+	guru.Errorf(404, "no such %s", "thing")
+	guru.WithCode(42, err)
+	guru.Code(err)
+}
+`)
+	write("a_test.go", `package scan
+
+import "zgo.at/guru"
+
+var _ = guru.NewSub(404, 2, "test")
+`)
+	sites, err := Scan(dir, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := func(n int64) *int64 { return &n }
+	want := []Site{
+		{Code: i(42), Func: "WithCode", Pos: "a.go:13"},
+		{Code: i(404), Func: "Errorf", Message: "no such %s", Pos: "a.go:12"},
+		{Code: i(404), Func: "NewSub", Message: "test", Pos: "a_test.go:5"},
+		{Code: i(500), Func: "Wrap", Message: "db", Pos: "a.go:10"},
+		{Func: "New", Message: "var", Expr: "code", Pos: "a.go:11"},
+	}
+	for i := range sites {
+		sites[i].file, sites[i].line = "", 0
+	}
+	if !reflect.DeepEqual(sites, want) {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", sites, want)
+	}
+}
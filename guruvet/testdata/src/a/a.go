@@ -0,0 +1,33 @@
+package a
+
+import "zgo.at/guru"
+
+const CodeFruit = 1
+
+func f(err error, code int) {
+	guru.New(CodeFruit, "too many bananas")
+	guru.New(CodeFruit, "too many bananas")
+	guru.Errorf(CodeFruit, "too many %s", "apples") // want `code 1 is also used for a different message at .*a.go:8`
+	guru.Wrap(2, err, "not enough beer")
+	guru.Wrapf(2, err, "not enough %s", "wine") // want `code 2 is also used for a different message`
+	guru.NewSub(3, 1, "x")
+
+	guru.New(0, "oh noes")      // want `guru.New called with code 0`
+	guru.WithCode(0, err)       // want `guru.WithCode called with code 0`
+	guru.New(99999, "too high") // want `guru.New called with code 99999, which is outside the range 1-9999`
+
+	// Codes in blocks are relative to the start of the block.
+	blk := guru.Block(5000, 5999, "storage")
+	blk.New(0, "first")
+	blk.New(1, "x")
+	blk.Errorf(1, "x %d", 1)
+	guru.Block(6000, 6999, "other").New(1, "y")
+	blk.Wrap(0, err, "z")
+
+	var reg guru.Registry
+	reg.New(0, "oh noes") // want `guru.New called with code 0`
+
+	guru.New(code, "not constant")
+	guru.New(4, "x"+err.Error())
+	guru.Code(err)
+}
@@ -0,0 +1,99 @@
+package b
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"zgo.at/guru"
+)
+
+var (
+	ErrCoded = guru.New(1, "coded") // want ErrCoded:"coded"
+	ErrPlain = errors.New("plain")
+	reg      = &guru.Registry{}
+)
+
+type T struct{}
+type t struct{}
+
+func New() error            { return guru.New(1, "x") }                // want New:"coded"
+func Reg() error            { return reg.New(1, "x") }                 // want Reg:"coded"
+func Nil() error            { return nil }                             // want Nil:"coded"
+func Sentinel() error       { return ErrCoded }                        // want Sentinel:"coded"
+func Errorf() error         { return fmt.Errorf("x: %w", ErrCoded) }   // want Errorf:"coded"
+func Field() error          { return guru.WithField(New(), "k", "v") } // want Field:"coded"
+func Plain() error          { return errors.New("x") }                 // want `Plain returns an error that isn't created or wrapped by guru`
+func PlainSentinel() error  { return ErrPlain }                        // want `PlainSentinel returns an error`
+func PlainErrorf() error    { return fmt.Errorf("x: %w", ErrPlain) }   // want `PlainErrorf returns an error`
+func Param(err error) error { return err }                             // want `Param returns an error`
+
+func Open(path string) (*os.File, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err // want `Open returns an error`
+	}
+	return fp, nil
+}
+
+func Wrapped(path string) (*os.File, error) { // want Wrapped:"coded"
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, guru.Wrap(1, err, "open")
+	}
+	return fp, nil
+}
+
+func Local() error { // want Local:"coded"
+	err := helper()
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("y: %w", helper())
+}
+
+func LocalPlain() error {
+	return plainHelper() // want `LocalPlain returns an error`
+}
+
+func Multi() (int, error) { return multi() }              // want Multi:"coded"
+func Pass() (int, error)  { return os.Stdout.Write(nil) } // want `Pass returns an error`
+func Reassign() error {
+	err := New()
+	err = errors.New("x")
+	return err // want `Reassign returns an error`
+}
+
+func Named() (err error) { // want Named:"coded"
+	err = New()
+	return
+}
+
+func NamedPlain() (err error) {
+	err = ErrPlain
+	return // want `NamedPlain returns an error`
+}
+
+func Closure() error { // want Closure:"coded"
+	f := func() error { return errors.New("not checked") }
+	_ = f
+	return nil
+}
+
+func Rec(n int) error { // want Rec:"coded"
+	if n == 0 {
+		return New()
+	}
+	return Rec(n - 1)
+}
+
+func (T) Method() error   { return errors.New("x") } // want `Method returns an error`
+func (*T) Method2() error { return New() }           // want Method2:"coded"
+func (t) Method() error   { return errors.New("x") }
+func unexported() error   { return errors.New("x") }
+
+func helper() error      { return New() }
+func plainHelper() error { return ErrPlain }
+func multi() (int, error) {
+	return 0, New()
+}
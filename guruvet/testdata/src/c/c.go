@@ -0,0 +1,8 @@
+package c
+
+import "b"
+
+func Imported() error         { return b.New() }    // want Imported:"coded"
+func ImportedVar() error      { return b.ErrCoded } // want ImportedVar:"coded"
+func ImportedPlain() error    { return b.Plain() }  // want `ImportedPlain returns an error`
+func ImportedPlainVar() error { return b.ErrPlain } // want `ImportedPlainVar returns an error`
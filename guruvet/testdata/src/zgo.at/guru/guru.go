@@ -0,0 +1,22 @@
+package guru
+
+func New(code int, msg string) error                              { return nil }
+func NewSub(code, sub int, msg string) error                      { return nil }
+func Errorf(code int, format string, args ...interface{}) error   { return nil }
+func Wrap(code int, err error, msg string) error                  { return nil }
+func Wrapf(code int, err error, f string, a ...interface{}) error { return nil }
+func WithCode(code int, err error) error                          { return nil }
+func Code(err error) int                                          { return 0 }
+
+type Registry struct{}
+
+func (r *Registry) New(code int, msg string) error { return nil }
+
+func WithField(err error, k string, v interface{}) error { return nil }
+
+type CodeBlock struct{}
+
+func Block(min, max int, name string) CodeBlock                         { return CodeBlock{} }
+func (b CodeBlock) New(offset int, msg string) error                    { return nil }
+func (b CodeBlock) Errorf(offset int, f string, a ...interface{}) error { return nil }
+func (b CodeBlock) Wrap(offset int, err error, msg string) error        { return nil }
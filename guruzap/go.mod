@@ -0,0 +1,12 @@
+module zgo.at/guru/guruzap
+
+go 1.23
+
+require (
+	go.uber.org/zap v1.28.0
+	zgo.at/guru v0.0.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+replace zgo.at/guru => ../
@@ -0,0 +1,176 @@
+// Package guruzap logs guru errors as structured objects with zap.
+package guruzap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"zgo.at/guru"
+)
+
+// Error gets a field with the key "error" for err; it's a no-op field if err
+// is nil.
+//
+//	logger.Error("saving invoice", guruzap.Error(err))
+func Error(err error) zap.Field { return NamedError("error", err) }
+
+// NamedError is like Error, but with the given key.
+func NamedError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Object(err))
+}
+
+// Object gets a marshaler for err, which encodes as:
+//
+//	code        guru.Code(), if there is a code.
+//	subcode     guru.Subcode(), if it's not 0.
+//	message     The message without codes (the %s verb).
+//	request_id  guru.RequestID(), if there is one.
+//	chain       Every error in the chain, with its code and message. Errors
+//	            that wrap more than one error have an "errors" list with an
+//	            object with a "chain" for every error.
+//	fields      guru.Fields(), if there are any.
+//	stack       guru.StackTrace() as a list of "function file:line".
+func Object(err error) zapcore.ObjectMarshaler { return object{err} }
+
+type object struct{ err error }
+
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if o.err == nil {
+		return nil
+	}
+	if c := guru.Codes(o.err); len(c) > 0 {
+		enc.AddInt("code", guru.Code(o.err))
+		if sub := guru.Subcode(o.err); sub != 0 {
+			enc.AddInt("subcode", sub)
+		}
+	}
+	enc.AddString("message", fmt.Sprintf("%s", o.err))
+	if id := guru.RequestID(o.err); id != "" {
+		enc.AddString("request_id", id)
+	}
+
+	var root *node
+	if j, err := guru.MarshalJSON(o.err); err == nil {
+		if err := json.Unmarshal(j, &root); err != nil {
+			return err
+		}
+		if err := enc.AddArray("chain", chain{root}); err != nil {
+			return err
+		}
+	}
+
+	if f := guru.Fields(o.err); len(f) > 0 {
+		if err := enc.AddObject("fields", fields(f)); err != nil {
+			return err
+		}
+	}
+	if st := guru.StackTrace(o.err); len(st) > 0 {
+		frames := make(stack, 0, len(st))
+		for _, f := range st {
+			frames = append(frames, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+		if err := enc.AddArray("stack", frames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// node is an error in the JSON from guru.MarshalJSON.
+type node struct {
+	Code    json.RawMessage `json:"code"` // Number, or string with SetCodeFormat.
+	Subcode int             `json:"subcode"`
+	Message string          `json:"message"`
+	Wrapped *node           `json:"wrapped"`
+	Errors  []*node         `json:"errors"`
+}
+
+// code gets the code, which may be a number or a string such as "0x1F4".
+func (n *node) code() (int, bool) {
+	if len(n.Code) == 0 || string(n.Code) == "null" {
+		return 0, false
+	}
+	s := string(n.Code)
+	if n.Code[0] == '"' && json.Unmarshal(n.Code, &s) != nil {
+		return 0, false
+	}
+	c, err := guru.ParseCode(s)
+	return c, err == nil
+}
+
+func (n *node) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if c, ok := n.code(); ok {
+		enc.AddInt("code", c)
+	}
+	if n.Subcode != 0 {
+		enc.AddInt("subcode", n.Subcode)
+	}
+	if n.Message != "" {
+		enc.AddString("message", n.Message)
+	}
+	if len(n.Errors) > 0 {
+		errs := make(chains, 0, len(n.Errors))
+		for _, e := range n.Errors {
+			errs = append(errs, chain{e})
+		}
+		return enc.AddArray("errors", errs)
+	}
+	return nil
+}
+
+// chain is a list of errors, starting at the node and following Wrapped. It's
+// encoded as an object with the list in "chain" if it's a wrapped error.
+type chain struct{ n *node }
+
+func (c chain) MarshalLogObject(enc zapcore.ObjectEncoder) error { return enc.AddArray("chain", c) }
+
+func (c chain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for n := c.n; n != nil; n = n.Wrapped {
+		if err := enc.AppendObject(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type chains []chain
+
+func (c chains) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, cc := range c {
+		if err := enc.AppendObject(cc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fields map[string]interface{}
+
+func (f fields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := enc.AddReflected(k, f[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type stack []string
+
+func (s stack) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, f := range s {
+		enc.AppendString(f)
+	}
+	return nil
+}
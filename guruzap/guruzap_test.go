@@ -0,0 +1,64 @@
+package guruzap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"zgo.at/guru"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{nil, `{"msg":"x"}`},
+		{errors.New("oh noes"), `{"msg":"x","error":{"message":"oh noes","chain":\[{"message":"oh noes"}\]}}`},
+		{guru.NewSub(42, 3, "oh noes"),
+			`{"msg":"x","error":{"code":42,"subcode":3,"message":"oh noes","chain":\[{"code":42,"subcode":3,"message":"oh noes"}\]}}`},
+		{guru.Wrap(42, guru.WithFields(guru.New(1, "oh noes"), map[string]interface{}{"b": 2, "a": "x"}), "ctx"),
+			`{"msg":"x","error":{"code":42,"message":"oh noes: ctx","chain":\[{"code":42,"message":"ctx"},{"code":1,"message":"oh noes"}\],"fields":{"a":"x","b":2}}}`},
+		{guru.WithCode(2, errors.Join(guru.New(1, "a"), errors.New("b"))),
+			`{"msg":"x","error":{"code":2,"message":"a\\nb","chain":\[{"code":2},{"errors":\[{"chain":\[{"code":1,"message":"a"}\]},{"chain":\[{"message":"b"}\]}\]}\]}}`},
+		{guru.WithRequestID(guru.New(42, "oh noes"), "abc"),
+			`{"msg":"x","error":{"code":42,"message":"oh noes \(request_id: abc\)","request_id":"abc","chain":\[{"code":42,"message":"oh noes"}\]}}`},
+		{guru.NewStack(42, "oh noes"),
+			`{"msg":"x","error":{"code":42,"message":"oh noes","chain":\[{"code":42,"message":"oh noes"}\],"stack":\["zgo.at/guru/guruzap.TestError .*/guruzap_test.go:\d+",.*\]}}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			l := zap.New(zapcore.NewCore(
+				zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}),
+				zapcore.AddSync(buf), zap.DebugLevel))
+			l.Info("x", Error(tt.in))
+
+			out := bytes.TrimSpace(buf.Bytes())
+			if !regexp.MustCompile(`^` + tt.want + `$`).Match(out) {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCodeFormat(t *testing.T) {
+	guru.SetCodeFormat(guru.CodeFormat{Base: 16})
+	defer guru.SetCodeFormat(guru.CodeFormat{})
+
+	buf := new(bytes.Buffer)
+	l := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}),
+		zapcore.AddSync(buf), zap.DebugLevel))
+	l.Info("x", Error(guru.Wrap(500, guru.New(42, "oh noes"), "ctx")))
+
+	out := string(bytes.TrimSpace(buf.Bytes()))
+	want := `{"msg":"x","error":{"code":500,"message":"oh noes: ctx","chain":[{"code":500,"message":"ctx"},{"code":42,"message":"oh noes"}]}}`
+	if out != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", out, want)
+	}
+}
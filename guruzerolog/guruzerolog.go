@@ -0,0 +1,141 @@
+// Package guruzerolog logs guru errors as structured objects with zerolog.
+package guruzerolog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"zgo.at/guru"
+)
+
+// ErrorMarshalFunc can be used as zerolog.ErrorMarshalFunc, so that errors
+// added with Err() are logged with Object:
+//
+//	zerolog.ErrorMarshalFunc = guruzerolog.ErrorMarshalFunc
+func ErrorMarshalFunc(err error) interface{} {
+	if err == nil {
+		return nil
+	}
+	return Object(err)
+}
+
+// Object gets a marshaler for err, which encodes as:
+//
+//	code        guru.Code(), if there is a code.
+//	subcode     guru.Subcode(), if it's not 0.
+//	message     The message without codes (the %s verb).
+//	request_id  guru.RequestID(), if there is one.
+//	chain       Every error in the chain, with its code and message. Errors
+//	            that wrap more than one error have an "errors" list with an
+//	            object with a "chain" for every error.
+//	fields      guru.Fields(), if there are any.
+//	stack       guru.StackTrace() as a list of "function file:line".
+//
+// For example:
+//
+//	log.Error().Object("error", guruzerolog.Object(err)).Msg("saving invoice")
+func Object(err error) zerolog.LogObjectMarshaler { return object{err} }
+
+type object struct{ err error }
+
+func (o object) MarshalZerologObject(e *zerolog.Event) {
+	if o.err == nil {
+		return
+	}
+	if c := guru.Codes(o.err); len(c) > 0 {
+		e.Int("code", guru.Code(o.err))
+		if sub := guru.Subcode(o.err); sub != 0 {
+			e.Int("subcode", sub)
+		}
+	}
+	e.Str("message", fmt.Sprintf("%s", o.err))
+	if id := guru.RequestID(o.err); id != "" {
+		e.Str("request_id", id)
+	}
+
+	if j, err := guru.MarshalJSON(o.err); err == nil {
+		var root *node
+		if json.Unmarshal(j, &root) == nil {
+			e.Array("chain", chain{root})
+		}
+	}
+
+	if f := guru.Fields(o.err); len(f) > 0 {
+		e.Object("fields", fields(f))
+	}
+	if st := guru.StackTrace(o.err); len(st) > 0 {
+		frames := make([]string, 0, len(st))
+		for _, f := range st {
+			frames = append(frames, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+		e.Strs("stack", frames)
+	}
+}
+
+// node is an error in the JSON from guru.MarshalJSON.
+type node struct {
+	Code    json.RawMessage `json:"code"` // Number, or string with SetCodeFormat.
+	Subcode int             `json:"subcode"`
+	Message string          `json:"message"`
+	Wrapped *node           `json:"wrapped"`
+	Errors  []*node         `json:"errors"`
+}
+
+// code gets the code, which may be a number or a string such as "0x1F4".
+func (n *node) code() (int, bool) {
+	if len(n.Code) == 0 || string(n.Code) == "null" {
+		return 0, false
+	}
+	s := string(n.Code)
+	if n.Code[0] == '"' && json.Unmarshal(n.Code, &s) != nil {
+		return 0, false
+	}
+	c, err := guru.ParseCode(s)
+	return c, err == nil
+}
+
+func (n *node) MarshalZerologObject(e *zerolog.Event) {
+	if c, ok := n.code(); ok {
+		e.Int("code", c)
+	}
+	if n.Subcode != 0 {
+		e.Int("subcode", n.Subcode)
+	}
+	if n.Message != "" {
+		e.Str("message", n.Message)
+	}
+	if len(n.Errors) > 0 {
+		errs := zerolog.Arr()
+		for _, c := range n.Errors {
+			errs.Object(chain{c})
+		}
+		e.Array("errors", errs)
+	}
+}
+
+// chain is a list of errors, starting at the node and following Wrapped. It's
+// encoded as an object with the list in "chain" if it's a wrapped error.
+type chain struct{ n *node }
+
+func (c chain) MarshalZerologObject(e *zerolog.Event) { e.Array("chain", c) }
+
+func (c chain) MarshalZerologArray(a *zerolog.Array) {
+	for n := c.n; n != nil; n = n.Wrapped {
+		a.Object(n)
+	}
+}
+
+type fields map[string]interface{}
+
+func (f fields) MarshalZerologObject(e *zerolog.Event) {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.Interface(k, f[k])
+	}
+}
@@ -0,0 +1,118 @@
+package guru
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// EventKind is the kind of event for hooks.
+type EventKind uint8
+
+// Event kinds.
+const (
+	KindNew  EventKind = iota + 1 // New error, e.g. from New or Errorf.
+	KindWrap                      // Existing error wrapped, e.g. with Wrap or WithCode.
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case KindNew:
+		return "new"
+	case KindWrap:
+		return "wrap"
+	}
+	return "EventKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Event is sent to hooks when an error with a code is created or wrapped.
+type Event struct {
+	Kind    EventKind
+	Err     error                  // The new error.
+	Cause   error                  // The error that was wrapped, for KindWrap.
+	Code    int                    // Error code.
+	Message string                 // Message added by the call, if any.
+	Fields  map[string]interface{} // Fields(Err).
+	Caller  runtime.Frame          // Location of the call.
+}
+
+var (
+	hookMu sync.Mutex
+	hookID int
+	hooks  atomic.Pointer[[]hook]
+)
+
+type hook struct {
+	id int
+	fn func(Event)
+}
+
+// AddHook adds a function that's called whenever an error is created or
+// wrapped with one of the functions in this package that accept an error code,
+// such as New, Errorf, Wrap, and WithCode. Generic codes (NewT, WrapT, etc.)
+// and functions that only annotate an error without adding a code (WithStack,
+// WithFields, WithField, Note, WithOp, etc.) don't call hooks.
+//
+// Hooks are called synchronously in the order they were added, and shouldn't
+// create errors with this package. The returned function removes the hook.
+func AddHook(fn func(Event)) (remove func()) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+
+	hookID++
+	id := hookID
+	var cur []hook
+	if h := hooks.Load(); h != nil {
+		cur = *h
+	}
+	n := append(append(make([]hook, 0, len(cur)+1), cur...), hook{id: id, fn: fn})
+	hooks.Store(&n)
+
+	return func() {
+		hookMu.Lock()
+		defer hookMu.Unlock()
+		cur := *hooks.Load()
+		n := make([]hook, 0, len(cur))
+		for _, h := range cur {
+			if h.id != id {
+				n = append(n, h)
+			}
+		}
+		hooks.Store(&n)
+	}
+}
+
+// created is called by all the functions that create an error with a code
+// after the error is created; it checks if the code is valid (in strict mode,
+// and with the registry's rules) and deprecated, records the ID, timestamp,
+// and runtime information (if enabled), and runs the hooks. It returns err, or
+// err wrapped with the recorded information.
+func created(kind EventKind, code int, msg string, cause, err error) error {
+	return createdSkip(1, kind, code, msg, cause, err)
+}
+
+// createdSkip is like created, but skips another skip frames to find the
+// location of the call, for functions that aren't called directly.
+func createdSkip(skip int, kind EventKind, code int, msg string, cause, err error) error {
+	checkStrict(err, code, skip+1)
+	checkRules(err, code, skip+1)
+	checkDeprecated(err, code, skip+1)
+	setMemo(err)
+	err = stamp(err)
+
+	h := hooks.Load()
+	if h == nil || len(*h) == 0 {
+		return err
+	}
+
+	ev := Event{Kind: kind, Err: err, Cause: cause, Code: code, Message: msg, Fields: Fields(err)}
+	pc := make([]uintptr, 1)
+	if runtime.Callers(skip+3, pc) > 0 {
+		ev.Caller, _ = runtime.CallersFrames(pc).Next()
+	}
+	for _, hk := range *h {
+		hk.fn(ev)
+	}
+	return err
+}
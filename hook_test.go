@@ -0,0 +1,116 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAddHook(t *testing.T) {
+	var events []Event
+	remove := AddHook(func(e Event) { events = append(events, e) })
+	var n int
+	remove2 := AddHook(func(e Event) { n++ })
+	t.Cleanup(func() { remove(); remove2() })
+
+	cause := errors.New("cause")
+	blk := CodeBlock{min: 1, max: 10}
+	tests := []struct {
+		in   func() error
+		want Event
+	}{
+		{func() error { return New(1, "x") }, Event{Kind: KindNew, Code: 1, Message: "x"}},
+		{func() error { return NewSub(1, 2, "x") }, Event{Kind: KindNew, Code: 1, Message: "x"}},
+		{func() error { return Errorf(1, "x %d", 1) }, Event{Kind: KindNew, Code: 1, Message: "x 1"}},
+		{func() error { return ErrorCode(1).Errorf("x %d", 1) }, Event{Kind: KindNew, Code: 1, Message: "x 1"}},
+		{func() error { return new(Registry).Errorf(1, "x %w", cause) }, Event{Kind: KindWrap, Code: 1, Message: "x cause", Cause: cause}},
+		{func() error { return NewStack(1, "x") }, Event{Kind: KindNew, Code: 1, Message: "x"}},
+		{func() error { return NewFromRegistry(1) }, Event{Kind: KindNew, Code: 1}},
+		{func() error { return Errorb(1) }, Event{Kind: KindNew, Code: 1}},
+		{func() error { return E(1, Msg("x"), Field("a", 1)) },
+			Event{Kind: KindNew, Code: 1, Message: "x", Fields: map[string]interface{}{"a": 1}}},
+		{func() error { return WithCode(1, cause) }, Event{Kind: KindWrap, Code: 1, Cause: cause}},
+		{func() error { return Wrap(1, cause, "x") }, Event{Kind: KindWrap, Code: 1, Message: "x", Cause: cause}},
+		{func() error { return Wrapf(1, cause, "x %d", 1) }, Event{Kind: KindWrap, Code: 1, Message: "x 1", Cause: cause}},
+		{func() error { return E(1, Cause(cause)) }, Event{Kind: KindWrap, Code: 1, Cause: cause}},
+		{func() error { return blk.New(0, "x") }, Event{Kind: KindNew, Code: 1, Message: "x"}},
+		{func() error { return blk.NewSub(0, 2, "x") }, Event{Kind: KindNew, Code: 1, Message: "x"}},
+		{func() error { return blk.Errorf(0, "x %d", 1) }, Event{Kind: KindNew, Code: 1, Message: "x 1"}},
+		{func() error { return blk.WithCode(0, cause) }, Event{Kind: KindWrap, Code: 1, Cause: cause}},
+		{func() error { return blk.Wrap(0, cause, "x") }, Event{Kind: KindWrap, Code: 1, Message: "x", Cause: cause}},
+		{func() error { return blk.Wrapf(0, cause, "x %d", 1) }, Event{Kind: KindWrap, Code: 1, Message: "x 1", Cause: cause}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			events = nil
+			err := tt.in()
+			if len(events) != 1 {
+				t.Fatalf("%d events", len(events))
+			}
+
+			ev := events[0]
+			if ev.Err != err {
+				t.Errorf("wrong error: %v", ev.Err)
+			}
+			if !strings.HasSuffix(ev.Caller.File, "hook_test.go") || !strings.Contains(ev.Caller.Function, "TestAddHook") {
+				t.Errorf("wrong caller: %#v", ev.Caller)
+			}
+			ev.Err, ev.Caller = nil, tt.want.Caller
+			if !reflect.DeepEqual(ev, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", ev, tt.want)
+			}
+		})
+	}
+	if n != len(tests) {
+		t.Errorf("second hook called %d times", n)
+	}
+
+	events = nil
+	Wrap(1, nil, "x")
+	NewT("x", "y")
+	remove()
+	New(1, "x")
+	if len(events) != 0 {
+		t.Errorf("%d events", len(events))
+	}
+	if n != len(tests)+1 {
+		t.Errorf("second hook removed: %d", n)
+	}
+}
+
+func TestAddHookAnnotate(t *testing.T) {
+	var n int
+	remove := AddHook(func(e Event) { n++ })
+	t.Cleanup(remove)
+
+	err := errors.New("x")
+	for i, f := range []func() error{
+		func() error { return WithStack(err) },
+		func() error { return WithFields(err, map[string]interface{}{"a": 1}) },
+		func() error { return WithField(err, "a", 1) },
+		func() error { return Note(err, "x") },
+		func() error { return WithOp(err, "x") },
+		func() error { return WithDetail(err, 1) },
+		func() error { return WithPublic(err, "x") },
+		func() error { return WithSeverity(err, LevelWarn) },
+		func() error { return WithRequestID(err, "x") },
+		func() error { return MarkRetryable(err) },
+		func() error { return WithTimeout(err) },
+		func() error { return WithRelated(err, err) },
+		func() error { return Append(nil, err, err) },
+		func() error { return NewT("x", "x") },
+	} {
+		n = 0
+		if f(); n != 0 {
+			t.Errorf("%d: %d events", i, n)
+		}
+	}
+}
+
+func TestEventKind(t *testing.T) {
+	if s := fmt.Sprint(KindNew, KindWrap, EventKind(9), EventKind(0)); s != "new wrap EventKind(9) EventKind(0)" {
+		t.Errorf("%q", s)
+	}
+}
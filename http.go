@@ -12,3 +12,47 @@ func HTTPUserError(err error) bool {
 	code := Code(err)
 	return code >= 400 && code <= 499
 }
+
+// RegisterHTTPStatus registers the HTTP status code to use for the error code
+// code in HTTPStatus.
+func (r *Registry) RegisterHTTPStatus(code, status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.http == nil {
+		r.http = make(map[int]int)
+	}
+	r.http[code] = status
+}
+
+// HTTPStatus gets the HTTP status code for err.
+//
+// The status registered with RegisterHTTPStatus for Code(err) is used if there
+// is one. Otherwise the code is used as-is if it's a valid HTTP status code
+// (in the 100 to 599 range), or 500 if it's not. It will return 200 if err is
+// nil.
+func (r *Registry) HTTPStatus(err error) int {
+	if err == nil {
+		return 200
+	}
+	code, ok := mappedCode(err)
+	if !ok {
+		return 500
+	}
+
+	r.mu.RLock()
+	status, ok := r.http[code]
+	r.mu.RUnlock()
+	if ok {
+		return status
+	}
+	if code >= 100 && code <= 599 {
+		return code
+	}
+	return 500
+}
+
+// RegisterHTTPStatus calls DefaultRegistry.RegisterHTTPStatus.
+func RegisterHTTPStatus(code, status int) { DefaultRegistry.RegisterHTTPStatus(code, status) }
+
+// HTTPStatus calls HTTPStatus on the registry of err; see RegistryOf.
+func HTTPStatus(err error) int { return RegistryOf(err).HTTPStatus(err) }
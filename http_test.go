@@ -30,3 +30,33 @@ func TestHTTPUserError(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPStatus(t *testing.T) {
+	resetRegistry(t)
+	RegisterHTTPStatus(4012, 404)
+	RegisterHTTPStatus(404, 410)
+
+	tests := []struct {
+		in   error
+		want int
+	}{
+		{nil, 200},
+		{errors.New("asd"), 500},
+		{New(0, "asd"), 500},
+		{New(4012, "asd"), 404},
+		{New(404, "asd"), 410},
+		{New(400, "asd"), 400},
+		{New(503, "asd"), 503},
+		{New(600, "asd"), 500},
+		{Wrap(4012, New(400, "asd"), "x"), 404},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := HTTPStatus(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package guru
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync/atomic"
+)
+
+var stampID atomic.Bool
+
+// EnableIDs records a unique ID with errors created with the functions that
+// accept an error code, such as New, Errorf, Wrap, and WithCode; see ID.
+//
+// This is disabled by default, as it adds some overhead to creating errors.
+// It's safe to call concurrently, and applies to errors created after the
+// call.
+func EnableIDs() { stampID.Store(true) }
+
+// ID gets the unique ID of the innermost error in the chain that has one; this
+// is the same for all errors wrapping it, so it can be used to find the log
+// entry for an error a user reported. It will return an empty string if there
+// is none, for example because EnableIDs wasn't called.
+//
+// IDs are ULIDs (https://github.com/ulid/spec), such as
+// "01HZY3MD8Y6SQF9QW1G1J0ZKXN", which sort by creation time.
+func ID(err error) string {
+	var id string
+	walk(err, func(err error) bool {
+		if s, ok := err.(*stamped); ok && s.id != "" {
+			id = s.id
+		}
+		return true
+	})
+	return id
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID creates a new ULID: a 48-bit timestamp in milliseconds followed by
+// 80 random bits, encoded as 26 characters of Crockford's base32.
+func newULID() string {
+	var b [16]byte
+	ms := uint64(now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:], uint32(ms))
+	rand.Read(b[6:])
+	return encodeULID(b)
+}
+
+func encodeULID(b [16]byte) string {
+	// 128 bits is 25.6 base32 characters, so the first character only has 3
+	// bits.
+	var (
+		out [26]byte
+		hi  = binary.BigEndian.Uint64(b[:8])
+		lo  = binary.BigEndian.Uint64(b[8:])
+	)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
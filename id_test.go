@@ -0,0 +1,70 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeULID(t *testing.T) {
+	tests := []struct {
+		in   [16]byte
+		want string
+	}{
+		{[16]byte{}, "00000000000000000000000000"},
+		{[16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			"7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{[16]byte{15: 0x1f}, "0000000000000000000000000Z"},
+		{[16]byte{15: 0x20}, "00000000000000000000000010"},
+		{[16]byte{0: 0x80}, "40000000000000000000000000"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := encodeULID(tt.in); out != tt.want {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestID(t *testing.T) {
+	EnableIDs()
+	t.Cleanup(func() { stampID.Store(false) })
+
+	inner := New(1, "x")
+	err := Wrap(2, inner, "y")
+	id := ID(err)
+	if !regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`).MatchString(id) {
+		t.Fatalf("invalid ID: %q", id)
+	}
+	if id != ID(inner) {
+		t.Errorf("ID not the same as inner: %q %q", id, ID(inner))
+	}
+	if id2 := ID(New(1, "x")); id2 == id {
+		t.Errorf("same ID twice: %q", id)
+	}
+
+	// IDs sort by creation time.
+	now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	if later := ID(New(1, "x")); later <= id {
+		t.Errorf("not sorted: %q <= %q", later, id)
+	}
+
+	if !strings.Contains(fmt.Sprintf("%+v", err), "\nid: "+id) {
+		t.Errorf("%+v", err)
+	}
+	j, jErr := MarshalJSON(err)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	if out := ID(mustFromJSON(t, string(j))); out != id {
+		t.Errorf("\nout:  %s\nwant: %s\n", out, id)
+	}
+	if out := ID(errors.New("x")); out != "" {
+		t.Error(out)
+	}
+}
@@ -0,0 +1,311 @@
+package guru
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jsonError is the JSON representation of an error.
+//
+// Every error in the chain is a jsonError, with the error it wraps in Wrapped,
+// or Errors if it wraps more than one error (e.g. errors.Join). Message is only
+// the message this error adds, without the messages of the errors it wraps.
+type jsonError struct {
+	Code    *jsonCode              `json:"code,omitempty"`
+	Subcode int                    `json:"subcode,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+	Retry   *bool                  `json:"retryable,omitempty"`
+	Timeout bool                   `json:"timeout,omitempty"`
+	Public  string                 `json:"public,omitempty"`
+	Level   Level                  `json:"severity,omitempty"`
+	Request string                 `json:"request_id,omitempty"`
+	Ops     []string               `json:"ops,omitempty"`
+	Details []interface{}          `json:"details,omitempty"`
+	Related []*jsonError           `json:"related,omitempty"`
+	ID      string                 `json:"id,omitempty"`
+	Time    *time.Time             `json:"time,omitempty"`
+	Runtime *RuntimeInfo           `json:"runtime,omitempty"`
+	Wrapped *jsonError             `json:"wrapped,omitempty"`
+	Errors  []*jsonError           `json:"errors,omitempty"`
+}
+
+func (e constError) MarshalJSON() ([]byte, error)     { return MarshalJSON(e) }
+func (e *withCode) MarshalJSON() ([]byte, error)      { return MarshalJSON(e) }
+func (e *wrapped) MarshalJSON() ([]byte, error)       { return MarshalJSON(e) }
+func (e *withFields) MarshalJSON() ([]byte, error)    { return MarshalJSON(e) }
+func (e *withStack) MarshalJSON() ([]byte, error)     { return MarshalJSON(e) }
+func (e *withRetry) MarshalJSON() ([]byte, error)     { return MarshalJSON(e) }
+func (e *withTimeout) MarshalJSON() ([]byte, error)   { return MarshalJSON(e) }
+func (e *withPublic) MarshalJSON() ([]byte, error)    { return MarshalJSON(e) }
+func (e *withSeverity) MarshalJSON() ([]byte, error)  { return MarshalJSON(e) }
+func (e *withRequestID) MarshalJSON() ([]byte, error) { return MarshalJSON(e) }
+func (e *withNote) MarshalJSON() ([]byte, error)      { return MarshalJSON(e) }
+func (e *withOp) MarshalJSON() ([]byte, error)        { return MarshalJSON(e) }
+func (e *withDetail) MarshalJSON() ([]byte, error)    { return MarshalJSON(e) }
+func (e *withRelated) MarshalJSON() ([]byte, error)   { return MarshalJSON(e) }
+func (e *stamped) MarshalJSON() ([]byte, error)       { return MarshalJSON(e) }
+
+// MarshalJSON encodes err as JSON, preserving the codes and messages of all
+// errors in the chain:
+//
+//	{"code": 42, "message": "context", "wrapped": {"message": "oh noes"}}
+//
+// This works for any error, not just errors from this package. Use FromJSON to
+// decode it. Stack traces are not included.
+func MarshalJSON(err error) ([]byte, error) {
+	return json.Marshal(toJSON(err))
+}
+
+// FromJSON decodes an error encoded with MarshalJSON.
+//
+// The errors in the chain won't be of the same type as the original errors,
+// but the messages, codes, and fields are preserved. It will return nil if
+// data is the JSON null value.
+func FromJSON(data []byte) (error, error) {
+	var j *jsonError
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	return fromJSON(j), nil
+}
+
+func toJSON(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *withStack:
+		return toJSON(e.error)
+	case *withFields:
+		j := toJSON(e.error)
+		if j.Fields == nil {
+			j.Fields = make(map[string]interface{}, len(e.fields))
+		}
+		for k, v := range e.fields {
+			j.Fields[k] = v
+		}
+		return j
+	case *withRetry:
+		j := toJSON(e.error)
+		r := e.retry
+		j.Retry = &r
+		return j
+	case *withTimeout:
+		j := toJSON(e.error)
+		j.Timeout = true
+		return j
+	case *withPublic:
+		j := toJSON(e.error)
+		j.Public = e.public
+		return j
+	case *withSeverity:
+		j := toJSON(e.error)
+		j.Level = e.level
+		return j
+	case *withRequestID:
+		j := toJSON(e.error)
+		j.Request = e.id
+		return j
+	case *withOp:
+		j := toJSON(e.error)
+		j.Ops = append([]string{e.op}, j.Ops...)
+		return j
+	case *withDetail:
+		j := toJSON(e.error)
+		j.Details = append([]interface{}{e.detail}, j.Details...)
+		return j
+	case *stamped:
+		j := toJSON(e.error)
+		if !e.time.IsZero() {
+			t := e.time
+			j.Time = &t
+		}
+		j.Runtime, j.ID = e.rt, e.id
+		return j
+	case *withRelated:
+		j := toJSON(e.error)
+		related := make([]*jsonError, 0, len(e.related)+len(j.Related))
+		for _, r := range e.related {
+			related = append(related, toJSON(r))
+		}
+		j.Related = append(related, j.Related...)
+		return j
+	case *withCode:
+		c := jsonCode(e.code)
+		j := &jsonError{Code: &c, Subcode: e.sub}
+		if isLeaf(e.error) {
+			j.Message = e.error.Error()
+		} else {
+			j.Wrapped = toJSON(e.error)
+		}
+		return j
+	case *withNote:
+		return &jsonError{Message: e.msg, Wrapped: toJSON(e.error)}
+	case *wrapped:
+		c := jsonCode(e.code)
+		return &jsonError{Code: &c, Message: e.message(), Wrapped: toJSON(e.error)}
+	}
+
+	j := &jsonError{}
+	if c, ok := codeOf(err); ok {
+		jc := jsonCode(c)
+		j.Code = &jc
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		j.Wrapped = toJSON(u.Unwrap())
+		j.Message = ownMessage(err, u.Unwrap())
+	case interface{ Unwrap() []error }:
+		errs := u.Unwrap()
+		j.Errors = make([]*jsonError, 0, len(errs))
+		for _, e := range errs {
+			if e != nil {
+				j.Errors = append(j.Errors, toJSON(e))
+			}
+		}
+		if msg := err.Error(); msg != errors.Join(errs...).Error() {
+			j.Message = msg
+		}
+	default:
+		j.Message = err.Error()
+	}
+	return j
+}
+
+func fromJSON(j *jsonError) error {
+	if j == nil {
+		return nil
+	}
+
+	var err error
+	switch {
+	case len(j.Errors) > 0:
+		errs := make([]error, 0, len(j.Errors))
+		for _, e := range j.Errors {
+			errs = append(errs, fromJSON(e))
+		}
+		err = &decodedJoin{msg: j.Message, errs: errs, memo: new(memo)}
+		if j.Code != nil {
+			err = &withCode{error: err, code: int(*j.Code)}
+		}
+	case j.Wrapped != nil:
+		err = fromJSON(j.Wrapped)
+		switch {
+		case j.Code != nil && j.Message == "":
+			err = &withCode{error: err, code: int(*j.Code), sub: j.Subcode}
+		case j.Code != nil:
+			err = &wrapped{msg: j.Message, code: int(*j.Code), error: err}
+		default:
+			err = &decoded{msg: j.Message, err: err, memo: new(memo)}
+		}
+	default:
+		err = errors.New(j.Message)
+		if j.Code != nil {
+			err = &withCode{error: err, code: int(*j.Code), sub: j.Subcode}
+		}
+	}
+
+	if j.Fields != nil {
+		err = WithFields(err, j.Fields)
+	}
+	if j.Retry != nil {
+		err = &withRetry{error: err, retry: *j.Retry}
+	}
+	if j.Timeout {
+		err = &withTimeout{error: err}
+	}
+	if j.Public != "" {
+		err = &withPublic{error: err, public: j.Public}
+	}
+	if j.Level != 0 {
+		err = &withSeverity{error: err, level: j.Level}
+	}
+	if j.Time != nil || j.Runtime != nil || j.ID != "" {
+		s := &stamped{error: err, rt: j.Runtime, id: j.ID}
+		if j.Time != nil {
+			s.time = *j.Time
+		}
+		err = s
+	}
+	if len(j.Related) > 0 {
+		related := make([]error, 0, len(j.Related))
+		for _, r := range j.Related {
+			related = append(related, fromJSON(r))
+		}
+		err = &withRelated{error: err, related: related}
+	}
+	for i := len(j.Details) - 1; i >= 0; i-- {
+		err = &withDetail{error: err, detail: j.Details[i]}
+	}
+	for i := len(j.Ops) - 1; i >= 0; i-- {
+		err = &withOp{error: err, op: j.Ops[i]}
+	}
+	if j.Request != "" {
+		err = &withRequestID{error: err, id: j.Request, msg: new(memo)}
+	}
+	return err
+}
+
+// decoded is an error without a code that was decoded; it's used for errors
+// that wrap another error, such as those created with fmt.Errorf("%w").
+type decoded struct {
+	msg  string
+	err  error
+	memo *memo
+}
+
+func (e *decoded) Unwrap() error { return e.err }
+func (e *decoded) Error() string {
+	return e.memo.get(func() string {
+		if e.msg == "" {
+			return fmt.Sprintf("%v", e.err)
+		}
+		return fmt.Sprintf("%s: %v", e.msg, e.err)
+	})
+}
+
+// decodedJoin is like decoded, but for errors that wrap more than one error,
+// such as those created with errors.Join.
+type decodedJoin struct {
+	msg  string
+	errs []error
+	memo *memo
+}
+
+func (e *decodedJoin) Unwrap() []error { return e.errs }
+func (e *decodedJoin) Error() string {
+	if e.msg != "" {
+		return e.msg
+	}
+	return e.memo.get(func() string { return errors.Join(e.errs...).Error() })
+}
+
+// isLeaf reports if err doesn't wrap any other errors.
+func isLeaf(err error) bool {
+	switch err.(type) {
+	case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		return false
+	}
+	return true
+}
+
+// ownMessage gets the message err adds to inner; for example for
+// fmt.Errorf("context: %w", inner) it returns "context".
+func ownMessage(err, inner error) string {
+	msg := err.Error()
+	if inner == nil {
+		return msg
+	}
+	// fmt.Errorf() uses %v, rather than Error().
+	for _, suffix := range []string{fmt.Sprintf("%v", inner), inner.Error()} {
+		if m := strings.TrimSuffix(msg, suffix); m != msg {
+			return strings.TrimRight(m, ": ")
+		}
+	}
+	return msg
+}
@@ -0,0 +1,110 @@
+package guru
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var _ json.Marshaler = &withCode{}
+var _ json.Marshaler = &wrapped{}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{nil, `null`},
+		{errors.New("oh noes"), `{"message":"oh noes"}`},
+		{New(42, "oh noes"), `{"code":42,"message":"oh noes"}`},
+		{New(0, "oh noes"), `{"code":0,"message":"oh noes"}`},
+		{NewSub(42, 3, "oh noes"), `{"code":42,"subcode":3,"message":"oh noes"}`},
+		{NewStack(42, "oh noes"), `{"code":42,"message":"oh noes"}`},
+		{Wrap(42, errors.New("oh noes"), "ctx"),
+			`{"code":42,"message":"ctx","wrapped":{"message":"oh noes"}}`},
+		{WithCode(666, Wrap(42, New(1, "oh noes"), "ctx")),
+			`{"code":666,"wrapped":{"code":42,"message":"ctx","wrapped":{"code":1,"message":"oh noes"}}}`},
+		{fmt.Errorf("ctx: %w", New(1, "oh noes")),
+			`{"message":"ctx","wrapped":{"code":1,"message":"oh noes"}}`},
+		{fmt.Errorf("%w", New(1, "oh noes")),
+			`{"wrapped":{"code":1,"message":"oh noes"}}`},
+		{WithFields(WithFields(New(1, "oh noes"), map[string]interface{}{"a": 1, "b": 2}), map[string]interface{}{"a": 3}),
+			`{"code":1,"message":"oh noes","fields":{"a":3,"b":2}}`},
+		{WithTimeout(New(1, "oh noes")),
+			`{"code":1,"message":"oh noes","timeout":true}`},
+		{WithSeverity(New(1, "oh noes"), LevelWarn),
+			`{"code":1,"message":"oh noes","severity":"warn"}`},
+		{WithPublic(New(1, "select failed"), "try again"),
+			`{"code":1,"message":"select failed","public":"try again"}`},
+		{Wrap(2, WithRequestID(New(1, "oh noes"), "abc"), "ctx"),
+			`{"code":2,"message":"ctx","wrapped":{"code":1,"message":"oh noes","request_id":"abc"}}`},
+		{WithOp(Wrap(2, WithOp(WithOp(New(1, "oh noes"), "c"), "b"), "ctx"), "a"),
+			`{"code":2,"message":"ctx","ops":["a"],"wrapped":{"code":1,"message":"oh noes","ops":["b","c"]}}`},
+		{errors.Join(New(1, "a"), errors.New("b")),
+			`{"errors":[{"code":1,"message":"a"},{"message":"b"}]}`},
+		{WithCode(2, fmt.Errorf("x %w %w", New(1, "a"), errors.New("b"))),
+			`{"code":2,"wrapped":{"message":"x error 1: a b","errors":[{"code":1,"message":"a"},{"message":"b"}]}}`},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, err := MarshalJSON(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+
+			back, err := FromJSON(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.in == nil {
+				if back != nil {
+					t.Fatalf("not nil: %#v", back)
+				}
+				return
+			}
+			if a, b := fmt.Sprintf("%v", back), fmt.Sprintf("%v", tt.in); a != b {
+				t.Errorf("message\nout:  %q\nwant: %q", a, b)
+			}
+			if a, b := Codes(back), Codes(tt.in); !reflect.DeepEqual(a, b) {
+				t.Errorf("codes\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := Subcode(back), Subcode(tt.in); a != b {
+				t.Errorf("subcode\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := fmt.Sprint(Fields(back)), fmt.Sprint(Fields(tt.in)); a != b {
+				t.Errorf("fields\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := Severity(back), Severity(tt.in); a != b {
+				t.Errorf("severity\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := Public(back), Public(tt.in); a != b {
+				t.Errorf("public\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := Ops(back), Ops(tt.in); !reflect.DeepEqual(a, b) {
+				t.Errorf("ops\nout:  %v\nwant: %v", a, b)
+			}
+			if a, b := RequestID(back), RequestID(tt.in); a != b {
+				t.Errorf("request ID\nout:  %v\nwant: %v", a, b)
+			}
+		})
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	out, err := json.Marshal(struct {
+		Err error `json:"err"`
+	}{Wrap(42, errors.New("oh noes"), "ctx")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"err":{"code":42,"message":"ctx","wrapped":{"message":"oh noes"}}}`
+	if string(out) != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", out, want)
+	}
+}
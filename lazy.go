@@ -0,0 +1,77 @@
+package guru
+
+import (
+	"fmt"
+	"sync"
+)
+
+// lazyMsg is an error message that's formatted when it's first used; the
+// formatted message replaces format.
+type lazyMsg struct {
+	once   sync.Once
+	format string
+	args   []interface{}
+}
+
+func (l *lazyMsg) Error() string {
+	l.once.Do(func() {
+		l.format, l.args = fmt.Sprintf(l.format, l.args...), nil
+	})
+	return l.format
+}
+
+// memo caches the result of Error() for errors that build their message from
+// the errors they wrap. A nil memo doesn't cache anything.
+type memo struct {
+	once sync.Once
+	msg  string
+}
+
+func (m *memo) get(fn func() string) string {
+	if m == nil {
+		return fn()
+	}
+	m.once.Do(func() { m.msg = fn() })
+	return m.msg
+}
+
+// hookMsg gets the message for hooks; this avoids formatting l if there are
+// no hooks.
+func hookMsg(l *lazyMsg) string {
+	if h := hooks.Load(); h == nil || len(*h) == 0 {
+		return ""
+	}
+	return l.Error()
+}
+
+// ErrorfLazy is like Errorf, but the message isn't formatted until it's used,
+// for example when Error() is called.
+//
+// This skips the formatting for errors that are never printed, which is faster
+// but not smaller: the args are kept until the message is formatted, so it
+// uses a bit more memory than Errorf.
+//
+// The %w verb isn't supported, as the wrapped errors would be unknown until
+// the message is formatted; use Errorf for that. The args shouldn't be
+// modified after calling this.
+func ErrorfLazy(code int, format string, args ...interface{}) error {
+	l := &lazyMsg{format: format, args: args}
+	return created(KindNew, code, hookMsg(l), nil, &withCode{
+		error: l,
+		code:  code,
+	})
+}
+
+// WrapfLazy is like Wrapf, but the message isn't formatted until it's used;
+// see ErrorfLazy. It will return nil if err is nil.
+func WrapfLazy(code int, err error, msg string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	l := &lazyMsg{format: msg, args: args}
+	return created(KindWrap, code, hookMsg(l), err, &wrapped{
+		lazy:  l,
+		code:  code,
+		error: err,
+	})
+}
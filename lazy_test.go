@@ -0,0 +1,135 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	s := stringer(func() string { calls++; return "x" })
+
+	tests := []struct {
+		in       error
+		want     string
+		wantPlus string
+	}{
+		{ErrorfLazy(1, "oh %s", "noes"), "error 1: oh noes", "error 1: oh noes"},
+		{WrapfLazy(2, New(1, "oh noes"), "ctx %d", 42), "error 2: error 1: oh noes: ctx 42",
+			"error 2: ctx 42\nerror 1: oh noes"},
+		{WrapfLazy(2, nil, "ctx %d", 42), "<nil>", "<nil>"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := fmt.Sprintf("%v", tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlus)
+			}
+		})
+	}
+
+	err := WrapfLazy(2, ErrorfLazy(1, "%s", s), "%s", s)
+	if calls != 0 {
+		t.Fatalf("formatted on creation: %d", calls)
+	}
+	if Code(err) != 2 || RootCode(err) != 1 {
+		t.Errorf("codes: %v", Codes(err))
+	}
+	for i := 0; i < 3; i++ {
+		_ = err.Error()
+		_ = errors.Unwrap(err).Error()
+	}
+	if calls != 2 {
+		t.Errorf("formatted %d times", calls)
+	}
+	if j, _ := MarshalJSON(err); string(j) != `{"code":2,"message":"x","wrapped":{"code":1,"message":"x"}}` {
+		t.Errorf("JSON: %s", j)
+	}
+
+	var ev Event
+	remove := AddHook(func(e Event) { ev = e })
+	defer remove()
+	ErrorfLazy(1, "oh %s", "noes")
+	if ev.Message != "oh noes" {
+		t.Errorf("hook message: %q", ev.Message)
+	}
+}
+
+type stringer func() string
+
+func (s stringer) String() string { return s() }
+
+func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = Errorf(1, "user %d: %s", n, "not found")
+	}
+}
+
+func BenchmarkErrorfLazy(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = ErrorfLazy(1, "user %d: %s", n, "not found")
+	}
+}
+
+func BenchmarkWrapf(b *testing.B) {
+	err := errors.New("oh noes")
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = Wrapf(1, err, "user %d: %s", n, "not found")
+	}
+}
+
+func BenchmarkWrapfLazy(b *testing.B) {
+	err := errors.New("oh noes")
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = WrapfLazy(1, err, "user %d: %s", n, "not found")
+	}
+}
+
+// Error() is called repeatedly by logging pipelines; make sure that it doesn't
+// format the message again.
+func TestErrorNoAlloc(t *testing.T) {
+	tests := []error{
+		New(1, "x"),
+		Errorf(1, "x %d", 42),
+		Wrap(3, Wrapf(2, WithCode(1, errors.New("x")), "y %d", 42), "z"),
+		ErrorfLazy(1, "x %d", 42),
+		WrapfLazy(2, ErrorfLazy(1, "x %d", 42), "y %d", 42),
+		WithFields(NewStack(1, "x"), map[string]interface{}{"a": 1}),
+		WithRequestID(New(1, "x"), "abc"),
+		Append(nil, New(1, "x"), errors.New("y")),
+		mustFromJSON(t, `{"message":"x","wrapped":{"code":1,"message":"y"}}`),
+		mustFromJSON(t, `{"errors":[{"code":1,"message":"y"},{"message":"z"}]}`),
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			_ = tt.Error()
+			if n := testing.AllocsPerRun(100, func() { _ = tt.Error() }); n != 0 {
+				t.Errorf("%v allocations", n)
+			}
+		})
+	}
+}
+
+func BenchmarkError(b *testing.B) {
+	err := WrapfLazy(3, Wrap(2, ErrorfLazy(1, "user %d", 42), "y"), "z %s", "x")
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = err.Error()
+	}
+}
+
+func mustFromJSON(t *testing.T, j string) error {
+	t.Helper()
+	err, jErr := FromJSON([]byte(j))
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package guru
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RegisterCatalog registers the translated messages for the language lang,
+// which is a BCP 47 tag such as "nl" or "pt-BR". This adds to any existing
+// messages for the language.
+//
+// The messages can contain variables such as {invoice}, which are replaced by
+// the value of the field with that name (see WithFields), or {code} for the
+// error code.
+func (r *Registry) RegisterCatalog(lang string, msgs map[int]string) {
+	lang = normalizeLang(lang)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.catalogs == nil {
+		r.catalogs = make(map[string]map[int]string)
+	}
+	if r.catalogs[lang] == nil {
+		r.catalogs[lang] = make(map[int]string, len(msgs))
+	}
+	for c, m := range msgs {
+		r.catalogs[lang][c] = m
+	}
+}
+
+// Languages gets all languages with a registered catalog, sorted.
+func (r *Registry) Languages() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	l := make([]string, 0, len(r.catalogs))
+	for lang := range r.catalogs {
+		l = append(l, lang)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// Localize gets the message for err in the language lang, for displaying to
+// users.
+//
+// The message is looked up in the catalog for lang, and then in the catalog of
+// the base language ("pt" for "pt-BR"). If neither has a message for the code
+// the message from WithPublic is used, then the message from Message(), and if
+// there's no message at all then the message of err without codes (the %s
+// verb).
+func (r *Registry) Localize(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+	code, ok := mappedCode(err)
+	if !ok {
+		return fmt.Sprintf("%s", err)
+	}
+
+	msg, ok := r.translation(code, normalizeLang(lang))
+	if !ok {
+		msg, ok = publicMsg(err)
+	}
+	if !ok {
+		msg = r.Message(code)
+	}
+	if msg == "" {
+		return fmt.Sprintf("%s", err)
+	}
+	return expandVars(msg, code, Fields(err))
+}
+
+func (r *Registry) translation(code int, lang string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for lang != "" {
+		if m, ok := r.catalogs[lang][code]; ok {
+			return m, true
+		}
+		i := strings.LastIndexByte(lang, '-')
+		if i == -1 {
+			break
+		}
+		lang = lang[:i]
+	}
+	return "", false
+}
+
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+}
+
+// expandVars replaces {name} in msg with the field name, or the code for
+// {code}. Unknown variables are left as-is.
+func expandVars(msg string, code int, fields map[string]interface{}) string {
+	if !strings.Contains(msg, "{") {
+		return msg
+	}
+	var b strings.Builder
+	b.Grow(len(msg))
+	for {
+		s := strings.IndexByte(msg, '{')
+		if s == -1 {
+			break
+		}
+		e := strings.IndexByte(msg[s:], '}')
+		if e == -1 {
+			break
+		}
+		e += s
+
+		name := msg[s+1 : e]
+		b.WriteString(msg[:s])
+		if v, ok := fields[name]; ok {
+			fmt.Fprint(&b, v)
+		} else if name == "code" {
+			b.WriteString(fmtCode(code))
+		} else {
+			b.WriteString(msg[s : e+1])
+		}
+		msg = msg[e+1:]
+	}
+	b.WriteString(msg)
+	return b.String()
+}
+
+// RegisterCatalog calls DefaultRegistry.RegisterCatalog.
+func RegisterCatalog(lang string, msgs map[int]string) { DefaultRegistry.RegisterCatalog(lang, msgs) }
+
+// Localize calls Localize on the registry of err; see RegistryOf.
+func Localize(err error, lang string) string { return RegistryOf(err).Localize(err, lang) }
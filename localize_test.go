@@ -0,0 +1,52 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	resetRegistry(t)
+	RegisterMessage(1, "invoice {invoice} not found")
+	Register(2, "ErrTwo", "Second error.")
+	RegisterCatalog("nl", map[int]string{
+		1: "factuur {invoice} niet gevonden",
+		2: "fout {code}: {unknown} {",
+	})
+	RegisterCatalog("pt_BR", map[int]string{1: "fatura {invoice} não encontrada"})
+	RegisterCatalog("pt", map[int]string{2: "segundo erro"})
+
+	err1 := WithFields(New(1, "select * from invoices where id=42"), map[string]interface{}{"invoice": 42})
+	tests := []struct {
+		in   error
+		lang string
+		want string
+	}{
+		{nil, "nl", ""},
+		{errors.New("x"), "nl", "x"},
+		{err1, "nl", "factuur 42 niet gevonden"},
+		{err1, "NL-be", "factuur 42 niet gevonden"},
+		{err1, "pt-BR", "fatura 42 não encontrada"},
+		{err1, "pt", "invoice 42 not found"},
+		{err1, "en", "invoice 42 not found"},
+		{New(2, "x"), "nl", "fout 2: {unknown} {"},
+		{New(2, "x"), "pt-BR", "segundo erro"},
+		{New(2, "x"), "de", "Second error."},
+		{Wrap(3, New(2, "x"), "y"), "nl", "x: y"},
+		{WithPublic(New(3, "x"), "public"), "nl", "public"},
+		{WithPublic(New(1, "x"), "public"), "nl", "factuur {invoice} niet gevonden"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Localize(tt.in, tt.lang)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	if l := fmt.Sprint(DefaultRegistry.Languages()); l != "[nl pt pt-br]" {
+		t.Errorf("Languages: %s", l)
+	}
+}
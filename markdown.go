@@ -0,0 +1,69 @@
+package guru
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteMarkdown writes a Markdown table of all registered codes to w.
+func (r *Registry) WriteMarkdown(w io.Writer) error {
+	b := new(strings.Builder)
+	b.WriteString("| Code | Name | Category | HTTP status | Description |\n")
+	b.WriteString("| ---: | ---- | -------- | ----------: | ----------- |\n")
+	for _, info := range r.All() {
+		desc := mdEscape(info.Description)
+		if info.Deprecated {
+			desc = strings.TrimLeft(desc+" **"+mdEscape(deprecationNote(info))+"**", " ")
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n",
+			fmtCode(info.Code), mdEscape(info.Name), mdEscape(info.Category),
+			itoa(info.HTTPStatus), desc)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTable = template.Must(template.New("").Funcs(template.FuncMap{
+	"deprecationNote": deprecationNote,
+	"code":            fmtCode,
+}).Parse(`<table>
+<thead><tr><th>Code</th><th>Name</th><th>Category</th><th>HTTP status</th><th>Description</th></tr></thead>
+<tbody>
+{{- range . }}
+<tr><td>{{ code .Code }}</td><td>{{ .Name }}</td><td>{{ .Category }}</td><td>{{ if .HTTPStatus }}{{ .HTTPStatus }}{{ end }}</td><td>{{ .Description }}{{ if .Deprecated }} <strong>{{ deprecationNote . }}</strong>{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+`))
+
+// WriteHTML writes a HTML table of all registered codes to w.
+func (r *Registry) WriteHTML(w io.Writer) error {
+	return htmlTable.Execute(w, r.All())
+}
+
+// deprecationNote describes the deprecation for the documentation.
+func deprecationNote(info Info) string {
+	msg := "Deprecated"
+	if info.DeprecationReason != "" {
+		msg += ": " + info.DeprecationReason
+	}
+	if info.ReplacedBy != 0 {
+		msg += "; use " + fmtCode(info.ReplacedBy) + " instead"
+	}
+	return msg + "."
+}
+
+// itoa converts n to a string, or an empty string if it's 0.
+func itoa(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+var mdReplacer = strings.NewReplacer(`|`, `\|`, "\n", " ", `\`, `\\`)
+
+func mdEscape(s string) string { return mdReplacer.Replace(s) }
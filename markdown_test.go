@@ -0,0 +1,56 @@
+package guru
+
+import (
+	"strings"
+	"testing"
+)
+
+func testRegistry() *Registry {
+	r := &Registry{}
+	r.Register(4012, "ErrInvoiceMissing", "The invoice | bill\ndoesn't exist.")
+	r.Register(1, "ErrFirst", "<First> error.")
+	r.RegisterCategory(4000, 4999, "billing")
+	r.RegisterHTTPStatus(4012, 404)
+	r.Deprecate(410, 411, "reworded")
+	return r
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	b := new(strings.Builder)
+	if err := testRegistry().WriteMarkdown(b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+| Code | Name | Category | HTTP status | Description |
+| ---: | ---- | -------- | ----------: | ----------- |
+| 1 | ErrFirst |  |  | <First> error. |
+| 410 |  |  |  | **Deprecated: reworded; use 411 instead.** |
+| 4012 | ErrInvoiceMissing | billing | 404 | The invoice \| bill doesn't exist. |
+`[1:]
+	if b.String() != want {
+		t.Errorf("\nout:\n%s\nwant:\n%s", b, want)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	b := new(strings.Builder)
+	if err := testRegistry().WriteHTML(b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+<table>
+<thead><tr><th>Code</th><th>Name</th><th>Category</th><th>HTTP status</th><th>Description</th></tr></thead>
+<tbody>
+<tr><td>1</td><td>ErrFirst</td><td></td><td></td><td>&lt;First&gt; error.</td></tr>
+<tr><td>410</td><td></td><td></td><td></td><td> <strong>Deprecated: reworded; use 411 instead.</strong></td></tr>
+<tr><td>4012</td><td>ErrInvoiceMissing</td><td>billing</td><td>404</td><td>The invoice | bill
+doesn&#39;t exist.</td></tr>
+</tbody>
+</table>
+`[1:]
+	if b.String() != want {
+		t.Errorf("\nout:\n%s\nwant:\n%s", b, want)
+	}
+}
@@ -0,0 +1,101 @@
+package guru
+
+import (
+	"errors"
+	"strings"
+)
+
+// Matcher reports if an error matches some condition; see Match.
+type Matcher func(error) bool
+
+// Match reports if err matches all of the matchers:
+//
+//	switch {
+//	case guru.Match(err, guru.CodeIn(100, 101), guru.MsgContains("timeout")):
+//		...
+//	case guru.Match(err, guru.CategoryIs("storage")):
+//		...
+//	}
+//
+// It will return false if err is nil, and true if err is not nil and there are
+// no matchers.
+func Match(err error, m ...Matcher) bool {
+	if err == nil {
+		return false
+	}
+	for _, mm := range m {
+		if !mm(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// CodeIn matches errors where the highest-level code (as returned by Code) is
+// one of codes.
+func CodeIn(codes ...int) Matcher {
+	return func(err error) bool {
+		if !hasCode(err) {
+			return false
+		}
+		c := Code(err)
+		for _, cc := range codes {
+			if c == cc {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasCodeIn matches errors where any error in the chain has one of codes.
+func HasCodeIn(codes ...int) Matcher {
+	return func(err error) bool {
+		for _, c := range codes {
+			if Has(err, c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CategoryIs matches errors in the category name, as returned by Category.
+func CategoryIs(name string) Matcher {
+	return func(err error) bool { return Category(err) == name }
+}
+
+// MsgContains matches errors where the message of the error or any of the
+// errors it wraps contains s.
+func MsgContains(s string) Matcher {
+	return func(err error) bool {
+		return !walk(err, func(err error) bool { return !strings.Contains(err.Error(), s) })
+	}
+}
+
+// Target matches errors where errors.Is(err, target) is true.
+func Target(target error) Matcher {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+// RetryableIs matches errors where Retryable(err) is retry.
+func RetryableIs(retry bool) Matcher {
+	return func(err error) bool { return Retryable(err) == retry }
+}
+
+// Any matches errors that match at least one of the matchers.
+func Any(m ...Matcher) Matcher {
+	return func(err error) bool {
+		for _, mm := range m {
+			if mm(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches errors that don't match m.
+func Not(m Matcher) Matcher {
+	return func(err error) bool { return !m(err) }
+}
@@ -0,0 +1,53 @@
+package guru
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	resetRegistry(t)
+	RegisterCategory(1000, 1999, "storage")
+
+	var (
+		storage = Wrap(1001, New(100, "read timeout"), "load")
+		plain   = fmt.Errorf("x: %w", io.EOF)
+	)
+
+	tests := []struct {
+		in   error
+		m    []Matcher
+		want bool
+	}{
+		{nil, nil, false},
+		{nil, []Matcher{Not(CodeIn(1))}, false},
+		{plain, nil, true},
+
+		{storage, []Matcher{CodeIn(1001)}, true},
+		{storage, []Matcher{CodeIn(100, 101)}, false},
+		{storage, []Matcher{HasCodeIn(100, 101)}, true},
+		{plain, []Matcher{CodeIn(0)}, false},
+		{storage, []Matcher{CategoryIs("storage")}, true},
+		{storage, []Matcher{CategoryIs("other")}, false},
+		{storage, []Matcher{MsgContains("timeout")}, true},
+		{storage, []Matcher{HasCodeIn(100), CategoryIs("storage"), MsgContains("timeout")}, true},
+		{storage, []Matcher{HasCodeIn(100), CategoryIs("storage"), MsgContains("refused")}, false},
+
+		{plain, []Matcher{Target(io.EOF)}, true},
+		{storage, []Matcher{Target(io.EOF)}, false},
+		{MarkRetryable(storage), []Matcher{RetryableIs(true)}, true},
+		{storage, []Matcher{RetryableIs(false)}, true},
+
+		{storage, []Matcher{Any(CodeIn(1), MsgContains("load"))}, true},
+		{storage, []Matcher{Any()}, false},
+		{storage, []Matcher{Not(CodeIn(1001))}, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := Match(tt.in, tt.m...); out != tt.want {
+				t.Errorf("\nout:  %t\nwant: %t\n", out, tt.want)
+			}
+		})
+	}
+}
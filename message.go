@@ -0,0 +1,55 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RegisterMessage registers the canonical message for the error code code,
+// which is used by NewFromRegistry and Errorb.
+//
+// For Errorb the message is used as a format string, so it can contain verbs
+// such as %s.
+func (r *Registry) RegisterMessage(code int, msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.msg == nil {
+		r.msg = make(map[int]string)
+	}
+	r.msg[code] = msg
+}
+
+// Message gets the message for code registered with RegisterMessage. If
+// there is no message it will use the description registered with Register,
+// or an empty string if there's no description either.
+func (r *Registry) Message(code int) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if m, ok := r.msg[code]; ok {
+		return m
+	}
+	return r.info[code].Description
+}
+
+// RegisterMessage calls DefaultRegistry.RegisterMessage.
+func RegisterMessage(code int, msg string) { DefaultRegistry.RegisterMessage(code, msg) }
+
+// NewFromRegistry returns a new error with an error code, using the message
+// from DefaultRegistry.Message().
+func NewFromRegistry(code int) error {
+	msg := DefaultRegistry.Message(code)
+	return created(KindNew, code, msg, nil, &withCode{
+		error: errors.New(msg),
+		code:  code,
+	})
+}
+
+// Errorb returns a new error with an error code, using the message from
+// DefaultRegistry.Message() as the format string.
+func Errorb(code int, args ...interface{}) error {
+	e := fmt.Errorf(DefaultRegistry.Message(code), args...)
+	return created(KindNew, code, e.Error(), nil, &withCode{
+		error: e,
+		code:  code,
+	})
+}
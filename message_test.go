@@ -0,0 +1,35 @@
+package guru
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewFromRegistry(t *testing.T) {
+	resetRegistry(t)
+	Register(1, "ErrOne", "The first error.")
+	RegisterMessage(2, "no such invoice: %d")
+
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{NewFromRegistry(1), "error 1: The first error."},
+		{NewFromRegistry(2), "error 2: no such invoice: %d"},
+		{NewFromRegistry(3), "error 3: "},
+		{Errorb(2, 42), "error 2: no such invoice: 42"},
+		{Errorb(1), "error 1: The first error."},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := fmt.Sprintf("%v", tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	if info, _ := DefaultRegistry.Lookup(2); info.Message != "no such invoice: %d" {
+		t.Errorf("Lookup: %#v", info)
+	}
+}
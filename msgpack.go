@@ -0,0 +1,215 @@
+package guru
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// MarshalMsgpack encodes err as MessagePack, with the same structure as
+// MarshalJSON. Use FromMsgpack to decode it.
+func MarshalMsgpack(err error) ([]byte, error) {
+	v, vErr := toValue(err)
+	if vErr != nil {
+		return nil, vErr
+	}
+	return msgpackValue(nil, v), nil
+}
+
+// FromMsgpack decodes an error encoded with MarshalMsgpack.
+//
+// The errors in the chain won't be of the same type as the original errors;
+// see FromJSON. It will return nil if data is the MessagePack nil value.
+func FromMsgpack(data []byte) (error, error) {
+	r := &reader{data: data}
+	v, err := r.msgpack()
+	if err == nil && len(r.data) > 0 {
+		err = fmt.Errorf("%d bytes of trailing data", len(r.data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("guru.FromMsgpack: %w", err)
+	}
+	return fromValue(v)
+}
+
+// msgpackHead writes the type byte for n elements: fix is the type for the
+// fixed-size form (if max is not 0), and t16 is the type for 16 bits, followed
+// by the type for 32 bits.
+func msgpackHead(b []byte, n int, fix byte, max int, t8, t16 byte) []byte {
+	switch {
+	case n < max:
+		return append(b, fix|byte(n))
+	case t8 != 0 && n <= math.MaxUint8:
+		return append(b, t8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, t16), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, t16+1), uint32(n))
+}
+
+func msgpackString(b []byte, s string) []byte {
+	return append(msgpackHead(b, len(s), 0xa0, 32, 0xd9, 0xda), s...)
+}
+
+func msgpackValue(b []byte, v interface{}) []byte {
+	switch vv := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if vv {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case int64:
+		switch {
+		case vv >= 0 && vv <= 0x7f, vv < 0 && vv >= -32:
+			return append(b, byte(vv))
+		case vv >= math.MinInt8 && vv <= math.MaxInt8:
+			return append(b, 0xd0, byte(vv))
+		case vv >= math.MinInt16 && vv <= math.MaxInt16:
+			return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(vv))
+		case vv >= math.MinInt32 && vv <= math.MaxInt32:
+			return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(vv))
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(vv))
+	case float64:
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(vv))
+	case string:
+		return msgpackString(b, vv)
+	case []interface{}:
+		b = msgpackHead(b, len(vv), 0x90, 16, 0, 0xdc)
+		for _, e := range vv {
+			b = msgpackValue(b, e)
+		}
+		return b
+	case map[string]interface{}:
+		b = msgpackHead(b, len(vv), 0x80, 16, 0, 0xde)
+		for _, k := range sortedKeys(vv) {
+			b = msgpackValue(msgpackString(b, k), vv[k])
+		}
+		return b
+	}
+	panic(fmt.Sprintf("guru: unexpected type %T", v)) // Never happens: toValue returns only the above.
+}
+
+func (r *reader) msgpack() (interface{}, error) {
+	t, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t <= 0x7f:
+		return int64(t), nil
+	case t >= 0xe0:
+		return int64(int8(t)), nil
+	case t >= 0xa0 && t <= 0xbf:
+		s, err := r.bytes(uint64(t & 0x1f))
+		return string(s), err
+	case t >= 0x90 && t <= 0x9f:
+		return r.msgpackArray(uint64(t & 0x0f))
+	case t >= 0x80 && t <= 0x8f:
+		return r.msgpackMap(uint64(t & 0x0f))
+	}
+
+	switch t {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := r.uint(1 << (t - 0xcc))
+		if n > math.MaxInt64 {
+			return float64(n), err
+		}
+		return int64(n), err
+	case 0xd0:
+		n, err := r.uint(1)
+		return int64(int8(n)), err
+	case 0xd1:
+		n, err := r.uint(2)
+		return int64(int16(n)), err
+	case 0xd2:
+		n, err := r.uint(4)
+		return int64(int32(n)), err
+	case 0xd3:
+		n, err := r.uint(8)
+		return int64(n), err
+	case 0xca:
+		n, err := r.uint(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := r.uint(8)
+		return math.Float64frombits(n), err
+	case 0xd9, 0xda, 0xdb, 0xc4, 0xc5, 0xc6: // str and bin
+		size := 1 << (t - 0xd9)
+		if t <= 0xc6 {
+			size = 1 << (t - 0xc4)
+		}
+		n, err := r.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		s, err := r.bytes(n)
+		return string(s), err
+	case 0xdc, 0xdd:
+		n, err := r.uint(2 << (t - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackArray(n)
+	case 0xde, 0xdf:
+		n, err := r.uint(2 << (t - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackMap(n)
+	}
+	return nil, fmt.Errorf("unsupported type 0x%02x", t)
+}
+
+func (r *reader) msgpackArray(n uint64) (interface{}, error) {
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { r.depth-- }()
+	if err := r.check(n); err != nil {
+		return nil, err
+	}
+	a := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v, err := r.msgpack()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+func (r *reader) msgpackMap(n uint64) (interface{}, error) {
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { r.depth-- }()
+	if err := r.check(n); err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{}, n)
+	for i := uint64(0); i < n; i++ {
+		k, err := r.msgpack()
+		if err != nil {
+			return nil, err
+		}
+		ks, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key is %T, not a string", k)
+		}
+		if m[ks], err = r.msgpack(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
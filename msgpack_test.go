@@ -0,0 +1,59 @@
+package guru
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMsgpack(t *testing.T) {
+	for i, tt := range binaryTests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			b, err := MarshalMsgpack(tt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := FromMsgpack(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testBinaryRoundTrip(t, tt, out)
+		})
+	}
+}
+
+func TestMsgpackEncoding(t *testing.T) {
+	b, err := MarshalMsgpack(New(-1, "x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"code": -1, "message": "x"}
+	if h, want := hex.EncodeToString(b), "82a4636f6465ffa76d657373616765a178"; h != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", h, want)
+	}
+}
+
+func TestFromMsgpackErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "unexpected end of data"},
+		{"82a4636f6465ff", "unexpected end of data"},
+		{"81a4636f6465ff00", "trailing data"},
+		{"810101", "map key is int64"},
+		{"01", "not a map"},
+		{"ddffffffff", "unexpected end of data"},
+		{"81a4636f6465c1", "unsupported type 0xc1"},
+		{strings.Repeat("91", 1001) + "01", "nested too deeply"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			b, _ := hex.DecodeString(tt.in)
+			_, err := FromMsgpack(b)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("\nout:  %v\nwant: %v\n", err, tt.want)
+			}
+		})
+	}
+}
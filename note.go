@@ -0,0 +1,52 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type withNote struct {
+	error
+	msg string
+}
+
+func (e *withNote) Unwrap() error { return e.error }
+func (e *withNote) Error() string { return e.msg + ": " + e.error.Error() }
+func (e withNote) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%s\n%+v", e.msg, e.error)
+	case verb == 's':
+		fmt.Fprintf(s, "%s: %s", e.msg, e.error)
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.msg+": "+fmt.Sprintf("%s", e.error))
+	default:
+		fmt.Fprintf(s, "%s: %v", e.msg, e.error)
+	}
+}
+
+// Note annotates err with a message, without changing the code: Code() will
+// still return the code of err (if any). This is useful for layers that want to
+// add context but have no opinion on the code.
+//
+// The message is added before the message of err, like fmt.Errorf("msg: %w")
+// does:
+//
+//	err := guru.Note(guru.New(4012, "no such invoice"), "charge card")
+//	guru.Code(err)         // 4012
+//	fmt.Sprintf("%v", err) // charge card: error 4012: no such invoice
+//
+// It will return nil if err is nil.
+func Note(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &withNote{error: err, msg: msg}
+}
+
+// Notef is like Note, but with a format specifier.
+func Notef(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &withNote{error: err, msg: fmt.Sprintf(format, args...)}
+}
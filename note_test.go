@@ -0,0 +1,49 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNote(t *testing.T) {
+	tests := []struct {
+		in                    error
+		wantCode              int
+		want, wantS, wantPlus string
+	}{
+		{Note(nil, "x"), 0, "<nil>", "%!s(<nil>)", "<nil>"},
+		{Notef(nil, "x"), 0, "<nil>", "%!s(<nil>)", "<nil>"},
+		{Note(errors.New("x"), "y"), 0, "y: x", "y: x", "y\nx"},
+		{Note(New(4012, "no such invoice"), "charge card"), 4012,
+			"charge card: error 4012: no such invoice", "charge card: no such invoice",
+			"charge card\nerror 4012: no such invoice"},
+		{Notef(Wrap(2, New(1, "x"), "y"), "z %d", 42), 2,
+			"z 42: error 2: error 1: x: y", "z 42: x: y", "z 42\nerror 2: y\nerror 1: x"},
+		{Wrap(3, Note(New(1, "x"), "y"), "z"), 3, "error 3: y: error 1: x: z", "y: x: z", "error 3: z\ny\nerror 1: x"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if c := Code(tt.in); c != tt.wantCode {
+				t.Errorf("code\nout:  %d\nwant: %d\n", c, tt.wantCode)
+			}
+			if out := fmt.Sprintf("%v", tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if out := fmt.Sprintf("%s", tt.in); out != tt.wantS {
+				t.Errorf("%%s\nout:  %#v\nwant: %#v\n", out, tt.wantS)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlus)
+			}
+		})
+	}
+
+	err := Note(New(1, "x"), "y")
+	if err.Error() != "y: x" {
+		t.Errorf("Error(): %q", err.Error())
+	}
+	if j, _ := MarshalJSON(err); string(j) != `{"message":"y","wrapped":{"code":1,"message":"x"}}` {
+		t.Errorf("JSON: %s", j)
+	}
+}
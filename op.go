@@ -0,0 +1,41 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type withOp struct {
+	error
+	op string
+}
+
+func (e *withOp) Unwrap() error { return e.error }
+func (e withOp) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.error) {
+		fmt.Fprintf(s, "\nop: %s", e.op)
+	}
+}
+
+// WithOp annotates err with the logical operation that was being performed,
+// such as "billing.ChargeCard". The operations are printed with the %+v verb,
+// so the path through the code is visible even without stack traces. It will
+// return nil if err is nil.
+func WithOp(err error, op string) error {
+	if err == nil {
+		return nil
+	}
+	return &withOp{error: err, op: op}
+}
+
+// Ops gets all operations added with WithOp, from the outermost error to the
+// innermost. It will return nil if there are none.
+func Ops(err error) []string {
+	var ops []string
+	walk(err, func(err error) bool {
+		if o, ok := err.(*withOp); ok {
+			ops = append(ops, o.op)
+		}
+		return true
+	})
+	return ops
+}
@@ -0,0 +1,38 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOps(t *testing.T) {
+	tests := []struct {
+		in       error
+		want     []string
+		wantPlus string
+	}{
+		{nil, nil, "<nil>"},
+		{WithOp(nil, "x"), nil, "<nil>"},
+		{errors.New("x"), nil, "x"},
+		{WithOp(New(1, "x"), "billing.ChargeCard"), []string{"billing.ChargeCard"}, "error 1: x\nop: billing.ChargeCard"},
+		{WithOp(Wrap(2, WithOp(New(1, "x"), "db.Query"), "y"), "billing.ChargeCard"),
+			[]string{"billing.ChargeCard", "db.Query"}, "error 2: y\nerror 1: x\nop: db.Query\nop: billing.ChargeCard"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := Ops(tt.in); !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlus)
+			}
+		})
+	}
+
+	err := WithOp(New(1, "x"), "op")
+	if fmt.Sprintf("%v", err) != "error 1: x" || Code(err) != 1 {
+		t.Errorf("%v", err)
+	}
+}
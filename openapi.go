@@ -0,0 +1,138 @@
+package guru
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type (
+	openAPI struct {
+		Components openAPIComponents `json:"components"`
+	}
+	openAPIComponents struct {
+		Schemas   map[string]interface{}     `json:"schemas"`
+		Responses map[string]openAPIResponse `json:"responses"`
+	}
+	openAPIResponse struct {
+		Description string                      `json:"description"`
+		Content     map[string]openAPIMediaType `json:"content"`
+	}
+	openAPIMediaType struct {
+		Schema   map[string]string         `json:"schema"`
+		Examples map[string]openAPIExample `json:"examples,omitempty"`
+	}
+	openAPIExample struct {
+		Summary     string      `json:"summary,omitempty"`
+		Description string      `json:"description,omitempty"`
+		Value       interface{} `json:"value"`
+	}
+)
+
+// WriteOpenAPI writes OpenAPI 3 components for the error responses of all
+// registered codes to w, as JSON:
+//
+//	{
+//	  "components": {
+//	    "schemas": {"Error": {...}},
+//	    "responses": {
+//	      "Error404": {
+//	        "description": "Not Found",
+//	        "content": {"application/json": {
+//	          "schema": {"$ref": "#/components/schemas/Error"},
+//	          "examples": {"ErrInvoiceMissing": {
+//	            "summary": "4012 ErrInvoiceMissing",
+//	            "value": {"code": 4012, "message": "invoice not found"}
+//	          }}
+//	        }}
+//	      }
+//	    }
+//	  }
+//	}
+//
+// There is a response for every HTTP status, with an example for every code
+// with that status. The status and message are what HTTPStatus and Public
+// use, and the schema is the format guruhttp.WriteError uses. The components
+// can be referenced from an API spec, for example with
+// {"$ref": "errors.json#/components/responses/Error404"}.
+func (r *Registry) WriteOpenAPI(w io.Writer) error {
+	var (
+		all   = r.All()
+		codes = make([]int, 0, len(all))
+	)
+	for _, info := range all {
+		codes = append(codes, info.Code)
+	}
+	doc := openAPI{Components: openAPIComponents{
+		Schemas:   map[string]interface{}{"Error": openAPISchema(codes)},
+		Responses: make(map[string]openAPIResponse),
+	}}
+
+	for _, info := range all {
+		status := 500
+		switch {
+		case info.HTTPStatus != 0:
+			status = info.HTTPStatus
+		case info.Code >= 100 && info.Code <= 599:
+			status = info.Code
+		}
+		key := "Error" + strconv.Itoa(status)
+		resp, ok := doc.Components.Responses[key]
+		if !ok {
+			resp = openAPIResponse{
+				Description: http.StatusText(status),
+				Content: map[string]openAPIMediaType{"application/json": {
+					Schema:   map[string]string{"$ref": "#/components/schemas/Error"},
+					Examples: make(map[string]openAPIExample),
+				}},
+			}
+			if resp.Description == "" {
+				resp.Description = "Error " + strconv.Itoa(status)
+			}
+			doc.Components.Responses[key] = resp
+		}
+
+		msg := info.Message
+		if msg == "" {
+			msg = "internal error"
+		}
+		name := info.Name
+		if name == "" {
+			name = "E" + strconv.Itoa(info.Code)
+		}
+		ex := openAPIExample{
+			Summary:     strconv.Itoa(info.Code) + " " + info.Name,
+			Description: info.Description,
+			Value:       map[string]interface{}{"code": info.Code, "message": msg},
+		}
+		if info.Deprecated {
+			ex.Description += " " + deprecationNote(info)
+		}
+		ex.Summary, ex.Description = strings.TrimSpace(ex.Summary), strings.TrimSpace(ex.Description)
+		resp.Content["application/json"].Examples[name] = ex
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func openAPISchema(codes []int) map[string]interface{} {
+	code := map[string]interface{}{"type": "integer", "description": "Error code."}
+	if len(codes) > 0 {
+		code["enum"] = codes
+	}
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"message"},
+		"properties": map[string]interface{}{
+			"code":       code,
+			"message":    map[string]interface{}{"type": "string", "description": "Message that's safe to show to users."},
+			"request_id": map[string]interface{}{"type": "string", "description": "Request ID, if there is one."},
+			"error_id":   map[string]interface{}{"type": "string", "description": "Unique ID of the error, if there is one."},
+		},
+	}
+}
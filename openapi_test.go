@@ -0,0 +1,119 @@
+package guru
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteOpenAPI(t *testing.T) {
+	r := testRegistry()
+	r.RegisterMessage(4012, "invoice not found")
+
+	b := new(strings.Builder)
+	if err := r.WriteOpenAPI(b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+{
+  "components": {
+    "schemas": {
+      "Error": {
+        "properties": {
+          "code": {
+            "description": "Error code.",
+            "enum": [
+              1,
+              410,
+              4012
+            ],
+            "type": "integer"
+          },
+          "error_id": {
+            "description": "Unique ID of the error, if there is one.",
+            "type": "string"
+          },
+          "message": {
+            "description": "Message that's safe to show to users.",
+            "type": "string"
+          },
+          "request_id": {
+            "description": "Request ID, if there is one.",
+            "type": "string"
+          }
+        },
+        "required": [
+          "message"
+        ],
+        "type": "object"
+      }
+    },
+    "responses": {
+      "Error404": {
+        "description": "Not Found",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            },
+            "examples": {
+              "ErrInvoiceMissing": {
+                "summary": "4012 ErrInvoiceMissing",
+                "description": "The invoice | bill\ndoesn't exist.",
+                "value": {
+                  "code": 4012,
+                  "message": "invoice not found"
+                }
+              }
+            }
+          }
+        }
+      },
+      "Error410": {
+        "description": "Gone",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            },
+            "examples": {
+              "E410": {
+                "summary": "410",
+                "description": "Deprecated: reworded; use 411 instead.",
+                "value": {
+                  "code": 410,
+                  "message": "internal error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "Error500": {
+        "description": "Internal Server Error",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            },
+            "examples": {
+              "ErrFirst": {
+                "summary": "1 ErrFirst",
+                "description": "<First> error.",
+                "value": {
+                  "code": 1,
+                  "message": "internal error"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`[1:]
+	if b.String() != want {
+		t.Errorf("\nout:\n%s\nwant:\n%s", b, want)
+	}
+}
@@ -0,0 +1,74 @@
+package guru
+
+import (
+	"errors"
+)
+
+// Option is an option for E.
+type Option func(*options)
+
+type options struct {
+	msg    string
+	cause  error
+	fields map[string]interface{}
+	stack  bool
+	skip   int
+}
+
+// Msg sets the error message.
+func Msg(msg string) Option { return func(o *options) { o.msg = msg } }
+
+// Cause sets the error to wrap.
+func Cause(err error) Option { return func(o *options) { o.cause = err } }
+
+// Field adds a field, as with WithFields.
+func Field(k string, v interface{}) Option {
+	return func(o *options) {
+		if o.fields == nil {
+			o.fields = make(map[string]interface{})
+		}
+		o.fields[k] = v
+	}
+}
+
+// Stack records the call stack, as with WithStack.
+func Stack() Option { return func(o *options) { o.stack = true } }
+
+// CallerSkip skips n more stack frames to find the location of the call for
+// hooks, the stack trace, and the location in panics from SetStrict, for
+// helper functions that create errors on behalf of their caller.
+func CallerSkip(n int) Option { return func(o *options) { o.skip = n } }
+
+// E creates a new error with the given code and options:
+//
+//	guru.E(41, guru.Msg("boom"), guru.Cause(err), guru.Field("user", id), guru.Stack())
+//
+// This is the same as New() if there is no cause, WithCode() if there is a
+// cause but no message, or Wrap() if there is both. Unlike the other
+// functions it will never return nil.
+func E(code int, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var err error
+	switch {
+	case o.cause == nil:
+		err = &withCode{error: errors.New(o.msg), code: code}
+	case o.msg == "":
+		err = &withCode{error: o.cause, code: code}
+	default:
+		err = &wrapped{msg: o.msg, code: code, error: o.cause}
+	}
+	if o.fields != nil {
+		err = &withFields{error: err, fields: o.fields}
+	}
+	if o.stack {
+		err = &withStack{error: err, stack: callers(1 + o.skip)}
+	}
+	if o.cause != nil {
+		return createdSkip(o.skip, KindWrap, code, o.msg, o.cause, err)
+	}
+	return createdSkip(o.skip, KindNew, code, o.msg, nil, err)
+}
@@ -0,0 +1,71 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestE(t *testing.T) {
+	cause := errors.New("cause")
+	tests := []struct {
+		in         error
+		want       string
+		wantCodes  []int
+		wantFields map[string]interface{}
+		wantStack  bool
+	}{
+		{E(1), "error 1: ", []int{1}, nil, false},
+		{E(1, Msg("boom")), "error 1: boom", []int{1}, nil, false},
+		{E(1, Cause(cause)), "error 1: cause", []int{1}, nil, false},
+		{E(1, Cause(New(2, "cause"))), "error 1: error 2: cause", []int{1, 2}, nil, false},
+		{E(1, Msg("boom"), Cause(cause)), "error 1: cause: boom", []int{1}, nil, false},
+		{E(1, Msg("boom"), Field("a", 1), Field("b", 2)), "error 1: boom", []int{1},
+			map[string]interface{}{"a": 1, "b": 2}, false},
+		{E(1, Msg("boom"), Stack()), "error 1: boom", []int{1}, nil, true},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := fmt.Sprintf("%v", tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if out := Codes(tt.in); !reflect.DeepEqual(out, tt.wantCodes) {
+				t.Errorf("codes\nout:  %#v\nwant: %#v\n", out, tt.wantCodes)
+			}
+			if out := Fields(tt.in); !reflect.DeepEqual(out, tt.wantFields) {
+				t.Errorf("fields\nout:  %#v\nwant: %#v\n", out, tt.wantFields)
+			}
+			st := StackTrace(tt.in)
+			if tt.wantStack != (st != nil) {
+				t.Errorf("stack: %v", st)
+			}
+			if st != nil && st[0].Function != "zgo.at/guru.TestE" {
+				t.Errorf("stack: %v", st[0].Function)
+			}
+		})
+	}
+}
+
+func TestCallerSkip(t *testing.T) {
+	var ev Event
+	t.Cleanup(AddHook(func(e Event) { ev = e }))
+
+	helper := func() error { return E(1, Msg("x"), CallerSkip(1), Stack()) }
+	err, line := helper(), callerLine()
+
+	if ev.Caller.Line != line || !strings.HasSuffix(ev.Caller.File, "option_test.go") {
+		t.Errorf("wrong caller: %s:%d; want line %d", ev.Caller.File, ev.Caller.Line, line)
+	}
+	if st := StackTrace(err); len(st) == 0 || st[0].Line != line {
+		t.Errorf("wrong stack: %v", st)
+	}
+}
+
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
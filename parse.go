@@ -0,0 +1,55 @@
+package guru
+
+import (
+	"errors"
+	"regexp"
+)
+
+var reParse = regexp.MustCompile(`^error (` + reCode + `)(?:\.(` + reCode + `))?(?:: |$)`)
+
+// Parse reconstructs an error from the text of an error from this package
+// printed with %v, such as:
+//
+//	error 42: oh noes
+//	error 2: error 1: oh noes: context
+//
+// The codes and subcodes are preserved, and the message of the error (and
+// therefore the %v output) is identical. Which parts of the message were added
+// by which error can't be known from the text, so the entire message is used
+// for the innermost error.
+//
+// This is useful if errors are only transported as strings, for example when
+// capturing the stderr of a program. Only the %v output works, as Error()
+// doesn't include the codes. It will return false if s doesn't start with
+// "error" followed by a code.
+func Parse(s string) (error, bool) {
+	type code struct{ code, sub int }
+	var codes []code
+	for {
+		m := reParse.FindStringSubmatch(s)
+		if m == nil {
+			break
+		}
+		c, err := ParseCode(m[1])
+		if err != nil {
+			break
+		}
+		var sub int
+		if m[2] != "" {
+			if sub, err = ParseCode(m[2]); err != nil {
+				break
+			}
+		}
+		codes = append(codes, code{c, sub})
+		s = s[len(m[0]):]
+	}
+	if len(codes) == 0 {
+		return nil, false
+	}
+
+	var err error = errors.New(s)
+	for i := len(codes) - 1; i >= 0; i-- {
+		err = &withCode{error: err, code: codes[i].code, sub: codes[i].sub}
+	}
+	return err, true
+}
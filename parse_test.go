@@ -0,0 +1,83 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantOK    bool
+		wantCodes []int
+		wantSub   int
+		wantS     string
+	}{
+		{"", false, nil, 0, ""},
+		{"oh noes", false, nil, 0, ""},
+		{"error: oh noes", false, nil, 0, ""},
+		{"error 42x: oh noes", false, nil, 0, ""},
+		{"error 99999999999999999999: x", false, nil, 0, ""},
+		{"error 42: oh noes", true, []int{42}, 0, "oh noes"},
+		{"error 42: ", true, []int{42}, 0, ""},
+		{"error -1: oh noes", true, []int{-1}, 0, "oh noes"},
+		{"error 42.3: oh noes", true, []int{42}, 3, "oh noes"},
+		{"error 2: error 1: x: y", true, []int{2, 1}, 0, "x: y"},
+		{"error 2: context: error 1: x", true, []int{2}, 0, "context: error 1: x"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, ok := Parse(tt.in)
+			if ok != tt.wantOK {
+				t.Fatalf("ok: %t", ok)
+			}
+			if !ok {
+				if out != nil {
+					t.Errorf("not nil: %#v", out)
+				}
+				return
+			}
+			if c := Codes(out); !reflect.DeepEqual(c, tt.wantCodes) {
+				t.Errorf("codes\nout:  %v\nwant: %v\n", c, tt.wantCodes)
+			}
+			if s := Subcode(out); s != tt.wantSub {
+				t.Errorf("subcode\nout:  %d\nwant: %d\n", s, tt.wantSub)
+			}
+			if s := fmt.Sprintf("%s", out); s != tt.wantS {
+				t.Errorf("%%s\nout:  %q\nwant: %q\n", s, tt.wantS)
+			}
+			if v := fmt.Sprintf("%v", out); v != tt.in {
+				t.Errorf("%%v\nout:  %q\nwant: %q\n", v, tt.in)
+			}
+		})
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []error{
+		New(42, "oh noes"),
+		NewSub(42, 3, "oh noes"),
+		Wrap(2, New(1, "x"), "y"),
+		WithCode(3, Wrapf(2, errors.New("x"), "y %d", 1)),
+		WithFields(New(1, "x"), map[string]interface{}{"k": "v"}),
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, ok := Parse(fmt.Sprintf("%v", tt))
+			if !ok {
+				t.Fatal("not ok")
+			}
+			if a, b := fmt.Sprintf("%v", out), fmt.Sprintf("%v", tt); a != b {
+				t.Errorf("\nout:  %q\nwant: %q\n", a, b)
+			}
+			if a, b := Codes(out), Codes(tt); !reflect.DeepEqual(a, b) {
+				t.Errorf("codes\nout:  %v\nwant: %v\n", a, b)
+			}
+			if a, b := Subcode(out), Subcode(tt); a != b {
+				t.Errorf("subcode\nout:  %v\nwant: %v\n", a, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package guru
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[1;31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// Fprint writes err to w for people reading it in a terminal, with every error
+// in the chain on its own line, followed by its operations and fields:
+//
+//	error 4012 (ErrInvoiceMissing): charge card
+//	    op: billing.Charge
+//	    fields: invoice=42
+//	error 500: query
+//	unexpected EOF
+//	    at main.query
+//	        /src/invoice.go:43
+//
+// The call stack (if any) is written at the end. If w is a terminal then the
+// codes are red, the operations cyan, the stack dimmed, and the root cause
+// bold. Colors are never used if the NO_COLOR environment variable is set or
+// TERM is "dumb". Nothing is written if err is nil.
+func Fprint(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	_, wErr := io.WriteString(w, render(err, colorize(w))+"\n")
+	return wErr
+}
+
+// colorize reports if w is a terminal that supports colors.
+func colorize(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
+
+func render(err error, color bool) string {
+	paint := func(c, s string) string {
+		if !color || s == "" {
+			return s
+		}
+		return c + s + ansiReset
+	}
+
+	var (
+		b       strings.Builder
+		reg     = RegistryOf(err)
+		errs    = multi(err)
+		entries = Flatten(err)
+	)
+	for i, e := range entries {
+		if e.Depth > 0 || (!e.HasCode && e.Message == "" && e.Ops == nil && e.Fields == nil) {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+
+		msg := e.Message
+		if i == len(entries)-1 && errs == nil {
+			msg = paint(ansiBold, msg)
+		}
+		if e.HasCode {
+			c := "error " + fmtCode(e.Code)
+			if e.Subcode != 0 {
+				c += "." + fmtCode(e.Subcode)
+			}
+			if n := reg.Name(e.Code); n != "" {
+				c += " (" + n + ")"
+			}
+			b.WriteString(paint(ansiRed, c))
+			if msg != "" {
+				b.WriteString(": ")
+			}
+		}
+		b.WriteString(msg)
+
+		if len(e.Ops) > 0 {
+			b.WriteString("\n    op: " + paint(ansiCyan, strings.Join(e.Ops, ", ")))
+		}
+		if len(e.Fields) > 0 {
+			keys := make([]string, 0, len(e.Fields))
+			for k := range e.Fields {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			b.WriteString("\n    fields:")
+			for _, k := range keys {
+				fmt.Fprintf(&b, " %s=%v", k, e.Fields[k])
+			}
+		}
+	}
+	if errs != nil {
+		writeBranches(&b, errs, b.Len() > 0, func(err error) string { return render(err, color) })
+	}
+
+	if st := StackTrace(err); len(st) > 0 && errs == nil {
+		for _, f := range st {
+			b.WriteString("\n" + paint(ansiDim, fmt.Sprintf("    at %s\n        %s:%d", f.Function, f.File, f.Line)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	resetRegistry(t)
+	Register(4012, "ErrInvoiceMissing", "")
+
+	tests := []struct {
+		in        error
+		want      string
+		wantColor string
+	}{
+		{io.EOF, "EOF", "\x1b[1mEOF\x1b[0m"},
+		{New(4012, "x"), "error 4012 (ErrInvoiceMissing): x",
+			"\x1b[1;31merror 4012 (ErrInvoiceMissing)\x1b[0m: \x1b[1mx\x1b[0m"},
+		{NewSub(5, 3, "x"), "error 5.3: x",
+			"\x1b[1;31merror 5.3\x1b[0m: \x1b[1mx\x1b[0m"},
+		{New(0, "x"), "error 0: x",
+			"\x1b[1;31merror 0\x1b[0m: \x1b[1mx\x1b[0m"},
+		{WithField(WithOp(Wrap(4012, WithCode(500, io.EOF), "charge"), "billing.Charge"), "id", 42),
+			"error 4012 (ErrInvoiceMissing): charge\n    op: billing.Charge\n    fields: id=42\nerror 500: EOF",
+			"\x1b[1;31merror 4012 (ErrInvoiceMissing)\x1b[0m: charge\n    op: \x1b[36mbilling.Charge\x1b[0m\n    fields: id=42\n" +
+				"\x1b[1;31merror 500\x1b[0m: \x1b[1mEOF\x1b[0m"},
+		{Wrap(2, errors.Join(New(1, "a"), errors.New("b")), "import"),
+			"error 2: import\n├─ error 1: a\n└─ b",
+			"\x1b[1;31merror 2\x1b[0m: import\n├─ \x1b[1;31merror 1\x1b[0m: \x1b[1ma\x1b[0m\n└─ \x1b[1mb\x1b[0m"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := render(tt.in, false); out != tt.want {
+				t.Errorf("plain\nout:\n%s\nwant:\n%s", out, tt.want)
+			}
+			if out := render(tt.in, true); out != tt.wantColor {
+				t.Errorf("color\nout:  %q\nwant: %q\n", out, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestRenderStack(t *testing.T) {
+	out := render(NewStack(1, "x"), true)
+	re := regexp.MustCompile(`^\x1b\[1;31merror 1\x1b\[0m: \x1b\[1mx\x1b\[0m\n` +
+		`\x1b\[2m    at zgo.at/guru.TestRenderStack\n        .*/print_test.go:\d+\x1b\[0m\n`)
+	if !re.MatchString(out) {
+		t.Errorf("%q", out)
+	}
+}
+
+func TestFprint(t *testing.T) {
+	b := new(strings.Builder)
+	if err := Fprint(b, New(1, "x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Fprint(b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if out := b.String(); out != "error 1: x\n" {
+		t.Errorf("%q", out)
+	}
+}
+
+func TestColorize(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	fp, err := os.Create(filepath.Join(t.TempDir(), "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fp.Close()
+	if colorize(fp) {
+		t.Error("file")
+	}
+	if colorize(new(strings.Builder)) {
+		t.Error("builder")
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		t.Skip("no tty:", err)
+	}
+	defer tty.Close()
+	if !colorize(tty) {
+		t.Error("tty")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if colorize(tty) {
+		t.Error("NO_COLOR")
+	}
+}
@@ -0,0 +1,63 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type withPublic struct {
+	error
+	public string
+}
+
+func (e *withPublic) Unwrap() error { return e.error }
+func (e withPublic) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.error) {
+		fmt.Fprintf(s, "\npublic: %s", e.public)
+	}
+}
+
+// WithPublic annotates err with a message that's safe to show to end users,
+// for example in an API response, which can be retrieved with Public. The
+// message of err isn't changed. It will return nil if err is nil.
+func WithPublic(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &withPublic{error: err, public: msg}
+}
+
+// Public gets the message for err that's safe to show to end users.
+//
+// This is the outermost message added with WithPublic. If there is none then
+// the registry's Message() is used for the code of err (see RegistryOf), and
+// if that's empty too then it returns "internal error". The message of err
+// itself is never used, as it may contain internal details. It will return an
+// empty string if err is nil.
+func Public(err error) string {
+	if err == nil {
+		return ""
+	}
+	if p, ok := publicMsg(err); ok {
+		return p
+	}
+	if code, ok := mappedCode(err); ok {
+		if m := RegistryOf(err).Message(code); m != "" {
+			return m
+		}
+	}
+	return "internal error"
+}
+
+func publicMsg(err error) (string, bool) {
+	var (
+		msg   string
+		found bool
+	)
+	walk(err, func(err error) bool {
+		if p, ok := err.(*withPublic); ok {
+			msg, found = p.public, true
+		}
+		return !found
+	})
+	return msg, found
+}
@@ -0,0 +1,46 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPublic(t *testing.T) {
+	resetRegistry(t)
+	RegisterMessage(2, "invoice not found")
+
+	tests := []struct {
+		in       error
+		want     string
+		wantPlus string
+	}{
+		{nil, "", "<nil>"},
+		{errors.New("dial tcp db1.internal"), "internal error", "dial tcp db1.internal"},
+		{New(1, "dial tcp db1.internal"), "internal error", "error 1: dial tcp db1.internal"},
+		{New(2, "select * from invoices"), "invoice not found", "error 2: select * from invoices"},
+		{WithPublic(New(1, "dial tcp db1.internal"), "please try again"), "please try again",
+			"error 1: dial tcp db1.internal\npublic: please try again"},
+		{Wrap(3, WithPublic(New(2, "x"), "inner"), "y"), "inner",
+			"error 3: y\nerror 2: x\npublic: inner"},
+		{WithPublic(WithPublic(New(2, "x"), "inner"), "outer"), "outer",
+			"error 2: x\npublic: inner\npublic: outer"},
+		{WithPublic(nil, "x"), "", "<nil>"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Public(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if plus := fmt.Sprintf("%+v", tt.in); plus != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", plus, tt.wantPlus)
+			}
+		})
+	}
+
+	err := WithPublic(New(1, "x"), "public")
+	if s := fmt.Sprintf("%s|%v", err, err); s != "x|error 1: x" {
+		t.Errorf("message changed: %q", s)
+	}
+}
@@ -0,0 +1,117 @@
+package guru
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Recorder records errors as they're created; see SetRecorder.
+type Recorder interface {
+	Record(Event)
+}
+
+// RecorderFunc is a function that implements Recorder.
+type RecorderFunc func(Event)
+
+// Record calls f.
+func (f RecorderFunc) Record(ev Event) { f(ev) }
+
+var (
+	recorderMu     sync.Mutex
+	recorderRemove func()
+)
+
+// SetRecorder sets the recorder that's called for every error that's created or
+// wrapped with one of the functions that accept an error code, replacing the
+// previous one. The recorder is removed if r is nil.
+//
+// This uses AddHook; use MultiRecorder to record to more than one destination:
+//
+//	guru.SetRecorder(guru.MultiRecorder(ring, guru.NewWriterRecorder(os.Stderr)))
+func SetRecorder(r Recorder) {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	if recorderRemove != nil {
+		recorderRemove()
+		recorderRemove = nil
+	}
+	if r != nil {
+		recorderRemove = AddHook(r.Record)
+	}
+}
+
+// MultiRecorder returns a recorder that records to all of rs, in order.
+func MultiRecorder(rs ...Recorder) Recorder {
+	rs = append([]Recorder(nil), rs...)
+	return RecorderFunc(func(ev Event) {
+		for _, r := range rs {
+			r.Record(ev)
+		}
+	})
+}
+
+// ChanRecorder sends events on a channel, for example to process them in
+// another goroutine. It never blocks: events are dropped if the channel is
+// full.
+type ChanRecorder chan<- Event
+
+// Record sends ev on the channel if it's not full.
+func (c ChanRecorder) Record(ev Event) {
+	select {
+	case c <- ev:
+	default:
+	}
+}
+
+// ErrorRecord is the structured record written by WriterRecorder.
+type ErrorRecord struct {
+	Time     time.Time              `json:"time"`
+	Kind     string                 `json:"kind"`
+	Code     int                    `json:"code"`
+	Message  string                 `json:"message"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Function string                 `json:"function,omitempty"`
+	Location string                 `json:"location,omitempty"` // file:line
+}
+
+// NewErrorRecord creates a record for ev.
+func NewErrorRecord(ev Event) ErrorRecord {
+	r := ErrorRecord{
+		Time:     now(),
+		Kind:     ev.Kind.String(),
+		Code:     ev.Code,
+		Message:  ev.Err.Error(),
+		Fields:   ev.Fields,
+		Function: ev.Caller.Function,
+	}
+	if ev.Caller.File != "" {
+		r.Location = ev.Caller.File + ":" + strconv.Itoa(ev.Caller.Line)
+	}
+	return r
+}
+
+// WriterRecorder writes every event to an io.Writer as an ErrorRecord in JSON,
+// one per line. It's safe for concurrent use.
+type WriterRecorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterRecorder creates a new recorder that writes to w.
+func NewWriterRecorder(w io.Writer) *WriterRecorder { return &WriterRecorder{w: w} }
+
+// Record writes ev. Errors from the writer are ignored.
+func (r *WriterRecorder) Record(ev Event) {
+	j, err := json.Marshal(NewErrorRecord(ev))
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.w.Write(append(j, '\n'))
+}
+
+var _ Recorder = &Ring{}
@@ -0,0 +1,53 @@
+package guru
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetRecorder(t *testing.T) {
+	t.Cleanup(func() { SetRecorder(nil) })
+
+	var (
+		buf  = new(bytes.Buffer)
+		ring = NewRing(10)
+		ch   = make(chan Event, 1)
+	)
+	now = func() time.Time { return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	SetRecorder(ring)
+	SetRecorder(MultiRecorder(NewWriterRecorder(buf), ChanRecorder(ch)))
+	New(1, "one")
+	New(2, "two") // Dropped from the channel.
+	SetRecorder(nil)
+	New(3, "three")
+
+	if e := ring.Entries(); len(e) != 0 {
+		t.Errorf("recorded after replacing: %#v", e)
+	}
+	if ev := <-ch; ev.Code != 1 {
+		t.Errorf("%#v", ev)
+	}
+	select {
+	case ev := <-ch:
+		t.Errorf("not dropped: %#v", ev)
+	default:
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%q", lines)
+	}
+	var rec ErrorRecord
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != 2 || rec.Message != "two" || rec.Kind != "new" || rec.Function != "zgo.at/guru.TestSetRecorder" ||
+		!strings.Contains(rec.Location, "recorder_test.go:") || !rec.Time.Equal(now()) {
+		t.Errorf("%#v", rec)
+	}
+}
@@ -0,0 +1,54 @@
+package guru
+
+import (
+	"fmt"
+)
+
+// Recover converts a panic to an error with the given code, and stores it in
+// err. It must be called with defer:
+//
+//	func work() (err error) {
+//		defer guru.Recover(&err, 500)
+//		...
+//	}
+//
+// The error message is "panic: " followed by the panic value. If the value is
+// an error then it's wrapped. The value is also added as the "panic" field,
+// and the stack trace of the panic is recorded. Hooks see this as a KindNew
+// event, with the location of the panic as the caller.
+//
+// Nothing is done if there is no panic.
+func Recover(err *error, code int) {
+	if r := recover(); r != nil {
+		*err = panicError(r, code, 3)
+	}
+}
+
+// Safe calls fn, converting a panic to an error with the given code in the
+// same way as Recover.
+func Safe(code int, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r, code, 3)
+		}
+	}()
+	return fn()
+}
+
+func panicError(r interface{}, code, skip int) error {
+	var err error
+	if e, ok := r.(error); ok {
+		err = fmt.Errorf("panic: %w", e)
+	} else {
+		err = fmt.Errorf("panic: %v", r)
+	}
+	// Same location as the start of the stack: skip is the number of frames
+	// callers skips above panicError, and createdSkip skips one less.
+	return createdSkip(skip-1, KindNew, code, err.Error(), nil, &withStack{
+		error: &withFields{
+			error:  &withCode{error: err, code: code},
+			fields: map[string]interface{}{"panic": r},
+		},
+		stack: callers(skip),
+	})
+}
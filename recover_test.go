@@ -0,0 +1,91 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		fn         func() error
+		want       string
+		wantCode   int
+		wantIsEOF  bool
+		wantPanic  interface{}
+		wantNoFunc bool
+	}{
+		{func() error { return nil }, "<nil>", 0, false, nil, true},
+		{func() error { return New(1, "x") }, "error 1: x", 1, false, nil, true},
+		{func() error { panic("oh noes") }, "error 500: panic: oh noes", 500, false, "oh noes", false},
+		{func() error { panic(io.EOF) }, "error 500: panic: EOF", 500, true, io.EOF, false},
+		{func() error { panic(New(1, "x")) }, "error 500: panic: error 1: x", 500, false, nil, false},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			check := func(t *testing.T, err error) {
+				if out := fmt.Sprintf("%v", err); out != tt.want {
+					t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+				}
+				if out := Code(err); out != tt.wantCode {
+					t.Errorf("code: %d", out)
+				}
+				if out := errors.Is(err, io.EOF); out != tt.wantIsEOF {
+					t.Errorf("errors.Is: %t", out)
+				}
+				if tt.wantPanic != nil && Fields(err)["panic"] != tt.wantPanic {
+					t.Errorf("fields: %v", Fields(err))
+				}
+				st := StackTrace(err)
+				if tt.wantNoFunc {
+					if st != nil {
+						t.Errorf("stack: %v", st)
+					}
+					return
+				}
+				if len(st) == 0 || st[0].Function != "zgo.at/guru.TestRecover.func"+fmt.Sprint(i+1) {
+					t.Errorf("stack: %v", st)
+				}
+			}
+
+			t.Run("Safe", func(t *testing.T) {
+				check(t, Safe(500, tt.fn))
+			})
+			t.Run("Recover", func(t *testing.T) {
+				check(t, func() (err error) {
+					defer Recover(&err, 500)
+					return tt.fn()
+				}())
+			})
+		})
+	}
+}
+
+func TestRecoverHook(t *testing.T) {
+	var events []Event
+	t.Cleanup(AddHook(func(e Event) { events = append(events, e) }))
+
+	for _, tt := range []func() error{
+		func() error { return Safe(500, func() error { panic("oh noes") }) },
+		func() (err error) {
+			defer Recover(&err, 500)
+			panic("oh noes")
+		},
+	} {
+		events = nil
+		err := tt()
+		if len(events) != 1 {
+			t.Fatalf("%d events", len(events))
+		}
+		ev := events[0]
+		if ev.Err != err || ev.Kind != KindNew || ev.Code != 500 || ev.Message != "panic: oh noes" || ev.Fields["panic"] != "oh noes" {
+			t.Errorf("wrong event: %#v", ev)
+		}
+		if !strings.HasSuffix(ev.Caller.File, "recover_test.go") || !strings.Contains(ev.Caller.Function, "TestRecoverHook.func") {
+			t.Errorf("wrong caller: %#v", ev.Caller)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package guru
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"text/template"
+)
+
+// Registry stores information about error codes, such as their names,
+// descriptions, and HTTP status codes.
+//
+// The zero value is an empty registry that's ready to use. The package-level
+// functions such as Register and New use DefaultRegistry; errors created with
+// Registry.New, Registry.Wrap, etc. use their own registry for HTTPStatus,
+// Public, etc.
+type Registry struct {
+	mu         sync.RWMutex
+	info       map[int]Info
+	categories []category
+	http       map[int]int
+	exit       map[int]int
+	retry      map[int]bool
+	help       map[int]string
+	deprecated map[int]deprecation
+	msg        map[int]string
+	catalogs   map[string]map[int]string
+	severity   map[int]Level
+	rules      []Rule
+	tmpl       map[int]*template.Template
+	catTmpl    map[string]*template.Template
+}
+
+// DefaultRegistry is the registry used by the package-level functions.
+var DefaultRegistry = &Registry{}
+
+// Info is information about an error code.
+type Info struct {
+	Code        int
+	Name        string
+	Description string
+	Message     string // From RegisterMessage.
+	Category    string // From RegisterCategory.
+	HTTPStatus  int    // From RegisterHTTPStatus; 0 if not registered.
+	ExitCode    int    // From RegisterExitCode; 0 if not registered.
+	Retryable   bool   // From RegisterRetryable.
+	Severity    Level  // From RegisterSeverity; 0 if not registered.
+	HelpURL     string // From RegisterHelpURL.
+
+	Deprecated        bool   // From Deprecate.
+	ReplacedBy        int    // Code to use instead; 0 if there is none.
+	DeprecationReason string // Why it's deprecated.
+}
+
+type category struct {
+	min, max int
+	name     string
+}
+
+// Register registers the name and description for the error code code,
+// replacing any previous registration.
+//
+// The name is a short identifier such as "ErrInvoiceMissing", and the
+// description is a human-readable explanation.
+func (r *Registry) Register(code int, name, description string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.info == nil {
+		r.info = make(map[int]Info)
+	}
+	r.info[code] = Info{Code: code, Name: name, Description: description}
+}
+
+// Name gets the registered name for code, or an empty string if it's not
+// registered.
+func (r *Registry) Name(code int) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.info[code].Name
+}
+
+// Describe gets the registered description for code, or an empty string if
+// it's not registered.
+func (r *Registry) Describe(code int) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.info[code].Description
+}
+
+// Lookup gets all information about code. The second return value reports if
+// anything was registered for the code (excluding categories).
+func (r *Registry) Lookup(code int) (Info, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lookup(code)
+}
+
+func (r *Registry) lookup(code int) (Info, bool) {
+	info, ok := r.info[code]
+	info.Code = code
+	info.Category = r.categoryOf(code)
+	if s, has := r.http[code]; has {
+		info.HTTPStatus, ok = s, true
+	}
+	if e, has := r.exit[code]; has {
+		info.ExitCode, ok = e, true
+	}
+	if rt, has := r.retry[code]; has {
+		info.Retryable, ok = rt, true
+	}
+	if l, has := r.severity[code]; has {
+		info.Severity, ok = l, true
+	}
+	if m, has := r.msg[code]; has {
+		info.Message, ok = m, true
+	}
+	if h, has := r.help[code]; has {
+		info.HelpURL, ok = h, true
+	}
+	if d, has := r.deprecated[code]; has {
+		info.Deprecated, info.ReplacedBy, info.DeprecationReason, ok = true, d.replacement, d.reason, true
+	}
+	return info, ok
+}
+
+// All gets information about all registered codes, sorted by code.
+func (r *Registry) All() []Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codes := r.codes()
+	all := make([]Info, 0, len(codes))
+	for _, c := range codes {
+		info, _ := r.lookup(c)
+		all = append(all, info)
+	}
+	return all
+}
+
+// codes gets all registered codes, sorted.
+func (r *Registry) codes() []int {
+	seen := make(map[int]struct{})
+	for _, m := range []map[int]int{r.http, r.exit} {
+		for c := range m {
+			seen[c] = struct{}{}
+		}
+	}
+	for c := range r.info {
+		seen[c] = struct{}{}
+	}
+	for c := range r.retry {
+		seen[c] = struct{}{}
+	}
+	for c := range r.severity {
+		seen[c] = struct{}{}
+	}
+	for c := range r.msg {
+		seen[c] = struct{}{}
+	}
+	for c := range r.help {
+		seen[c] = struct{}{}
+	}
+	for c := range r.deprecated {
+		seen[c] = struct{}{}
+	}
+
+	codes := make([]int, 0, len(seen))
+	for c := range seen {
+		codes = append(codes, c)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// RegisterHelpURL registers a URL with more information about the error code
+// code, such as a page in the documentation.
+func (r *Registry) RegisterHelpURL(code int, url string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.help == nil {
+		r.help = make(map[int]string)
+	}
+	r.help[code] = url
+}
+
+// RegisterCategory registers name as the category for the codes from min to
+// max (inclusive). If ranges overlap then the smallest range containing the
+// code is used.
+//
+// This will panic if min is larger than max.
+func (r *Registry) RegisterCategory(min, max int, name string) {
+	if min > max {
+		panic(fmt.Sprintf("guru.RegisterCategory: min %d is larger than max %d", min, max))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.categories = append(r.categories, category{min: min, max: max, name: name})
+}
+
+// Category gets the category name for the code of err, as returned by Code().
+// It will return an empty string if err has no code or if the code is not in
+// any registered category.
+func (r *Registry) Category(err error) string {
+	code, ok := mappedCode(err)
+	if !ok {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.categoryOf(code)
+}
+
+func (r *Registry) categoryOf(code int) string {
+	var found *category
+	for i, c := range r.categories {
+		if code >= c.min && code <= c.max && (found == nil || c.max-c.min < found.max-found.min) {
+			found = &r.categories[i]
+		}
+	}
+	if found == nil {
+		return ""
+	}
+	return found.name
+}
+
+// Register calls DefaultRegistry.Register.
+func Register(code int, name, description string) {
+	DefaultRegistry.Register(code, name, description)
+}
+
+// Name calls DefaultRegistry.Name.
+func Name(code int) string { return DefaultRegistry.Name(code) }
+
+// Describe calls DefaultRegistry.Describe.
+func Describe(code int) string { return DefaultRegistry.Describe(code) }
+
+// RegisterCategory calls DefaultRegistry.RegisterCategory.
+func RegisterCategory(min, max int, name string) {
+	DefaultRegistry.RegisterCategory(min, max, name)
+}
+
+// RegisterHelpURL calls DefaultRegistry.RegisterHelpURL.
+func RegisterHelpURL(code int, url string) { DefaultRegistry.RegisterHelpURL(code, url) }
+
+// Category calls Category on the registry of err; see RegistryOf.
+func Category(err error) string { return RegistryOf(err).Category(err) }
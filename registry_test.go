@@ -0,0 +1,123 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// resetRegistry sets DefaultRegistry to a new registry for the duration of
+// the test.
+func resetRegistry(t *testing.T) {
+	old := DefaultRegistry
+	DefaultRegistry = &Registry{}
+	t.Cleanup(func() { DefaultRegistry = old })
+}
+
+func TestCategory(t *testing.T) {
+	resetRegistry(t)
+	RegisterCategory(1000, 1999, "storage")
+	RegisterCategory(2000, 2999, "auth")
+	RegisterCategory(2100, 2199, "auth.token")
+	RegisterCategory(0, 9999, "all")
+
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("x"), ""},
+		{New(1000, "x"), "storage"},
+		{New(1999, "x"), "storage"},
+		{New(2000, "x"), "auth"},
+		{New(2150, "x"), "auth.token"},
+		{Wrap(1500, New(2150, "x"), "y"), "storage"},
+		{New(3000, "x"), "all"},
+		{New(10000, "x"), ""},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Category(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("no panic")
+			}
+		}()
+		RegisterCategory(2, 1, "x")
+	}()
+}
+
+func TestRegister(t *testing.T) {
+	resetRegistry(t)
+	Register(4012, "ErrInvoiceMissing", "The invoice doesn't exist.")
+	Register(1, "ErrOne", "")
+	Register(1, "ErrFirst", "First error.")
+	RegisterCategory(4000, 4999, "billing")
+	RegisterHTTPStatus(4012, 404)
+	RegisterHTTPStatus(5, 503)
+	RegisterExitCode(4012, 3)
+	RegisterRetryable(5, true)
+
+	tests := []struct {
+		code     int
+		wantName string
+		wantDesc string
+	}{
+		{0, "", ""},
+		{1, "ErrFirst", "First error."},
+		{4012, "ErrInvoiceMissing", "The invoice doesn't exist."},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			name, desc := Name(tt.code), Describe(tt.code)
+			if name != tt.wantName || desc != tt.wantDesc {
+				t.Errorf("\nout:  %q %q\nwant: %q %q\n", name, desc, tt.wantName, tt.wantDesc)
+			}
+		})
+	}
+
+	info, ok := DefaultRegistry.Lookup(4012)
+	want := Info{Code: 4012, Name: "ErrInvoiceMissing", Description: "The invoice doesn't exist.",
+		Category: "billing", HTTPStatus: 404, ExitCode: 3}
+	if !ok || info != want {
+		t.Errorf("Lookup\nout:  %#v\nwant: %#v", info, want)
+	}
+	if info, ok := DefaultRegistry.Lookup(4013); ok || info != (Info{Code: 4013, Category: "billing"}) {
+		t.Errorf("Lookup: %#v", info)
+	}
+
+	all := DefaultRegistry.All()
+	wantAll := []Info{
+		{Code: 1, Name: "ErrFirst", Description: "First error."},
+		{Code: 5, HTTPStatus: 503, Retryable: true},
+		want,
+	}
+	if !reflect.DeepEqual(all, wantAll) {
+		t.Errorf("All\nout:  %#v\nwant: %#v", all, wantAll)
+	}
+
+	if out := fmt.Sprintf("%+v", Wrap(4012, New(5, "oh noes"), "ctx")); out != "error 4012 (ErrInvoiceMissing): ctx\nerror 5: oh noes" {
+		t.Errorf("%%+v: %q", out)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	var r Registry
+	r.Register(1, "ErrOne", "")
+	r.RegisterHTTPStatus(1, 400)
+	if Name(1) != "" || HTTPStatus(New(1, "x")) != 500 {
+		t.Error("registered in DefaultRegistry")
+	}
+	if r.Name(1) != "ErrOne" || r.HTTPStatus(New(1, "x")) != 400 {
+		t.Error("not registered")
+	}
+}
@@ -0,0 +1,58 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type withRelated struct {
+	error
+	related []error
+}
+
+func (e *withRelated) Unwrap() error { return e.error }
+func (e withRelated) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.error) {
+		fmt.Fprint(s, "\nrelated:")
+		writeBranches(s, e.related, true, plusV)
+	}
+}
+
+// WithRelated annotates err with errors that are associated with it but didn't
+// cause it, such as a cleanup failure that happened while handling err:
+//
+//	if err := tx.Commit(); err != nil {
+//		return guru.WithRelated(err, tx.Rollback())
+//	}
+//
+// The related errors don't change the error message and aren't seen by
+// errors.Is, errors.As, Code, etc., but are printed with the %+v verb. nil
+// errors in others are ignored, and err is returned as-is if there are no
+// related errors. It will return nil if err is nil.
+func WithRelated(err error, others ...error) error {
+	if err == nil {
+		return nil
+	}
+	related := make([]error, 0, len(others))
+	for _, o := range others {
+		if o != nil {
+			related = append(related, o)
+		}
+	}
+	if len(related) == 0 {
+		return err
+	}
+	return &withRelated{error: err, related: related}
+}
+
+// Related gets all errors added with WithRelated, from the outermost error to
+// the innermost. It will return nil if there are none.
+func Related(err error) []error {
+	var related []error
+	walk(err, func(err error) bool {
+		if r, ok := err.(*withRelated); ok {
+			related = append(related, r.related...)
+		}
+		return true
+	})
+	return related
+}
@@ -0,0 +1,70 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestRelated(t *testing.T) {
+	var (
+		rollback = New(2, "rollback failed")
+		closeErr = errors.New("close: broken pipe")
+	)
+
+	tests := []struct {
+		in       error
+		want     []error
+		wantPlus string
+	}{
+		{nil, nil, "<nil>"},
+		{WithRelated(nil, rollback), nil, "<nil>"},
+		{errors.New("x"), nil, "x"},
+		{WithRelated(New(1, "x")), nil, "error 1: x"},
+		{WithRelated(New(1, "x"), nil), nil, "error 1: x"},
+		{WithRelated(New(1, "x"), rollback), []error{rollback},
+			"error 1: x\nrelated:\n└─ error 2: rollback failed"},
+		{WithRelated(Wrap(3, WithRelated(New(1, "x"), closeErr), "y"), rollback, nil), []error{rollback, closeErr},
+			"error 3: y\nerror 1: x\nrelated:\n└─ close: broken pipe\nrelated:\n└─ error 2: rollback failed"},
+		{WithRelated(New(1, "x"), rollback, closeErr),
+			[]error{rollback, closeErr},
+			"error 1: x\nrelated:\n├─ error 2: rollback failed\n└─ close: broken pipe"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := Related(tt.in); !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlus)
+			}
+		})
+	}
+
+	err := WithRelated(New(1, "x"), rollback, io.EOF)
+	if fmt.Sprintf("%v", err) != "error 1: x" || Code(err) != 1 || !reflect.DeepEqual(Codes(err), []int{1}) {
+		t.Errorf("%v", err)
+	}
+	if errors.Is(err, io.EOF) || Has(err, 2) {
+		t.Error("related error is seen as a cause")
+	}
+}
+
+func TestRelatedJSON(t *testing.T) {
+	err := WithRelated(New(1, "x"), Wrap(2, io.EOF, "rollback"))
+	j, jErr := MarshalJSON(err)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	want := `{"code":1,"message":"x","related":[{"code":2,"message":"rollback","wrapped":{"message":"EOF"}}]}`
+	if string(j) != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", j, want)
+	}
+
+	back := mustFromJSON(t, string(j))
+	if a, b := fmt.Sprintf("%+v", back), fmt.Sprintf("%+v", err); a != b {
+		t.Errorf("\nout:  %s\nwant: %s\n", a, b)
+	}
+}
@@ -0,0 +1,141 @@
+package guru
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Reporter reports errors, limiting how often the same error is reported.
+//
+// An error is reported at most once per Interval for every combination of
+// error code and Fingerprint, so errors with the same code and message are
+// limited, but errors with the same code and a different message (ignoring
+// variable parts such as IDs) are reported separately. The next report after
+// the interval includes the number of errors that were suppressed in the
+// meanwhile.
+//
+// For example, to log every error at most once every 10 seconds:
+//
+//	r := &guru.Reporter{
+//		Sink: func(err error, suppressed int) {
+//			log.Printf("%v (%d suppressed)", err, suppressed)
+//		},
+//	}
+//	guru.AddHook(r.Hook)
+type Reporter struct {
+	Sink     func(err error, suppressed int) // Called for every reported error.
+	Interval time.Duration                   // Default is 10 seconds.
+
+	mu        sync.Mutex
+	seen      map[string]*reported
+	lastPrune time.Time
+	now       func() time.Time // For tests.
+}
+
+type reported struct {
+	at         time.Time
+	suppressed int
+	err        error // Last suppressed error.
+}
+
+// Report calls Sink for err, unless another error with the same code and
+// fingerprint was reported less than Interval ago. It reports whether Sink was
+// called.
+func (r *Reporter) Report(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	key := reportKey(err)
+	r.mu.Lock()
+	now, interval := r.time(), r.interval()
+	if r.seen == nil {
+		r.seen = make(map[string]*reported)
+	}
+	r.prune(now, interval)
+
+	s, ok := r.seen[key]
+	if ok && now.Sub(s.at) < interval {
+		s.suppressed++
+		s.err = err
+		r.mu.Unlock()
+		return false
+	}
+	suppressed := 0
+	if ok {
+		suppressed = s.suppressed
+	}
+	r.seen[key] = &reported{at: now}
+	r.mu.Unlock()
+
+	r.Sink(err, suppressed)
+	return true
+}
+
+// reportKey gets the key to limit err by: the code and fingerprint.
+func reportKey(err error) string {
+	if !hasCode(err) {
+		return Fingerprint(err)
+	}
+	return strconv.Itoa(Code(err)) + "/" + Fingerprint(err)
+}
+
+// Hook calls Report for the error in the event; use it with AddHook.
+//
+// Wrapping an error that already has a code isn't reported, so that an error
+// is only reported once when it's created and then wrapped.
+func (r *Reporter) Hook(e Event) {
+	if e.Kind == KindWrap && hasCode(e.Cause) {
+		return
+	}
+	r.Report(e.Err)
+}
+
+// Flush calls Sink for the last suppressed error of every code and fingerprint
+// with any suppressed errors, and resets the limits. This is useful before the
+// program exits, as errors are otherwise only reported when another error with
+// the same code and fingerprint comes in after the interval.
+func (r *Reporter) Flush() {
+	r.mu.Lock()
+	var flush []*reported
+	for _, s := range r.seen {
+		if s.suppressed > 0 {
+			flush = append(flush, s)
+		}
+	}
+	r.seen = nil
+	r.mu.Unlock()
+
+	for _, s := range flush {
+		r.Sink(s.err, s.suppressed-1)
+	}
+}
+
+// prune removes entries older than interval without any suppressed errors, at
+// most once per interval.
+func (r *Reporter) prune(now time.Time, interval time.Duration) {
+	if now.Sub(r.lastPrune) < interval {
+		return
+	}
+	r.lastPrune = now
+	for k, s := range r.seen {
+		if s.suppressed == 0 && now.Sub(s.at) >= interval {
+			delete(r.seen, k)
+		}
+	}
+}
+
+func (r *Reporter) interval() time.Duration {
+	if r.Interval <= 0 {
+		return 10 * time.Second
+	}
+	return r.Interval
+}
+
+func (r *Reporter) time() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
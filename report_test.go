@@ -0,0 +1,89 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestReporter(t *testing.T) {
+	var (
+		now  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		sent []string
+	)
+	r := &Reporter{
+		Sink: func(err error, suppressed int) { sent = append(sent, fmt.Sprintf("%v (%d)", err, suppressed)) },
+		now:  func() time.Time { return now },
+	}
+
+	tests := []struct {
+		in      error
+		advance time.Duration
+		want    bool
+	}{
+		{New(1, "user 1"), 0, true},
+		{New(1, "user 2"), time.Second, false},
+		{New(2, "x"), 0, true},
+		{errors.New("db 1"), 0, true},
+		{errors.New("db 2"), 0, false},
+		{errors.New("other"), 0, true},
+		{New(1, "other"), 0, true},
+		{New(1, "user 3"), 8 * time.Second, false},
+		{New(1, "user 4"), time.Second, true},
+		{New(1, "user 5"), 0, false},
+		{nil, 0, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			now = now.Add(tt.advance)
+			if out := r.Report(tt.in); out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+		})
+	}
+
+	want := []string{"error 1: user 1 (0)", "error 2: x (0)", "db 1 (0)", "other (0)", "error 1: other (0)", "error 1: user 4 (2)"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", sent, want)
+	}
+
+	sent = nil
+	r.Flush()
+	sort.Strings(sent)
+	want = []string{"db 2 (0)", "error 1: user 5 (0)"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Flush\nout:  %#v\nwant: %#v\n", sent, want)
+	}
+
+	sent = nil
+	r.Report(New(1, "x"))
+	if len(sent) != 1 {
+		t.Errorf("not reset after Flush: %v", sent)
+	}
+
+	// Pruned after interval.
+	now = now.Add(time.Minute)
+	r.Report(New(3, "x"))
+	if _, ok := r.seen[reportKey(New(1, "x"))]; ok {
+		t.Error("not pruned")
+	}
+}
+
+func TestReporterHook(t *testing.T) {
+	n := 0
+	r := &Reporter{Sink: func(error, int) { n++ }}
+	remove := AddHook(r.Hook)
+	defer remove()
+
+	err := New(1, "user 1")
+	Wrap(2, err, "ctx")
+	New(1, "user 2")
+	New(1, "other")
+	Wrap(2, errors.New("x"), "ctx")
+	if n != 3 {
+		t.Errorf("n=%d", n)
+	}
+}
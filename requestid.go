@@ -0,0 +1,57 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type withRequestID struct {
+	error
+	id  string
+	msg *memo
+}
+
+func (e *withRequestID) Unwrap() error { return e.error }
+func (e *withRequestID) Error() string {
+	return e.msg.get(func() string { return e.error.Error() + " (request_id: " + e.id + ")" })
+}
+func (e withRequestID) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%+v\nrequest_id: %s", e.error, e.id)
+	case verb == 's':
+		fmt.Fprintf(s, "%s (request_id: %s)", e.error, e.id)
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", fmt.Sprintf("%s (request_id: %s)", e.error, e.id))
+	default:
+		fmt.Fprintf(s, "%v (request_id: %s)", e.error, e.id)
+	}
+}
+
+// WithRequestID annotates err with the ID of the request (or trace, job, etc.)
+// that caused it. Unlike fields the ID is part of the message for all verbs, so
+// it can be quoted from an error shown to a user and used to find the
+// matching logs:
+//
+//	error 42: oh noes (request_id: 01HXG3)
+//
+// It will return nil if err is nil.
+func WithRequestID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	return &withRequestID{error: err, id: id, msg: new(memo)}
+}
+
+// RequestID gets the outermost request ID set with WithRequestID, or an empty
+// string if there is none.
+func RequestID(err error) string {
+	var id string
+	walk(err, func(err error) bool {
+		if r, ok := err.(*withRequestID); ok {
+			id = r.id
+			return false
+		}
+		return true
+	})
+	return id
+}
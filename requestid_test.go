@@ -0,0 +1,48 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		in                            error
+		want                          string
+		wantV, wantS, wantQ, wantPlus string
+	}{
+		{nil, "", "<nil>", "%!s(<nil>)", "%!q(<nil>)", "<nil>"},
+		{errors.New("x"), "", "x", "x", `"x"`, "x"},
+		{WithRequestID(nil, "abc"), "", "<nil>", "%!s(<nil>)", "%!q(<nil>)", "<nil>"},
+		{WithRequestID(New(1, "x"), "abc"), "abc",
+			"error 1: x (request_id: abc)", "x (request_id: abc)", `"x (request_id: abc)"`,
+			"error 1: x\nrequest_id: abc"},
+		{Wrap(2, WithRequestID(New(1, "x"), "abc"), "y"), "abc",
+			"error 2: error 1: x (request_id: abc): y", "x (request_id: abc): y", `"x (request_id: abc): y"`,
+			"error 2: y\nerror 1: x\nrequest_id: abc"},
+		{WithRequestID(WithRequestID(errors.New("x"), "inner"), "outer"), "outer",
+			"x (request_id: inner) (request_id: outer)", "x (request_id: inner) (request_id: outer)",
+			`"x (request_id: inner) (request_id: outer)"`, "x\nrequest_id: inner\nrequest_id: outer"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := RequestID(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			for _, f := range []struct{ verb, want string }{
+				{"%v", tt.wantV}, {"%s", tt.wantS}, {"%q", tt.wantQ}, {"%+v", tt.wantPlus},
+			} {
+				if out := fmt.Sprintf(f.verb, tt.in); out != f.want {
+					t.Errorf("%s\nout:  %#v\nwant: %#v\n", f.verb, out, f.want)
+				}
+			}
+		})
+	}
+
+	err := WithRequestID(errors.New("x"), "abc")
+	if err.Error() != "x (request_id: abc)" {
+		t.Errorf("Error(): %q", err.Error())
+	}
+}
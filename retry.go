@@ -0,0 +1,77 @@
+package guru
+
+import (
+	"fmt"
+)
+
+type withRetry struct {
+	error
+	retry bool
+}
+
+func (e *withRetry) Unwrap() error                { return e.error }
+func (e *withRetry) Retryable() bool              { return e.retry }
+func (e withRetry) Format(s fmt.State, verb rune) { formatInner(s, verb, e.error) }
+
+// MarkRetryable marks err as retryable; that is, the operation that caused it
+// may succeed if it's tried again. It will return nil if err is nil.
+func MarkRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withRetry{error: err, retry: true}
+}
+
+// MarkPermanent marks err as permanent; that is, the operation that caused it
+// will fail again if it's retried. It will return nil if err is nil.
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withRetry{error: err, retry: false}
+}
+
+// RegisterRetryable sets if errors with the error code code are retryable if
+// they're not explicitly marked with MarkRetryable or MarkPermanent.
+func (r *Registry) RegisterRetryable(code int, retryable bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.retry == nil {
+		r.retry = make(map[int]bool)
+	}
+	r.retry[code] = retryable
+}
+
+// Retryable reports if the operation that caused err may succeed if it's
+// tried again.
+//
+// The highest-level error marked with MarkRetryable or MarkPermanent is used.
+// If none of the errors are marked then the value registered with
+// RegisterRetryable for Code(err) is used, and if there isn't one then the
+// error is not retryable.
+func (r *Registry) Retryable(err error) bool {
+	var (
+		retry  bool
+		marked bool
+	)
+	walk(err, func(err error) bool {
+		if rt, ok := err.(*withRetry); ok {
+			retry, marked = rt.retry, true
+		}
+		return !marked
+	})
+	code, ok := mappedCode(err)
+	if marked || !ok {
+		return retry
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.retry[code]
+}
+
+// RegisterRetryable calls DefaultRegistry.RegisterRetryable.
+func RegisterRetryable(code int, retryable bool) { DefaultRegistry.RegisterRetryable(code, retryable) }
+
+// Retryable calls Retryable on the registry of err; see RegistryOf.
+func Retryable(err error) bool { return RegistryOf(err).Retryable(err) }
@@ -0,0 +1,61 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var _ error = &withRetry{}
+
+func TestRetryable(t *testing.T) {
+	resetRegistry(t)
+	RegisterRetryable(503, true)
+	RegisterRetryable(0, true)
+
+	tests := []struct {
+		in   error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("x"), false},
+		{New(500, "x"), false},
+		{New(503, "x"), true},
+		{New(0, "x"), true},
+		{MarkRetryable(errors.New("x")), true},
+		{MarkPermanent(New(503, "x")), false},
+		{MarkRetryable(New(500, "x")), true},
+		{Wrap(500, MarkRetryable(New(500, "x")), "y"), true},
+		{MarkPermanent(MarkRetryable(New(500, "x"))), false},
+		{Wrap(503, MarkPermanent(New(500, "x")), "y"), false},
+		{fmt.Errorf("y: %w", New(503, "x")), true},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Retryable(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+
+			j, err := MarshalJSON(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := FromJSON(j)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out := Retryable(back); out != tt.want {
+				t.Errorf("JSON: %s", j)
+			}
+		})
+	}
+
+	if MarkRetryable(nil) != nil || MarkPermanent(nil) != nil {
+		t.Error("not nil")
+	}
+	if out := fmt.Sprintf("%v", MarkRetryable(New(1, "x"))); out != "error 1: x" {
+		t.Error(out)
+	}
+}
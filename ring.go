@@ -0,0 +1,76 @@
+package guru
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Ring records the last errors in a ring buffer, for example to show recent
+// errors on a debug page:
+//
+//	ring := guru.NewRing(100)
+//	guru.AddHook(ring.Record)
+type Ring struct {
+	mu      sync.Mutex
+	entries []RingEntry
+	next    int
+	full    bool
+}
+
+// RingEntry is an error recorded in a Ring.
+type RingEntry struct {
+	Time    time.Time
+	Code    int
+	Message string
+	Fields  map[string]interface{}
+	Stack   []runtime.Frame // StackTrace(), or the location the error was created.
+	Err     error
+}
+
+// NewRing creates a new ring buffer for the last n errors.
+func NewRing(n int) *Ring {
+	if n < 1 {
+		n = 1
+	}
+	return &Ring{entries: make([]RingEntry, n)}
+}
+
+// Record adds the error from the event to the ring, removing the oldest error
+// if it's full.
+func (r *Ring) Record(ev Event) {
+	e := RingEntry{
+		Time:    now(),
+		Code:    ev.Code,
+		Message: ev.Err.Error(),
+		Fields:  ev.Fields,
+		Stack:   StackTrace(ev.Err),
+		Err:     ev.Err,
+	}
+	if e.Stack == nil && ev.Caller.PC != 0 {
+		e.Stack = []runtime.Frame{ev.Caller}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Entries gets the recorded errors, newest first.
+func (r *Ring) Entries() []RingEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	out := make([]RingEntry, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package guru
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing(3)
+	if e := r.Entries(); len(e) != 0 {
+		t.Fatal(e)
+	}
+	remove := AddHook(r.Record)
+	defer remove()
+
+	codes := func() []int {
+		var c []int
+		for _, e := range r.Entries() {
+			c = append(c, e.Code)
+		}
+		return c
+	}
+
+	New(1, "one")
+	New(2, "two")
+	if out, want := codes(), []int{2, 1}; !reflect.DeepEqual(out, want) {
+		t.Errorf("\nout:  %v\nwant: %v\n", out, want)
+	}
+	WithFields(NewStack(3, "three"), map[string]interface{}{"k": "v"})
+	New(4, "four")
+	New(5, "five")
+	if out, want := codes(), []int{5, 4, 3}; !reflect.DeepEqual(out, want) {
+		t.Errorf("\nout:  %v\nwant: %v\n", out, want)
+	}
+
+	e := r.Entries()
+	if e[0].Message != "five" || e[0].Time.IsZero() || len(e[0].Stack) != 1 {
+		t.Errorf("%#v", e[0])
+	}
+	if fn := e[0].Stack[0].Function; fn != "zgo.at/guru.TestRing" {
+		t.Errorf("caller: %s", fn)
+	}
+	if len(e[2].Stack) < 2 {
+		t.Errorf("no stack: %#v", e[2])
+	}
+	if fmt.Sprintf("%v", e[2].Err) != "error 3: three" {
+		t.Errorf("%v", e[2].Err)
+	}
+}
@@ -0,0 +1,91 @@
+package guru
+
+import (
+	"errors"
+)
+
+// New is like the package-level New, but the error uses r instead of
+// DefaultRegistry for HTTPStatus, Public, etc.
+//
+// This allows libraries to use their own registry without registering codes in
+// (and possibly colliding with) DefaultRegistry:
+//
+//	var Errors = &guru.Registry{}
+//
+//	func init() {
+//		Errors.Register(4012, "ErrInvoiceMissing", "The invoice doesn't exist.")
+//		Errors.RegisterHTTPStatus(4012, 404)
+//	}
+//
+//	err := Errors.New(4012, "no such invoice")
+//	guru.HTTPStatus(err)  // 404
+func (r *Registry) New(code int, msg string) error {
+	return created(KindNew, code, msg, nil, &withCode{
+		error: errors.New(msg),
+		code:  code,
+		reg:   r,
+	})
+}
+
+// Errorf is like the package-level Errorf, but the error uses r; see
+// Registry.New.
+func (r *Registry) Errorf(code int, format string, args ...interface{}) error {
+	return errorf(r, code, format, args)
+}
+
+// WithCode is like the package-level WithCode, but the error uses r; see
+// Registry.New.
+func (r *Registry) WithCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return created(KindWrap, code, "", err, &withCode{
+		error: err,
+		code:  code,
+		reg:   r,
+	})
+}
+
+// Wrap is like the package-level Wrap, but the error uses r; see Registry.New.
+func (r *Registry) Wrap(code int, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return created(KindWrap, code, msg, err, &wrapped{
+		msg:   msg,
+		code:  code,
+		reg:   r,
+		error: err,
+	})
+}
+
+// RegistryOf gets the registry of the error that Code gets the code from: the
+// registry it was created with if it was created with Registry.New,
+// Registry.Wrap, etc., or DefaultRegistry otherwise.
+//
+// The package-level functions that accept an error, such as HTTPStatus and
+// Category, use this registry. Errors decoded with FromJSON always use
+// DefaultRegistry.
+func RegistryOf(err error) *Registry { return registryOf(err, innermost()) }
+
+func registryOf(err error, inner bool) *Registry {
+	reg := DefaultRegistry
+	walk(err, func(err error) bool {
+		if _, ok := codeOf(err); !ok {
+			return true
+		}
+		reg = DefaultRegistry
+		switch e := err.(type) {
+		case *withCode:
+			if e.reg != nil {
+				reg = e.reg
+			}
+		case *wrapped:
+			if e.reg != nil {
+				reg = e.reg
+			}
+		}
+		return inner
+	})
+	return reg
+}
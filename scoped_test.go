@@ -0,0 +1,99 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestRegistryNew(t *testing.T) {
+	resetRegistry(t)
+	Register(4012, "ErrGlobal", "")
+	RegisterHTTPStatus(4012, 400)
+
+	r := &Registry{}
+	r.Register(4012, "ErrInvoiceMissing", "")
+	r.RegisterHTTPStatus(4012, 404)
+	r.RegisterMessage(4012, "invoice not found")
+	r.RegisterCategory(4000, 4999, "billing")
+
+	tests := []struct {
+		in                 error
+		wantStatus         int
+		wantPublic, wantV  string
+		wantCat, wantPlusV string
+	}{
+		{New(4012, "x"), 400, "internal error", "error 4012: x", "", "error 4012 (ErrGlobal): x"},
+		{r.New(4012, "x"), 404, "invoice not found", "error 4012: x", "billing", "error 4012 (ErrInvoiceMissing): x"},
+		{r.Errorf(4012, "x: %w", io.EOF), 404, "invoice not found", "error 4012: x: EOF", "billing", "error 4012 (ErrInvoiceMissing): x: EOF"},
+		{r.WithCode(4012, io.EOF), 404, "invoice not found", "error 4012: EOF", "billing", "error 4012 (ErrInvoiceMissing): EOF"},
+		{r.Wrap(4012, io.EOF, "x"), 404, "invoice not found", "error 4012: EOF: x", "billing", "error 4012 (ErrInvoiceMissing): x\nEOF"},
+		{fmt.Errorf("y: %w", r.New(4012, "x")), 404, "invoice not found", "y: error 4012: x", "billing", "y: error 4012: x"},
+		{Wrap(4012, r.New(4012, "x"), "y"), 400, "internal error", "error 4012: error 4012: x: y", "", "error 4012 (ErrGlobal): y\nerror 4012 (ErrInvoiceMissing): x"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if s := HTTPStatus(tt.in); s != tt.wantStatus {
+				t.Errorf("HTTPStatus\nout:  %#v\nwant: %#v\n", s, tt.wantStatus)
+			}
+			if p := Public(tt.in); p != tt.wantPublic {
+				t.Errorf("Public\nout:  %#v\nwant: %#v\n", p, tt.wantPublic)
+			}
+			if c := Category(tt.in); c != tt.wantCat {
+				t.Errorf("Category\nout:  %#v\nwant: %#v\n", c, tt.wantCat)
+			}
+			if v := fmt.Sprintf("%v", tt.in); v != tt.wantV {
+				t.Errorf("%%v\nout:  %#v\nwant: %#v\n", v, tt.wantV)
+			}
+			if v := fmt.Sprintf("%+v", tt.in); v != tt.wantPlusV {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", v, tt.wantPlusV)
+			}
+		})
+	}
+
+	if err := r.WithCode(1, nil); err != nil {
+		t.Errorf("not nil: %v", err)
+	}
+	if err := r.Wrap(1, nil, "x"); err != nil {
+		t.Errorf("not nil: %v", err)
+	}
+	if !errors.Is(r.Errorf(1, "%w", io.EOF), io.EOF) {
+		t.Error("not io.EOF")
+	}
+}
+
+func TestRegistryOf(t *testing.T) {
+	r := &Registry{}
+	err := Wrap(1, r.New(2, "x"), "y")
+
+	if got := RegistryOf(err); got != DefaultRegistry {
+		t.Errorf("outermost: %p", got)
+	}
+	if got := RegistryOf(errors.New("x")); got != DefaultRegistry {
+		t.Errorf("no code: %p", got)
+	}
+
+	SetCodeSearch(SearchInnermost)
+	t.Cleanup(func() { SetCodeSearch(SearchOutermost) })
+	if got := RegistryOf(err); got != r {
+		t.Errorf("innermost: %p", got)
+	}
+}
+
+func TestRegistryDeprecated(t *testing.T) {
+	resetRegistry(t)
+	SetDeprecationMode(DeprecationPanic)
+	t.Cleanup(func() { SetDeprecationMode(DeprecationIgnore) })
+
+	r := &Registry{}
+	r.Deprecate(410, 0, "")
+	_ = New(410, "x")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("didn't panic")
+		}
+	}()
+	_ = r.New(410, "x")
+}
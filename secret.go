@@ -0,0 +1,41 @@
+package guru
+
+import (
+	"fmt"
+	"io"
+)
+
+// Redacted is the text secrets are displayed as.
+const Redacted = "[REDACTED]"
+
+// SecretValue is a value that is always displayed as [REDACTED]; see Secret.
+type SecretValue struct {
+	// Stored in a function so it's not printed if fmt uses reflection, e.g.
+	// when it's in an unexported struct field.
+	v func() interface{}
+}
+
+// Secret wraps v so that it's displayed as [REDACTED] with fmt, encoding/json,
+// and encoding.TextMarshaler (used by most loggers). Use it for format
+// arguments and fields that shouldn't end up in logs or error messages:
+//
+//	guru.Errorf(401, "invalid token %s", guru.Secret(token))
+//	guru.WithFields(err, map[string]interface{}{"token": guru.Secret(token)})
+//
+// The value can be retrieved in the same process with Reveal, for example from
+// the fields of an error.
+func Secret(v interface{}) SecretValue { return SecretValue{v: func() interface{} { return v }} }
+
+// Reveal gets the wrapped value.
+func (s SecretValue) Reveal() interface{} {
+	if s.v == nil {
+		return nil
+	}
+	return s.v()
+}
+
+func (s SecretValue) String() string                { return Redacted }
+func (s SecretValue) GoString() string              { return Redacted }
+func (s SecretValue) Format(f fmt.State, verb rune) { io.WriteString(f, Redacted) }
+func (s SecretValue) MarshalJSON() ([]byte, error)  { return []byte(`"` + Redacted + `"`), nil }
+func (s SecretValue) MarshalText() ([]byte, error)  { return []byte(Redacted), nil }
@@ -0,0 +1,53 @@
+package guru
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	tok := Secret("hunter2")
+	err := WithFields(Errorf(401, "invalid token %q", tok), map[string]interface{}{"token": tok})
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{err.Error(), "invalid token [REDACTED]"},
+		{fmt.Sprintf("%v", err), "error 401: invalid token [REDACTED]"},
+		{fmt.Sprintf("%+v", err), "error 401: invalid token [REDACTED]\nfields: token=[REDACTED]"},
+		{fmt.Sprintf("%#v %x %d %10s", tok, tok, tok, tok), "[REDACTED] [REDACTED] [REDACTED] [REDACTED]"},
+		{fmt.Sprintf("%v", struct{ s SecretValue }{tok}), ""},
+		{fmt.Sprintf("%v", SecretValue{}), "[REDACTED]"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if strings.Contains(tt.in, "hunter2") || (tt.want != "" && tt.in != tt.want) {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", tt.in, tt.want)
+			}
+		})
+	}
+
+	j, jErr := MarshalJSON(err)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	if want := `{"code":401,"message":"invalid token [REDACTED]","fields":{"token":"[REDACTED]"}}`; string(j) != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", j, want)
+	}
+	if tx, _ := json.Marshal(map[string]interface{}{"t": tok}); string(tx) != `{"t":"[REDACTED]"}` {
+		t.Errorf("%s", tx)
+	}
+	if tx, _ := tok.MarshalText(); string(tx) != "[REDACTED]" {
+		t.Errorf("%s", tx)
+	}
+
+	if v := Fields(err)["token"].(SecretValue).Reveal(); v != "hunter2" {
+		t.Errorf("Reveal: %#v", v)
+	}
+	if v := (SecretValue{}).Reveal(); v != nil {
+		t.Errorf("Reveal: %#v", v)
+	}
+}
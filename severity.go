@@ -0,0 +1,110 @@
+package guru
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is a severity level; higher levels are more severe.
+type Level int
+
+// Severity levels.
+const (
+	LevelDebug Level = iota + 1
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levelNames = []string{"", "debug", "info", "warn", "error", "fatal"}
+
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// MarshalText encodes the level as its name.
+func (l Level) MarshalText() ([]byte, error) { return []byte(l.String()), nil }
+
+// UnmarshalText decodes a level from its name.
+func (l *Level) UnmarshalText(text []byte) error {
+	t := strings.ToLower(string(text))
+	for i, n := range levelNames {
+		if n != "" && n == t {
+			*l = Level(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("guru.Level: unknown level %q", text)
+}
+
+type withSeverity struct {
+	error
+	level Level
+}
+
+func (e *withSeverity) Unwrap() error { return e.error }
+func (e withSeverity) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.error) {
+		fmt.Fprintf(s, "\nseverity: %s", e.level)
+	}
+}
+
+// WithSeverity annotates err with the severity level. It will return nil if
+// err is nil.
+func WithSeverity(err error, level Level) error {
+	if err == nil {
+		return nil
+	}
+	return &withSeverity{error: err, level: level}
+}
+
+// RegisterSeverity registers the default severity level for errors with the
+// error code code, for errors without a level set with WithSeverity.
+func (r *Registry) RegisterSeverity(code int, level Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.severity == nil {
+		r.severity = make(map[int]Level)
+	}
+	r.severity[code] = level
+}
+
+// Severity gets the severity level of err.
+//
+// The highest-level level set with WithSeverity is used. If there is none
+// then the level registered with RegisterSeverity for Code(err) is used, and
+// if there isn't one then it's LevelError. It will return 0 if err is nil.
+func (r *Registry) Severity(err error) Level {
+	if err == nil {
+		return 0
+	}
+	var level Level
+	walk(err, func(err error) bool {
+		if s, ok := err.(*withSeverity); ok {
+			level = s.level
+		}
+		return level == 0
+	})
+	if level != 0 {
+		return level
+	}
+
+	if code, ok := mappedCode(err); ok {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		if l, ok := r.severity[code]; ok {
+			return l
+		}
+	}
+	return LevelError
+}
+
+// RegisterSeverity calls DefaultRegistry.RegisterSeverity.
+func RegisterSeverity(code int, level Level) { DefaultRegistry.RegisterSeverity(code, level) }
+
+// Severity calls Severity on the registry of err; see RegistryOf.
+func Severity(err error) Level { return RegistryOf(err).Severity(err) }
@@ -0,0 +1,64 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	resetRegistry(t)
+	RegisterSeverity(1, LevelDebug)
+
+	tests := []struct {
+		in       error
+		want     Level
+		wantPlus string
+	}{
+		{nil, 0, "<nil>"},
+		{errors.New("x"), LevelError, "x"},
+		{New(1, "x"), LevelDebug, "error 1: x"},
+		{New(2, "x"), LevelError, "error 2: x"},
+		{WithSeverity(New(1, "x"), LevelFatal), LevelFatal, "error 1: x\nseverity: fatal"},
+		{Wrap(2, WithSeverity(New(1, "x"), LevelInfo), "y"), LevelInfo, "error 2: y\nerror 1: x\nseverity: info"},
+		{WithSeverity(WithSeverity(New(1, "x"), LevelInfo), LevelWarn), LevelWarn,
+			"error 1: x\nseverity: info\nseverity: warn"},
+		{WithSeverity(nil, LevelWarn), 0, "<nil>"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := Severity(tt.in)
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if plus := fmt.Sprintf("%+v", tt.in); plus != tt.wantPlus {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", plus, tt.wantPlus)
+			}
+		})
+	}
+
+	if !(LevelDebug < LevelInfo && LevelInfo < LevelWarn && LevelWarn < LevelError && LevelError < LevelFatal) {
+		t.Error("not ordered")
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for _, l := range []Level{LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal} {
+		text, _ := l.MarshalText()
+		var back Level
+		if err := back.UnmarshalText(text); err != nil || back != l {
+			t.Errorf("%s: %v %v", text, back, err)
+		}
+	}
+
+	if s := fmt.Sprintf("%s|%s", Level(0), Level(9)); s != "|Level(9)" {
+		t.Errorf("%q", s)
+	}
+	var l Level
+	if err := l.UnmarshalText([]byte("WARN")); err != nil || l != LevelWarn {
+		t.Errorf("%v %v", l, err)
+	}
+	if err := l.UnmarshalText([]byte("x")); err == nil {
+		t.Error("err is nil")
+	}
+}
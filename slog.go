@@ -0,0 +1,81 @@
+package guru
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// The error types implement slog.LogValuer, so that logging them with
+// log/slog (e.g. slog.Any("err", err)) emits structured attributes; see
+// SlogAttrs.
+func (e constError) LogValue() slog.Value     { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withCode) LogValue() slog.Value      { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *wrapped) LogValue() slog.Value       { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withFields) LogValue() slog.Value    { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withStack) LogValue() slog.Value     { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withRetry) LogValue() slog.Value     { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withTimeout) LogValue() slog.Value   { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withPublic) LogValue() slog.Value    { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withSeverity) LogValue() slog.Value  { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withRequestID) LogValue() slog.Value { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withNote) LogValue() slog.Value      { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withOp) LogValue() slog.Value        { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withDetail) LogValue() slog.Value    { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *withRelated) LogValue() slog.Value   { return slog.GroupValue(SlogAttrs(e)...) }
+func (e *stamped) LogValue() slog.Value       { return slog.GroupValue(SlogAttrs(e)...) }
+func (g *Group) LogValue() slog.Value         { return slog.GroupValue(SlogAttrs(g)...) }
+
+// SlogAttrs gets the attributes for err for log/slog:
+//
+//	code        Code(), if there is a code.
+//	subcode     Subcode(), if it's not 0.
+//	message     The message without codes (the %s verb).
+//	request_id  RequestID(), if there is one.
+//	ops         Ops(), if there are any.
+//	fields      Group with Fields(), if there are any.
+//	stack       StackTrace() as a list of "function file:line", if there is one.
+//
+// It will return nil if err is nil.
+func SlogAttrs(err error) []slog.Attr {
+	if err == nil {
+		return nil
+	}
+
+	attrs := make([]slog.Attr, 0, 5)
+	if hasCode(err) {
+		attrs = append(attrs, slog.Int("code", Code(err)))
+		if sub := Subcode(err); sub != 0 {
+			attrs = append(attrs, slog.Int("subcode", sub))
+		}
+	}
+	attrs = append(attrs, slog.String("message", fmt.Sprintf("%s", err)))
+	if id := RequestID(err); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	if ops := Ops(err); len(ops) > 0 {
+		attrs = append(attrs, slog.Any("ops", ops))
+	}
+	if f := Fields(err); len(f) > 0 {
+		keys := make([]string, 0, len(f))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]slog.Attr, 0, len(f))
+		for _, k := range keys {
+			fields = append(fields, slog.Any(k, f[k]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fields...)})
+	}
+
+	if st := StackTrace(err); len(st) > 0 {
+		frames := make([]string, 0, len(st))
+		for _, f := range st {
+			frames = append(frames, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+		attrs = append(attrs, slog.Any("stack", frames))
+	}
+	return attrs
+}
@@ -0,0 +1,52 @@
+package guru
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"testing"
+)
+
+func TestSlog(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{errors.New("oh noes"), `{"msg":"x","err":"oh noes"}`},
+		{New(42, "oh noes"), `{"msg":"x","err":{"code":42,"message":"oh noes"}}`},
+		{NewSub(42, 3, "oh noes"), `{"msg":"x","err":{"code":42,"subcode":3,"message":"oh noes"}}`},
+		{Wrap(42, WithFields(errors.New("oh noes"), map[string]interface{}{"b": 2, "a": "x"}), "ctx"),
+			`{"msg":"x","err":{"code":42,"message":"oh noes: ctx","fields":{"a":"x","b":2}}}`},
+		{WithPublic(errors.New("oh noes"), "x"), `{"msg":"x","err":{"message":"oh noes"}}`},
+		{WithRequestID(New(1, "oh noes"), "abc"),
+			`{"msg":"x","err":{"code":1,"message":"oh noes \(request_id: abc\)","request_id":"abc"}}`},
+		{Append(nil, New(1, "a"), New(2, "b")), `{"msg":"x","err":{"code":1,"message":"a\\nb"}}`},
+		{NewStack(42, "oh noes"),
+			`{"msg":"x","err":{"code":42,"message":"oh noes","stack":\["zgo.at/guru.TestSlog .*/slog_test.go:\d+",.*\]}}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			l := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			l.Info("x", "err", tt.in)
+
+			out := bytes.TrimSpace(buf.Bytes())
+			if !regexp.MustCompile(`^` + tt.want + `$`).Match(out) {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+		})
+	}
+
+	if SlogAttrs(nil) != nil {
+		t.Error("not nil")
+	}
+}
@@ -0,0 +1,122 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// stackTracer is implemented by errors that recorded a call stack.
+type stackTracer interface {
+	StackTrace() []runtime.Frame
+}
+
+type stack []uintptr
+
+// StackOptions configures how call stacks are recorded, for example with
+// NewStack and WithStack.
+type StackOptions struct {
+	Disable bool // Don't record call stacks; errors will have an empty stack.
+	Depth   int  // Maximum number of frames; 0 means the default of 32.
+	Skip    int  // Extra frames to skip at the top, e.g. for helper functions.
+}
+
+var stackOpts atomic.Pointer[StackOptions]
+
+// SetStackOptions sets the options for recording call stacks, for example to
+// record shallow stacks (or none at all) in production. It's safe to call
+// concurrently, and applies to errors created after the call.
+func SetStackOptions(opts StackOptions) { stackOpts.Store(&opts) }
+
+// callers records the call stack, skipping skip frames (0 being the caller of
+// callers).
+func callers(skip int) stack {
+	depth := 32
+	if o := stackOpts.Load(); o != nil {
+		if o.Disable {
+			return nil
+		}
+		if o.Depth > 0 {
+			depth = o.Depth
+		}
+		if o.Skip > 0 {
+			skip += o.Skip
+		}
+	}
+	pc := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pc)
+	return stack(pc[:n])
+}
+
+func (s stack) StackTrace() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	var (
+		frames = runtime.CallersFrames(s)
+		st     = make([]runtime.Frame, 0, len(s))
+	)
+	for {
+		f, more := frames.Next()
+		st = append(st, f)
+		if !more {
+			break
+		}
+	}
+	return st
+}
+
+func (s stack) write(w io.Writer) {
+	for _, f := range s.StackTrace() {
+		fmt.Fprintf(w, "\n\t%s\n\t\t%s:%d", f.Function, f.File, f.Line)
+	}
+}
+
+type withStack struct {
+	error
+	stack
+}
+
+func (e *withStack) Unwrap() error { return e.error }
+func (e withStack) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.error) {
+		e.stack.write(s)
+	}
+}
+
+// NewStack is like New, but also records the call stack.
+func NewStack(code int, msg string) error {
+	return created(KindNew, code, msg, nil, &withStack{
+		error: &withCode{error: errors.New(msg), code: code},
+		stack: callers(1),
+	})
+}
+
+// WithStack annotates err with the call stack at the point WithStack was
+// called. It will return nil if err is nil.
+//
+// The stack is printed with the %+v verb.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withStack{
+		error: err,
+		stack: callers(1),
+	}
+}
+
+// StackTrace returns the call stack of the first error in the chain that
+// recorded one, or nil if none of them did.
+func StackTrace(err error) []runtime.Frame {
+	var st []runtime.Frame
+	walk(err, func(err error) bool {
+		if s, ok := err.(stackTracer); ok {
+			st = s.StackTrace()
+		}
+		return st == nil
+	})
+	return st
+}
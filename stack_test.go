@@ -0,0 +1,104 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var _ error = &withStack{}
+var _ stackTracer = &withStack{}
+
+func TestStackTrace(t *testing.T) {
+	tests := []struct {
+		in       error
+		want     string
+		wantCode int
+	}{
+		{errors.New("foo"), "", 0},
+		{New(1, "foo"), "", 1},
+		{NewStack(1, "foo"), "zgo.at/guru.TestStackTrace", 1},
+		{WithStack(errors.New("foo")), "zgo.at/guru.TestStackTrace", 0},
+		{Wrap(2, WithStack(New(1, "foo")), "bar"), "zgo.at/guru.TestStackTrace", 2},
+		{fmt.Errorf("x: %w", NewStack(1, "foo")), "zgo.at/guru.TestStackTrace", 1},
+		{errors.Join(errors.New("x"), NewStack(1, "foo")), "zgo.at/guru.TestStackTrace", 1},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			st := StackTrace(tt.in)
+			var out string
+			if len(st) > 0 {
+				out = st[0].Function
+			}
+			if out != tt.want {
+				t.Errorf("\nout:  %#v\nwant: %#v\n", out, tt.want)
+			}
+			if c := Code(tt.in); c != tt.wantCode {
+				t.Errorf("code: %d; want %d", c, tt.wantCode)
+			}
+		})
+	}
+
+	if WithStack(nil) != nil {
+		t.Error("WithStack(nil) not nil")
+	}
+}
+
+func TestFormatStack(t *testing.T) {
+	err := NewStack(42, "oh noes")
+
+	if out := fmt.Sprintf("%v", err); out != "error 42: oh noes" {
+		t.Errorf("%%v: %q", out)
+	}
+
+	out := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(out, "error 42: oh noes\n\tzgo.at/guru.TestFormatStack\n\t\t") {
+		t.Errorf("%%+v:\n%s", out)
+	}
+	if !strings.Contains(out, "stack_test.go:") {
+		t.Errorf("%%+v: no file:\n%s", out)
+	}
+}
+
+func TestFormatStackChain(t *testing.T) {
+	err := Wrap(2, NewStack(1, "oh noes"), "ctx")
+
+	if out := fmt.Sprintf("%s", err); out != "oh noes: ctx" {
+		t.Errorf("%%s: %q", out)
+	}
+	out := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(out, "error 2: ctx\nerror 1: oh noes\n\tzgo.at/guru.TestFormatStackChain\n\t\t") {
+		t.Errorf("%%+v:\n%s", out)
+	}
+}
+
+func TestSetStackOptions(t *testing.T) {
+	t.Cleanup(func() { SetStackOptions(StackOptions{}) })
+
+	helper := func() error { return NewStack(1, "foo") }
+
+	tests := []struct {
+		opts      StackOptions
+		wantLen   int
+		wantFirst string
+	}{
+		{StackOptions{}, -1, "zgo.at/guru.TestSetStackOptions.func2"},
+		{StackOptions{Disable: true}, 0, ""},
+		{StackOptions{Depth: 1}, 1, "zgo.at/guru.TestSetStackOptions.func2"},
+		{StackOptions{Depth: 2, Skip: 1}, 2, "zgo.at/guru.TestSetStackOptions.func3"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			SetStackOptions(tt.opts)
+			st := StackTrace(helper())
+			if tt.wantLen >= 0 && len(st) != tt.wantLen {
+				t.Errorf("len\nout:  %d\nwant: %d\n", len(st), tt.wantLen)
+			}
+			if tt.wantFirst != "" && (len(st) == 0 || st[0].Function != tt.wantFirst) {
+				t.Errorf("\nout:  %v\nwant: %s\n", st, tt.wantFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package guru
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// stamped is information recorded when an error is created, if enabled.
+type stamped struct {
+	error
+	time time.Time
+	rt   *RuntimeInfo
+	id   string
+}
+
+func (e *stamped) Unwrap() error { return e.error }
+func (e stamped) Format(s fmt.State, verb rune) {
+	if formatInner(s, verb, e.error) {
+		if e.id != "" {
+			fmt.Fprintf(s, "\nid: %s", e.id)
+		}
+		if !e.time.IsZero() {
+			fmt.Fprintf(s, "\ntime: %s", e.time.Format(time.RFC3339Nano))
+		}
+		if e.rt != nil {
+			fmt.Fprintf(s, "\nruntime: host=%s pid=%d program=%s version=%s",
+				e.rt.Host, e.rt.PID, e.rt.Program, e.rt.Version)
+		}
+	}
+}
+
+// RuntimeInfo is information about the process that created an error; see
+// EnableRuntimeInfo.
+type RuntimeInfo struct {
+	Host    string `json:"host,omitempty"`    // From os.Hostname.
+	PID     int    `json:"pid,omitempty"`     // From os.Getpid.
+	Program string `json:"program,omitempty"` // Main module path from debug.ReadBuildInfo.
+	Version string `json:"version,omitempty"` // Main module version, or the VCS revision for "(devel)" builds.
+}
+
+var (
+	stampTime    atomic.Bool
+	stampRuntime atomic.Bool
+	now          = time.Now // For tests.
+	runtimeInfo  = sync.OnceValue(readRuntimeInfo)
+)
+
+func readRuntimeInfo() *RuntimeInfo {
+	rt := &RuntimeInfo{PID: os.Getpid()}
+	rt.Host, _ = os.Hostname()
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		rt.Program, rt.Version = bi.Main.Path, bi.Main.Version
+		if rt.Version == "" || rt.Version == "(devel)" {
+			for _, s := range bi.Settings {
+				if s.Key == "vcs.revision" {
+					rt.Version = s.Value
+				}
+			}
+		}
+	}
+	return rt
+}
+
+// EnableTimestamps records the time errors are created with the functions that
+// accept an error code, such as New, Errorf, Wrap, and WithCode; see Time.
+//
+// This is disabled by default, as it adds some overhead to creating errors.
+// It's safe to call concurrently, and applies to errors created after the
+// call.
+func EnableTimestamps() { stampTime.Store(true) }
+
+// EnableRuntimeInfo records the host, PID, and version of the program with
+// errors created with the functions that accept an error code, such as New,
+// Errorf, Wrap, and WithCode; see Runtime.
+//
+// This is useful if errors from many machines are collected in one place. It's
+// disabled by default, as it adds some overhead to creating errors. It's safe
+// to call concurrently, and applies to errors created after the call.
+func EnableRuntimeInfo() { stampRuntime.Store(true) }
+
+// stamp records the enabled information for err.
+func stamp(err error) error {
+	t, rt, id := stampTime.Load(), stampRuntime.Load(), stampID.Load()
+	if !t && !rt && !id {
+		return err
+	}
+	s := &stamped{error: err}
+	if t {
+		s.time = now()
+	}
+	if id {
+		s.id = newULID()
+	}
+	if rt {
+		s.rt = runtimeInfo()
+	}
+	return s
+}
+
+// Time gets the time the innermost error in the chain with a timestamp was
+// created, which is usually when the failure occurred. It will return the zero
+// time if there is no timestamp, for example because EnableTimestamps wasn't
+// called.
+func Time(err error) time.Time {
+	var t time.Time
+	walk(err, func(err error) bool {
+		if s, ok := err.(*stamped); ok && !s.time.IsZero() {
+			t = s.time
+		}
+		return true
+	})
+	return t
+}
+
+// Runtime gets the runtime information of the innermost error in the chain
+// that has it. It will return false if there is none, for example because
+// EnableRuntimeInfo wasn't called.
+func Runtime(err error) (RuntimeInfo, bool) {
+	var rt *RuntimeInfo
+	walk(err, func(err error) bool {
+		if s, ok := err.(*stamped); ok && s.rt != nil {
+			rt = s.rt
+		}
+		return true
+	})
+	if rt == nil {
+		return RuntimeInfo{}, false
+	}
+	return *rt, true
+}
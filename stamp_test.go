@@ -0,0 +1,108 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func enableTimestamps(t *testing.T, at ...time.Time) {
+	t.Helper()
+	i := 0
+	now = func() time.Time { i++; return at[i-1] }
+	EnableTimestamps()
+	t.Cleanup(func() {
+		stampTime.Store(false)
+		now = time.Now
+	})
+}
+
+func TestTime(t *testing.T) {
+	var (
+		t1 = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		t2 = t1.Add(time.Minute)
+	)
+	enableTimestamps(t, t1, t2)
+
+	err := Wrap(2, New(1, "x"), "y")
+	if out := Time(err); !out.Equal(t1) {
+		t.Errorf("\nout:  %s\nwant: %s\n", out, t1)
+	}
+	if Code(err) != 2 || !Is(err, 2) || fmt.Sprintf("%v", err) != "error 2: error 1: x: y" {
+		t.Errorf("%v", err)
+	}
+
+	wantPlus := "error 2: y\nerror 1: x\ntime: 2024-06-01T12:00:00Z\ntime: 2024-06-01T12:01:00Z"
+	if out := fmt.Sprintf("%+v", err); out != wantPlus {
+		t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, wantPlus)
+	}
+
+	j, jErr := MarshalJSON(err)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	wantJSON := `{"code":2,"message":"y","time":"2024-06-01T12:01:00Z","wrapped":{"code":1,"message":"x","time":"2024-06-01T12:00:00Z"}}`
+	if string(j) != wantJSON {
+		t.Errorf("\nout:  %s\nwant: %s\n", j, wantJSON)
+	}
+	if out := Time(mustFromJSON(t, string(j))); !out.Equal(t1) {
+		t.Errorf("\nout:  %s\nwant: %s\n", out, t1)
+	}
+}
+
+func TestTimeDisabled(t *testing.T) {
+	err := New(1, "x")
+	if _, ok := err.(*withCode); !ok {
+		t.Errorf("wrong type: %T", err)
+	}
+	if out := Time(err); !out.IsZero() {
+		t.Error(out)
+	}
+	if out := Time(errors.New("x")); !out.IsZero() {
+		t.Error(out)
+	}
+}
+
+func TestRuntime(t *testing.T) {
+	rt := &RuntimeInfo{Host: "web1", PID: 42, Program: "example.com/app", Version: "v1.2.3"}
+	old := runtimeInfo
+	runtimeInfo = func() *RuntimeInfo { return rt }
+	EnableRuntimeInfo()
+	t.Cleanup(func() {
+		stampRuntime.Store(false)
+		runtimeInfo = old
+	})
+
+	err := New(1, "x")
+	if out, ok := Runtime(err); !ok || out != *rt {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", out, *rt)
+	}
+	if out := Time(err); !out.IsZero() {
+		t.Error(out)
+	}
+
+	wantPlus := "error 1: x\nruntime: host=web1 pid=42 program=example.com/app version=v1.2.3"
+	if out := fmt.Sprintf("%+v", err); out != wantPlus {
+		t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, wantPlus)
+	}
+
+	j, jErr := MarshalJSON(err)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	wantJSON := `{"code":1,"message":"x","runtime":{"host":"web1","pid":42,"program":"example.com/app","version":"v1.2.3"}}`
+	if string(j) != wantJSON {
+		t.Errorf("\nout:  %s\nwant: %s\n", j, wantJSON)
+	}
+	if out, ok := Runtime(mustFromJSON(t, string(j))); !ok || out != *rt {
+		t.Errorf("\nout:  %#v\nwant: %#v\n", out, *rt)
+	}
+
+	if _, ok := Runtime(errors.New("x")); ok {
+		t.Error("ok for error without runtime info")
+	}
+	if p := readRuntimeInfo().PID; p == 0 {
+		t.Error("no PID")
+	}
+}
@@ -0,0 +1,58 @@
+package guru
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	statsMu     sync.Mutex
+	statsRemove func() // Removes the hook.
+	published   bool
+	counts      sync.Map // int → *atomic.Uint64
+)
+
+// EnableStats counts the errors created per code, and publishes the counts
+// with expvar as "guru_errors". They can be retrieved with Stats.
+//
+// This uses AddHook, so every error created or wrapped with one of the
+// functions that accept an error code is counted. It's safe to call more than
+// once.
+func EnableStats() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	if statsRemove == nil {
+		statsRemove = AddHook(func(ev Event) {
+			c, ok := counts.Load(ev.Code)
+			if !ok {
+				c, _ = counts.LoadOrStore(ev.Code, new(atomic.Uint64))
+			}
+			c.(*atomic.Uint64).Add(1)
+		})
+	}
+	if !published {
+		published = true
+		expvar.Publish("guru_errors", expvar.Func(func() interface{} {
+			s := Stats()
+			m := make(map[string]uint64, len(s))
+			for code, n := range s {
+				m[strconv.Itoa(code)] = n
+			}
+			return m
+		}))
+	}
+}
+
+// Stats gets a snapshot of the number of errors created per code since
+// EnableStats was called. It will return an empty map if EnableStats wasn't
+// called.
+func Stats() map[int]uint64 {
+	s := make(map[int]uint64)
+	counts.Range(func(k, v interface{}) bool {
+		s[k.(int)] = v.(*atomic.Uint64).Load()
+		return true
+	})
+	return s
+}
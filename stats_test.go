@@ -0,0 +1,40 @@
+package guru
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	EnableStats()
+	EnableStats()
+	t.Cleanup(func() {
+		statsMu.Lock()
+		defer statsMu.Unlock()
+		statsRemove()
+		statsRemove = nil
+	})
+
+	before := Stats()
+	New(9001, "x")
+	New(9001, "x")
+	Wrap(9002, errors.New("x"), "y")
+
+	s := Stats()
+	if d := s[9001] - before[9001]; d != 2 {
+		t.Errorf("9001: %d", d)
+	}
+	if d := s[9002] - before[9002]; d != 1 {
+		t.Errorf("9002: %d", d)
+	}
+
+	var pub map[string]uint64
+	if err := json.Unmarshal([]byte(expvar.Get("guru_errors").String()), &pub); err != nil {
+		t.Fatal(err)
+	}
+	if pub["9001"] != s[9001] || pub["9002"] != s[9002] {
+		t.Errorf("\nout:  %v\nwant: %v\n", pub, s)
+	}
+}
@@ -0,0 +1,44 @@
+package guru
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+var strict atomic.Bool
+
+// SetStrict sets if New, Errorf, Wrap, etc. panic when they're called with an
+// invalid code: 0, a negative code, or a code that's not registered in
+// DefaultRegistry (or the registry for Registry.New, Registry.Wrap, etc.). A
+// code is registered if Lookup reports it. This also applies to codes from
+// Classify.
+//
+// This is intended for development and tests, to catch typos in codes.
+func SetStrict(on bool) { strict.Store(on) }
+
+// checkStrict panics if strict mode is enabled and code is invalid for the
+// registry err was created with. skip is the number of stack frames to skip
+// for the location of the caller, with 0 being the caller of checkStrict.
+func checkStrict(err error, code, skip int) {
+	if !strict.Load() {
+		return
+	}
+
+	var msg string
+	switch {
+	case code == 0:
+		msg = "guru: code 0 is the same as no code"
+	case code < 0:
+		msg = fmt.Sprintf("guru: negative code %d", code)
+	default:
+		if _, ok := registryOf(err, false).Lookup(code); ok {
+			return
+		}
+		msg = fmt.Sprintf("guru: code %d is not registered", code)
+	}
+	if _, file, line, ok := runtime.Caller(skip + 2); ok {
+		msg += fmt.Sprintf(" (at %s:%d)", file, line)
+	}
+	panic(msg)
+}
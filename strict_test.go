@@ -0,0 +1,54 @@
+package guru
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetStrict(t *testing.T) {
+	resetRegistry(t)
+	Register(4012, "ErrInvoiceMissing", "")
+	RegisterHTTPStatus(4013, 404)
+	RegisterCategory(4000, 4999, "billing")
+	reg := &Registry{}
+	reg.Register(5, "ErrFive", "")
+
+	_ = New(0, "not strict")
+
+	SetStrict(true)
+	t.Cleanup(func() { SetStrict(false) })
+
+	tests := []struct {
+		fn   func()
+		want string
+	}{
+		{func() { New(4012, "x") }, ""},
+		{func() { Wrap(4013, io.EOF, "x") }, ""},
+		{func() { reg.New(5, "x") }, ""},
+		{func() { WithCode(0, nil) }, ""},
+		{func() { New(0, "x") }, "guru: code 0 is the same as no code (at "},
+		{func() { Errorf(-1, "x") }, "guru: negative code -1 (at "},
+		{func() { WithCode(4014, io.EOF) }, "guru: code 4014 is not registered (at "},
+		{func() { reg.New(4012, "x") }, "guru: code 4012 is not registered (at "},
+		{func() { Classify(io.EOF) }, "guru: code 400 is not registered (at "},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if tt.want == "" {
+					if r != nil {
+						t.Fatalf("panic: %v", r)
+					}
+					return
+				}
+				if s, _ := r.(string); !strings.HasPrefix(s, tt.want) || !strings.Contains(s, "strict_test.go:") {
+					t.Errorf("\nout:  %#v\nwant: %#v\n", r, tt.want)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
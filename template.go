@@ -0,0 +1,224 @@
+package guru
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+)
+
+// TemplateData is passed to templates set with SetTemplate.
+type TemplateData struct {
+	Code     int
+	Name     string                 // Registered name.
+	Category string                 // Registered category.
+	Message  string                 // The message of the error.
+	Fields   map[string]interface{} // Fields of the error, the errors it wraps, and the errors wrapping it.
+}
+
+// hasTemplates is set if any template was set, to avoid the overhead of
+// looking up templates for every Error() call.
+var hasTemplates atomic.Bool
+
+// SetTemplate sets a text/template to render the message of errors with the
+// code code, for example to add guidance on how to fix the error:
+//
+//	guru.SetTemplate(4010, `{{.Message}}; run "tool login" to log in again`)
+//
+// The template is executed with TemplateData. It's used by Error() and Format
+// of errors created with New, Wrap, etc. with this code (but not Const or
+// errors with a generic code), and replaces the message of that error only.
+// The original message is used if the template fails. An empty tmpl removes
+// the template.
+//
+// The message is rendered once for errors created after a template was set,
+// so changing the template doesn't change the message of existing errors.
+// Errors created before the first template was set aren't cached, and render
+// the message on every call.
+//
+// The message in JSON, gob, etc. encodings is never changed.
+func (r *Registry) SetTemplate(code int, tmpl string) error {
+	t, err := parseTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tmpl == nil {
+		r.tmpl = make(map[int]*template.Template)
+	}
+	if t == nil {
+		delete(r.tmpl, code)
+	} else {
+		r.tmpl[code] = t
+	}
+	return nil
+}
+
+// SetCategoryTemplate is like SetTemplate, but sets the template for all codes
+// in the category name (registered with RegisterCategory). Templates for a
+// code take precedence.
+func (r *Registry) SetCategoryTemplate(name, tmpl string) error {
+	t, err := parseTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.catTmpl == nil {
+		r.catTmpl = make(map[string]*template.Template)
+	}
+	if t == nil {
+		delete(r.catTmpl, name)
+	} else {
+		r.catTmpl[name] = t
+	}
+	return nil
+}
+
+// SetTemplate calls DefaultRegistry.SetTemplate.
+func SetTemplate(code int, tmpl string) error { return DefaultRegistry.SetTemplate(code, tmpl) }
+
+// SetCategoryTemplate calls DefaultRegistry.SetCategoryTemplate.
+func SetCategoryTemplate(name, tmpl string) error {
+	return DefaultRegistry.SetCategoryTemplate(name, tmpl)
+}
+
+func parseTemplate(tmpl string) (*template.Template, error) {
+	if tmpl == "" {
+		return nil, nil
+	}
+	t, err := template.New("").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	hasTemplates.Store(true)
+	return t, nil
+}
+
+// templated renders msg with the template for code in reg (or
+// DefaultRegistry if it's nil), reporting false if there is no template or if
+// it failed. err is the error that the error with the code wraps, and outer are
+// the fields of the errors wrapping it.
+func templated(reg *Registry, code int, msg string, err error, outer map[string]interface{}) (string, bool) {
+	if !hasTemplates.Load() {
+		return "", false
+	}
+	if reg == nil {
+		reg = DefaultRegistry
+	}
+
+	reg.mu.RLock()
+	t, ok := reg.tmpl[code]
+	cat := reg.categoryOf(code)
+	if !ok && cat != "" {
+		t, ok = reg.catTmpl[cat]
+	}
+	name := reg.info[code].Name
+	reg.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+
+	b := new(strings.Builder)
+	err = t.Execute(b, TemplateData{Code: code, Name: name, Category: cat, Message: msg,
+		Fields: mergeFields(outer, Fields(err))})
+	if err != nil {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// mergeFields merges the fields in high and low; high wins if a key is in both.
+func mergeFields(high, low map[string]interface{}) map[string]interface{} {
+	if len(high) == 0 {
+		return low
+	}
+	if len(low) == 0 {
+		return high
+	}
+	m := make(map[string]interface{}, len(high)+len(low))
+	for k, v := range low {
+		m[k] = v
+	}
+	for k, v := range high {
+		m[k] = v
+	}
+	return m
+}
+
+// withOuterFields returns a copy of err where the errors with a code have
+// fields for templates, for errors wrapped in an error with fields. Only the
+// types from this package that don't change the message are followed. The
+// messages of the copies are cached if memo is set.
+func withOuterFields(err error, fields map[string]interface{}, memo bool) error {
+	var tm *tmplMemo
+	if memo {
+		tm = new(tmplMemo)
+	}
+	switch e := err.(type) {
+	case *withCode:
+		c := *e
+		c.outer, c.tm, c.error = mergeFields(e.outer, fields), tm, withOuterFields(e.error, fields, memo)
+		return &c
+	case *wrapped:
+		c := *e
+		c.outer, c.tm, c.error = mergeFields(e.outer, fields), tm, withOuterFields(e.error, fields, memo)
+		return &c
+	case *withFields:
+		c := *e
+		c.applied, c.in, c.error = true, nil, withOuterFields(e.error, mergeFields(fields, e.fields), memo)
+		return &c
+	case *withStack:
+		c := *e
+		c.error = withOuterFields(e.error, fields, memo)
+		return &c
+	case *stamped:
+		c := *e
+		c.error = withOuterFields(e.error, fields, memo)
+		return &c
+	}
+	return err
+}
+
+// setMemo sets the caches for templates on a new error created with code. Only
+// the wrappers directly around the error with the code are changed, as
+// anything below it already exists.
+func setMemo(err error) {
+	if !hasTemplates.Load() {
+		return
+	}
+	for {
+		switch e := err.(type) {
+		case *withCode:
+			e.tm = new(tmplMemo)
+			return
+		case *wrapped:
+			e.tm = new(tmplMemo)
+			return
+		case *withFields:
+			e.in = new(fieldsMemo)
+			err = e.error
+		case *withStack:
+			err = e.error
+		default:
+			return
+		}
+	}
+}
+
+// tmplMemo caches the message rendered with a template. A nil tmplMemo doesn't
+// cache anything.
+type tmplMemo struct {
+	once sync.Once
+	msg  string
+	ok   bool
+}
+
+func (m *tmplMemo) get(fn func() (string, bool)) (string, bool) {
+	if m == nil {
+		return fn()
+	}
+	m.once.Do(func() { m.msg, m.ok = fn() })
+	return m.msg, m.ok
+}
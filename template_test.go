@@ -0,0 +1,181 @@
+package guru
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetTemplate(t *testing.T) {
+	resetRegistry(t)
+	t.Cleanup(func() { hasTemplates.Store(false) })
+	Register(4010, "ErrLoggedOut", "")
+	RegisterCategory(5000, 5999, "storage")
+
+	if err := SetTemplate(4010, `{{.Message}}; run "tool login" to log in again`); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTemplate(4011, `{{.Name}} {{.Fields.user}} {{.Code}}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTemplate(4012, `{{.Nope}}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetCategoryTemplate("storage", `{{.Category}}: {{.Message}}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTemplate(5001, `{{.Message}} (5001)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTemplate(1, "{{"); err == nil {
+		t.Error("no error for invalid template")
+	}
+
+	reg := &Registry{}
+	reg.SetTemplate(4010, "{{.Message}} (own registry)")
+
+	tests := []struct {
+		in          error
+		wantErr     string
+		wantV       string
+		wantS       string
+		wantPlusV   string
+		wantMessage string
+	}{
+		{New(4010, "token expired"), `token expired; run "tool login" to log in again`,
+			`error 4010: token expired; run "tool login" to log in again`,
+			`token expired; run "tool login" to log in again`,
+			`error 4010 (ErrLoggedOut): token expired; run "tool login" to log in again`, "token expired"},
+		{Wrap(4010, io.EOF, "token expired"), `token expired; run "tool login" to log in again`,
+			`error 4010: EOF: token expired; run "tool login" to log in again`,
+			`EOF: token expired; run "tool login" to log in again`,
+			"error 4010 (ErrLoggedOut): token expired; run \"tool login\" to log in again\nEOF", "token expired"},
+		{WithCode(4010, fmt.Errorf("x: %w", io.EOF)), `x: EOF; run "tool login" to log in again`,
+			`error 4010: x: EOF; run "tool login" to log in again`,
+			`x: EOF; run "tool login" to log in again`,
+			"error 4010 (ErrLoggedOut): x: EOF; run \"tool login\" to log in again\nx: EOF", ""},
+		{Wrap(4011, WithField(io.EOF, "user", "bob"), "x"), " bob 4011", "error 4011: EOF:  bob 4011", "EOF:  bob 4011",
+			"error 4011:  bob 4011\nEOF\nfields: user=bob", "x"},
+		{New(4011, "x"), " <no value> 4011", "error 4011:  <no value> 4011", " <no value> 4011", "error 4011:  <no value> 4011", "x"},
+		{New(4012, "x"), "x", "error 4012: x", "x", "error 4012: x", "x"},
+		{New(5000, "x"), "storage: x", "error 5000: storage: x", "storage: x", "error 5000: storage: x", "x"},
+		{New(5001, "x"), "x (5001)", "error 5001: x (5001)", "x (5001)", "error 5001: x (5001)", "x"},
+		{reg.New(4010, "x"), "x (own registry)", "error 4010: x (own registry)", "x (own registry)", "error 4010: x (own registry)", "x"},
+		{Const(4010, "x"), "x", "error 4010: x", "x", "error 4010 (ErrLoggedOut): x", "x"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := tt.in.Error(); out != tt.wantErr {
+				t.Errorf("Error()\nout:  %#v\nwant: %#v\n", out, tt.wantErr)
+			}
+			if out := fmt.Sprintf("%v", tt.in); out != tt.wantV {
+				t.Errorf("%%v\nout:  %#v\nwant: %#v\n", out, tt.wantV)
+			}
+			if out := fmt.Sprintf("%s", tt.in); out != tt.wantS {
+				t.Errorf("%%s\nout:  %#v\nwant: %#v\n", out, tt.wantS)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlusV {
+				t.Errorf("%%+v\nout:  %#v\nwant: %#v\n", out, tt.wantPlusV)
+			}
+
+			j, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var m struct{ Message string }
+			json.Unmarshal(j, &m)
+			if m.Message != tt.wantMessage {
+				t.Errorf("JSON\nout:  %#v\nwant: %#v\n", m.Message, tt.wantMessage)
+			}
+		})
+	}
+
+	if out := Tree(New(4010, "x")); !strings.HasSuffix(out, `run "tool login" to log in again`) {
+		t.Errorf("Tree: %q", out)
+	}
+
+	SetTemplate(4010, "")
+	if out := New(4010, "x").Error(); out != "x" {
+		t.Errorf("not removed: %q", out)
+	}
+}
+
+type renderCount struct{ n int }
+
+func (c *renderCount) Count() int { c.n++; return c.n }
+
+func TestTemplateFields(t *testing.T) {
+	resetRegistry(t)
+	t.Cleanup(func() { hasTemplates.Store(false) })
+	if err := SetTemplate(4011, `{{.Message}} for {{.Fields.user}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in           error
+		wantErr      string
+		wantV, wantS string
+		wantTree     string
+	}{
+		{WithFields(New(4011, "x"), map[string]interface{}{"user": "bob"}),
+			"x for bob", "error 4011: x for bob", "x for bob", "error 4011: x for bob"},
+		{E(4011, Msg("x"), Field("user", "bob"), Stack()),
+			"x for bob", "error 4011: x for bob", "x for bob", "error 4011: x for bob"},
+		{WithField(WithField(New(4011, "x"), "user", "alice"), "user", "bob"),
+			"x for bob", "error 4011: x for bob", "x for bob", "error 4011: x for bob"},
+		{WithField(WithStack(WithField(New(4011, "x"), "user", "alice")), "user", "bob"),
+			"x for bob", "error 4011: x for bob", "x for bob", "error 4011: x for bob"},
+		{WithField(Wrap(4011, WithField(io.EOF, "user", "alice"), "x"), "n", 1),
+			"x for alice", "error 4011: EOF: x for alice", "EOF: x for alice", "error 4011: x for alice\nEOF"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := tt.in.Error(); out != tt.wantErr {
+				t.Errorf("Error()\nout:  %#v\nwant: %#v\n", out, tt.wantErr)
+			}
+			if out := fmt.Sprintf("%v", tt.in); out != tt.wantV {
+				t.Errorf("%%v\nout:  %#v\nwant: %#v\n", out, tt.wantV)
+			}
+			if out := fmt.Sprintf("%s", tt.in); out != tt.wantS {
+				t.Errorf("%%s\nout:  %#v\nwant: %#v\n", out, tt.wantS)
+			}
+			if out := Tree(tt.in); out != tt.wantTree {
+				t.Errorf("Tree\nout:  %#v\nwant: %#v\n", out, tt.wantTree)
+			}
+		})
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		if err := SetTemplate(4012, `{{.Message}} {{.Fields.n.Count}}`); err != nil {
+			t.Fatal(err)
+		}
+		for _, err := range []error{
+			Wrap(4012, WithField(io.EOF, "n", new(renderCount)), "x"),
+			WithField(New(4012, "x"), "n", new(renderCount)),
+		} {
+			for i := 0; i < 3; i++ {
+				if out := err.Error(); !strings.HasSuffix(out, "x 1") {
+					t.Errorf("Error(): %q", out)
+				}
+				if out := fmt.Sprintf("%v", err); !strings.HasSuffix(out, "x 1") {
+					t.Errorf("%%v: %q", out)
+				}
+			}
+			if n := testing.AllocsPerRun(10, func() { _ = err.Error() }); n != 0 {
+				t.Errorf("%v allocations", n)
+			}
+		}
+
+		// Created before a template was set: no cache.
+		err := &withFields{error: &withCode{error: errors.New("x"), code: 4012},
+			fields: map[string]interface{}{"n": new(renderCount)}}
+		for i := 1; i <= 3; i++ {
+			if out, want := err.Error(), fmt.Sprintf("x %d", i); out != want {
+				t.Errorf("Error(): %q", out)
+			}
+		}
+	})
+}
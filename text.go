@@ -0,0 +1,264 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func (e constError) MarshalText() ([]byte, error)     { return MarshalText(e) }
+func (e *withCode) MarshalText() ([]byte, error)      { return MarshalText(e) }
+func (e *wrapped) MarshalText() ([]byte, error)       { return MarshalText(e) }
+func (e *withFields) MarshalText() ([]byte, error)    { return MarshalText(e) }
+func (e *withStack) MarshalText() ([]byte, error)     { return MarshalText(e) }
+func (e *withRetry) MarshalText() ([]byte, error)     { return MarshalText(e) }
+func (e *withTimeout) MarshalText() ([]byte, error)   { return MarshalText(e) }
+func (e *withPublic) MarshalText() ([]byte, error)    { return MarshalText(e) }
+func (e *withSeverity) MarshalText() ([]byte, error)  { return MarshalText(e) }
+func (e *withRequestID) MarshalText() ([]byte, error) { return MarshalText(e) }
+func (e *withNote) MarshalText() ([]byte, error)      { return MarshalText(e) }
+func (e *withOp) MarshalText() ([]byte, error)        { return MarshalText(e) }
+func (e *withDetail) MarshalText() ([]byte, error)    { return MarshalText(e) }
+func (e *withRelated) MarshalText() ([]byte, error)   { return MarshalText(e) }
+func (e *stamped) MarshalText() ([]byte, error)       { return MarshalText(e) }
+
+var reMarker = regexp.MustCompile(`^E(` + reCode + `)(?:\.(` + reCode + `))?$`)
+
+// MarshalText encodes err as a single line of text, preserving the codes and
+// messages of all errors in the chain. Use FromText to decode it.
+//
+// The text is a list of segments separated by ": ", where every segment is an
+// error code, a message, or a group:
+//
+//	E42: context: E1: oh noes
+//
+// A code is written as "E" followed by the code, optionally followed by "."
+// and the subcode ("E42.3"). A code applies to the message or group directly
+// after it, and every message wraps the segments after it. Two codes in a row
+// means the first error wraps the second without adding a message, and a code
+// followed by a colon ("E42:") wraps the message after it without adding one:
+//
+//	E42:: context: oh noes
+//
+// In messages the characters `:;[]\` and newlines are escaped with a
+// backslash, as is an "E" at the start of a message that would otherwise look
+// like a code.
+//
+// Groups are used for errors that wrap more than one error (such as those
+// created with errors.Join), and are written as a list of segments separated
+// by "; " in square brackets:
+//
+//	E42: [E1: oh noes; E2: not again]
+//
+// Fields, public messages, severity levels, request IDs, operations, details,
+// related errors, IDs, timestamps, runtime information, stack traces, and the
+// messages of errors that wrap more than one error are not preserved. It will
+// return an empty text if err is nil.
+func MarshalText(err error) ([]byte, error) {
+	return []byte(textChain(toJSON(err))), nil
+}
+
+// FromText decodes an error encoded with MarshalText.
+//
+// The errors in the chain won't be of the same type as the original errors,
+// but the messages and codes are preserved. It will return nil if the text is
+// empty.
+func FromText(text []byte) (error, error) {
+	if len(text) == 0 {
+		return nil, nil
+	}
+	j, err := parseChain(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("guru.FromText: %w", err)
+	}
+	return fromJSON(j), nil
+}
+
+func textChain(j *jsonError) string {
+	var seg []string
+	for ; j != nil; j = j.Wrapped {
+		if j.Code != nil {
+			m := "E" + fmtCode(int(*j.Code))
+			if j.Subcode != 0 {
+				m += "." + fmtCode(j.Subcode)
+			}
+			if w := j.Wrapped; j.Message == "" && w != nil && w.Code == nil && len(w.Errors) == 0 {
+				m += ":"
+			}
+			seg = append(seg, m)
+		}
+		if len(j.Errors) > 0 {
+			group := make([]string, 0, len(j.Errors))
+			for _, e := range j.Errors {
+				group = append(group, textChain(e))
+			}
+			seg = append(seg, "["+strings.Join(group, "; ")+"]")
+			break
+		}
+		if j.Message != "" {
+			seg = append(seg, escapeText(j.Message))
+		}
+	}
+	return strings.Join(seg, ": ")
+}
+
+func escapeText(msg string) string {
+	var b strings.Builder
+	b.Grow(len(msg) + 2)
+	if reMarker.MatchString(msg) {
+		b.WriteByte('\\')
+	}
+	for _, c := range msg {
+		switch c {
+		case ':', ';', '[', ']', '\\':
+			b.WriteByte('\\')
+		case '\n':
+			b.WriteString(`\n`)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// splitText splits s on the unescaped sep character followed by a space,
+// ignoring anything inside square brackets. A sep followed by another sep is
+// part of the segment, so "E42:: a" is split as "E42:" and "a".
+func splitText(s string, sep byte) ([]string, error) {
+	var (
+		seg   []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("unexpected ']'")
+			}
+		case sep:
+			if depth > 0 || (i+1 < len(s) && s[i+1] == sep) {
+				continue
+			}
+			if i+1 >= len(s) || s[i+1] != ' ' {
+				return nil, fmt.Errorf("no space after %q at position %d", sep, i)
+			}
+			seg = append(seg, s[start:i])
+			start = i + 2
+			i++
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("unclosed '['")
+	}
+	return append(seg, s[start:]), nil
+}
+
+func parseChain(s string) (*jsonError, error) {
+	seg, err := splitText(s, ':')
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		root, cur *jsonError
+		open      bool // cur has a code but no message yet.
+	)
+	next := func() {
+		n := &jsonError{}
+		if cur == nil {
+			root = n
+		} else {
+			cur.Wrapped = n
+		}
+		cur = n
+	}
+	for i, sg := range seg {
+		bare := strings.HasSuffix(sg, ":") && reMarker.MatchString(sg[:len(sg)-1])
+		if bare {
+			sg = sg[:len(sg)-1]
+		}
+		switch m := reMarker.FindStringSubmatch(sg); {
+		case m != nil:
+			next()
+			c, err := ParseCode(m[1])
+			if err != nil {
+				return nil, err
+			}
+			jc := jsonCode(c)
+			cur.Code = &jc
+			if m[2] != "" {
+				cur.Subcode, err = ParseCode(m[2])
+				if err != nil {
+					return nil, err
+				}
+			}
+			open = !bare
+		case strings.HasPrefix(sg, "[") && strings.HasSuffix(sg, "]"):
+			if i != len(seg)-1 {
+				return nil, errors.New("group must be the last segment")
+			}
+			group, err := splitText(sg[1:len(sg)-1], ';')
+			if err != nil {
+				return nil, err
+			}
+			if !open {
+				next()
+			}
+			for _, g := range group {
+				e, err := parseChain(g)
+				if err != nil {
+					return nil, err
+				}
+				cur.Errors = append(cur.Errors, e)
+			}
+		default:
+			msg, err := unescapeText(sg)
+			if err != nil {
+				return nil, err
+			}
+			if !open {
+				next()
+			}
+			cur.Message, open = msg, false
+		}
+	}
+	return root, nil
+}
+
+func unescapeText(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		if strings.ContainsAny(s, ":;[]") {
+			return "", fmt.Errorf("unescaped character in %q", s)
+		}
+		return s, nil
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\\':
+			i++
+			if i >= len(s) {
+				return "", fmt.Errorf("trailing backslash in %q", s)
+			}
+			if s[i] == 'n' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(s[i])
+			}
+		case ':', ';', '[', ']':
+			return "", fmt.Errorf("unescaped character in %q", s)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
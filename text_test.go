@@ -0,0 +1,127 @@
+package guru
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+var _ encoding.TextMarshaler = &withCode{}
+var _ encoding.TextMarshaler = &wrapped{}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{nil, ``},
+		{errors.New("oh noes"), `oh noes`},
+		{New(42, "oh noes"), `E42: oh noes`},
+		{New(42, ""), `E42`},
+		{NewSub(42, 3, "oh noes"), `E42.3: oh noes`},
+		{New(-1, "oh noes"), `E-1: oh noes`},
+		{Wrap(42, errors.New("oh noes"), "ctx"), `E42: ctx: oh noes`},
+		{WithCode(666, Wrap(42, New(1, "oh noes"), "ctx")), `E666: E42: ctx: E1: oh noes`},
+		{fmt.Errorf("ctx: %w", New(1, "oh noes")), `ctx: E1: oh noes`},
+		{fmt.Errorf("%w", New(1, "oh noes")), `E1: oh noes`},
+		{New(1, `a: b; [c] \ d`), `E1: a\: b\; \[c\] \\ d`},
+		{New(1, "E2"), `E1: \E2`},
+		{New(1, "a\nb"), `E1: a\nb`},
+		{errors.Join(New(1, "a"), errors.New("b")), `[E1: a; b]`},
+		{WithCode(2, errors.Join(Wrap(1, New(3, "x"), "a"), errors.New("b"))), `E2: [E1: a: E3: x; b]`},
+		{New(1, "E2:"), `E1: E2\:`},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, err := MarshalText(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+
+			back, err := FromText(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.in == nil {
+				if back != nil {
+					t.Fatalf("not nil: %#v", back)
+				}
+				return
+			}
+			if a, b := fmt.Sprintf("%v", back), fmt.Sprintf("%v", tt.in); a != b {
+				t.Errorf("message\nout:  %q\nwant: %q", a, b)
+			}
+			if a, b := Codes(back), Codes(tt.in); !reflect.DeepEqual(a, b) {
+				t.Errorf("codes\nout:  %v\nwant: %v", a, b)
+			}
+		})
+	}
+}
+
+func TestTextWrappedMessage(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{Errorf(3, "ctx: %w", io.EOF), `E3:: ctx: EOF`},
+		{WithCode(1, fmt.Errorf("x: %w", New(2, "y"))), `E1:: x: E2: y`},
+		{WithCode(1, fmt.Errorf("x: %w", fmt.Errorf("y: %w", io.EOF))), `E1:: x: y: EOF`},
+		{fmt.Errorf("a: %w", Errorf(3, "ctx: %w", io.EOF)), `a: E3:: ctx: EOF`},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, err := MarshalText(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+
+			back, err := FromText(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a, b := back.Error(), tt.in.Error(); a != b {
+				t.Errorf("Error()\nout:  %q\nwant: %q", a, b)
+			}
+			if a, b := fmt.Sprintf("%v", back), fmt.Sprintf("%v", tt.in); a != b {
+				t.Errorf("message\nout:  %q\nwant: %q", a, b)
+			}
+			if a, b := Codes(back), Codes(tt.in); !reflect.DeepEqual(a, b) {
+				t.Errorf("codes\nout:  %v\nwant: %v", a, b)
+			}
+		})
+	}
+}
+
+func TestFromTextError(t *testing.T) {
+	tests := []string{
+		`a:b`,
+		`a: b:`,
+		`a; b`,
+		`[a; b`,
+		`a]`,
+		`[a]: b`,
+		`a\`,
+		`a:: b`,
+		`E1:`,
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			_, err := FromText([]byte(tt))
+			if err == nil {
+				t.Errorf("no error for %q", tt)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package guru
+
+import (
+	"fmt"
+)
+
+// The error types forward Timeout() and Temporary() to the error they wrap, so
+// that checks such as:
+//
+//	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+//
+// keep working after adding a code.
+func (e *withCode) Timeout() bool        { return IsTimeout(e.error) }
+func (e *withCode) Temporary() bool      { return IsTemporary(e.error) }
+func (e *wrapped) Timeout() bool         { return IsTimeout(e.error) }
+func (e *wrapped) Temporary() bool       { return IsTemporary(e.error) }
+func (e *withCodeT[C]) Timeout() bool    { return IsTimeout(e.error) }
+func (e *withCodeT[C]) Temporary() bool  { return IsTemporary(e.error) }
+func (e *wrappedT[C]) Timeout() bool     { return IsTimeout(e.error) }
+func (e *wrappedT[C]) Temporary() bool   { return IsTemporary(e.error) }
+func (e *withFields) Timeout() bool      { return IsTimeout(e.error) }
+func (e *withFields) Temporary() bool    { return IsTemporary(e.error) }
+func (e *withStack) Timeout() bool       { return IsTimeout(e.error) }
+func (e *withStack) Temporary() bool     { return IsTemporary(e.error) }
+func (e *withRetry) Timeout() bool       { return IsTimeout(e.error) }
+func (e *withRetry) Temporary() bool     { return IsTemporary(e.error) }
+func (e *withPublic) Timeout() bool      { return IsTimeout(e.error) }
+func (e *withPublic) Temporary() bool    { return IsTemporary(e.error) }
+func (e *withSeverity) Timeout() bool    { return IsTimeout(e.error) }
+func (e *withSeverity) Temporary() bool  { return IsTemporary(e.error) }
+func (e *withRequestID) Timeout() bool   { return IsTimeout(e.error) }
+func (e *withRequestID) Temporary() bool { return IsTemporary(e.error) }
+func (e *withNote) Timeout() bool        { return IsTimeout(e.error) }
+func (e *withNote) Temporary() bool      { return IsTemporary(e.error) }
+func (e *withOp) Timeout() bool          { return IsTimeout(e.error) }
+func (e *withOp) Temporary() bool        { return IsTemporary(e.error) }
+func (e *withDetail) Timeout() bool      { return IsTimeout(e.error) }
+func (e *withDetail) Temporary() bool    { return IsTemporary(e.error) }
+func (e *withRelated) Timeout() bool     { return IsTimeout(e.error) }
+func (e *withRelated) Temporary() bool   { return IsTemporary(e.error) }
+func (e *stamped) Timeout() bool         { return IsTimeout(e.error) }
+func (e *stamped) Temporary() bool       { return IsTemporary(e.error) }
+
+type withTimeout struct{ error }
+
+func (e *withTimeout) Unwrap() error                { return e.error }
+func (e *withTimeout) Timeout() bool                { return true }
+func (e *withTimeout) Temporary() bool              { return IsTemporary(e.error) }
+func (e withTimeout) Format(s fmt.State, verb rune) { formatInner(s, verb, e.error) }
+
+// WithTimeout marks err as a timeout, so that IsTimeout and the Timeout()
+// method report true. It will return nil if err is nil.
+func WithTimeout(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withTimeout{error: err}
+}
+
+// IsTimeout reports if err is a timeout.
+//
+// This uses the Timeout() method of the first error in the chain that has
+// one, such as net.Error, os.PathError, or context.DeadlineExceeded.
+func IsTimeout(err error) bool {
+	is := false
+	walk(err, func(err error) bool {
+		t, ok := err.(interface{ Timeout() bool })
+		if ok {
+			is = t.Timeout()
+		}
+		return !ok
+	})
+	return is
+}
+
+// IsTemporary reports if err is temporary.
+//
+// This uses the Temporary() method of the first error in the chain that has
+// one, such as net.Error or syscall.Errno.
+func IsTemporary(err error) bool {
+	is := false
+	walk(err, func(err error) bool {
+		t, ok := err.(interface{ Temporary() bool })
+		if ok {
+			is = t.Temporary()
+		}
+		return !ok
+	})
+	return is
+}
@@ -0,0 +1,53 @@
+package guru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+var _ net.Error = &withCode{}
+var _ net.Error = &wrapped{}
+var _ net.Error = &withTimeout{}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		in            error
+		wantTimeout   bool
+		wantTemporary bool
+	}{
+		{nil, false, false},
+		{errors.New("x"), false, false},
+		{New(1, "x"), false, false},
+		{WithCode(1, context.DeadlineExceeded), true, true},
+		{Wrap(1, context.DeadlineExceeded, "x"), true, true},
+		{WithFields(WithCode(1, context.DeadlineExceeded), nil), true, true},
+		{WithStack(MarkRetryable(WithCodeT("x", syscall.EINTR))), false, true},
+		{WithCode(1, &os.PathError{Op: "read", Path: "/x", Err: os.ErrDeadlineExceeded}), true, true},
+		{fmt.Errorf("y: %w", WithCode(1, context.DeadlineExceeded)), true, true},
+		{WithTimeout(errors.New("x")), true, false},
+		{WithCode(1, WithTimeout(syscall.EINTR)), true, true},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			timeout, temp := IsTimeout(tt.in), IsTemporary(tt.in)
+			if timeout != tt.wantTimeout || temp != tt.wantTemporary {
+				t.Errorf("\nout:  %t %t\nwant: %t %t\n", timeout, temp, tt.wantTimeout, tt.wantTemporary)
+			}
+
+			var ne net.Error
+			if tt.in != nil && errors.As(tt.in, &ne) && ne.Timeout() != tt.wantTimeout {
+				t.Errorf("net.Error: %t", ne.Timeout())
+			}
+		})
+	}
+
+	if WithTimeout(nil) != nil {
+		t.Error("not nil")
+	}
+}
@@ -0,0 +1,93 @@
+package guru
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Tree renders err as an indented tree, with every error in the chain on its
+// own line and the errors wrapped by errors that wrap more than one error
+// (such as those created with errors.Join) as branches:
+//
+//	error 2: import
+//	├─ error 1: invalid row 3
+//	└─ parse row 5
+//	   └─ unexpected EOF
+//
+// Only codes and messages are included; use the %+v verb to also get fields,
+// stack traces, etc. It will return an empty string if err is nil.
+func Tree(err error) string {
+	var b strings.Builder
+	for _, e := range Flatten(err) {
+		if e.Depth > 0 || (!e.HasCode && e.Message == "") {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(entryLine(e))
+	}
+	if errs := multi(err); errs != nil {
+		writeBranches(&b, errs, b.Len() > 0, Tree)
+	}
+	return b.String()
+}
+
+func entryLine(e Entry) string {
+	if !e.HasCode {
+		return e.Message
+	}
+	c := "error " + fmtCode(e.Code)
+	if e.Subcode != 0 {
+		c += "." + fmtCode(e.Subcode)
+	}
+	if e.Message == "" {
+		return c
+	}
+	return c + ": " + e.Message
+}
+
+// multi gets the errors wrapped by the first error in the chain that wraps
+// more than one error, or nil if there is none.
+func multi(err error) []error {
+	for err != nil {
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			return u.Unwrap()
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// writeBranches writes every error in errs as a branch of a tree, with the
+// text from render. If newline is true a newline is written before the first
+// branch. nil errors are skipped.
+func writeBranches(w io.Writer, errs []error, newline bool, render func(error) string) {
+	nonNil := make([]error, 0, len(errs))
+	for _, e := range errs {
+		if e != nil {
+			nonNil = append(nonNil, e)
+		}
+	}
+	for i, e := range nonNil {
+		first, rest := "├─ ", "│  "
+		if i == len(nonNil)-1 {
+			first, rest = "└─ ", "   "
+		}
+		if newline || i > 0 {
+			io.WriteString(w, "\n")
+		}
+		lines := strings.Split(render(e), "\n")
+		io.WriteString(w, first+lines[0])
+		for _, l := range lines[1:] {
+			io.WriteString(w, "\n"+strings.TrimRight(rest+l, " "))
+		}
+	}
+}
+
+func plusV(err error) string { return fmt.Sprintf("%+v", err) }
@@ -0,0 +1,44 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	tests := []struct {
+		in       error
+		want     string
+		wantPlus string
+	}{
+		{nil, "", "<nil>"},
+		{errors.New("x"), "x", "x"},
+		{Wrap(2, New(1, "x"), "y"), "error 2: y\nerror 1: x", "error 2: y\nerror 1: x"},
+		{Wrap(2, errors.Join(NewSub(1, 3, "invalid row 3"), fmt.Errorf("parse row 5: %w", errors.New("unexpected EOF"))), "import"),
+			"error 2: import\n├─ error 1.3: invalid row 3\n└─ parse row 5\n   unexpected EOF",
+			"error 2: import\n├─ error 1.3: invalid row 3\n└─ parse row 5: unexpected EOF"},
+		{errors.Join(New(1, "a"), WithCode(2, errors.Join(New(3, "b"), New(4, "c")))),
+			"├─ error 1: a\n└─ error 2\n   ├─ error 3: b\n   └─ error 4: c",
+			"a\nb\nc"},
+		{Append(nil, New(1, "a"), WithFields(New(2, "b"), map[string]interface{}{"k": "v"})),
+			"├─ error 1: a\n└─ error 2: b",
+			"├─ error 1: a\n└─ error 2: b\n   fields: k=v"},
+		{WithCode(2, Append(nil, New(1, "a"), WithCode(3, errors.Join(New(4, "b"), nil)))),
+			"error 2\n├─ error 1: a\n└─ error 3\n   └─ error 4: b",
+			"error 2\n├─ error 1: a\n└─ error 3\n   └─ error 4: b"},
+		{New(0, "x"), "error 0: x", "error 0: x"},
+		{Wrap(1, WithCode(0, errors.New("x")), "y"), "error 1: y\nerror 0: x", "error 1: y\nerror 0: x"},
+		{errors.Join(New(0, ""), errors.New("b")), "├─ error 0\n└─ b", "\nb"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			if out := Tree(tt.in); out != tt.want {
+				t.Errorf("\nout:\n%s\nwant:\n%s", out, tt.want)
+			}
+			if out := fmt.Sprintf("%+v", tt.in); out != tt.wantPlus {
+				t.Errorf("%%+v\nout:\n%s\nwant:\n%s", out, tt.wantPlus)
+			}
+		})
+	}
+}
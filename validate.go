@@ -0,0 +1,67 @@
+package guru
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// Rule validates an error code; see Registry.Require.
+type Rule func(code int) error
+
+// Range is a Rule that requires codes to be in the range min to max
+// (inclusive).
+func Range(min, max int) Rule {
+	return func(code int) error {
+		if code < min || code > max {
+			return fmt.Errorf("code %d is outside the range %d to %d", code, min, max)
+		}
+		return nil
+	}
+}
+
+// hasRules is set if Require was called on any registry, to avoid the
+// overhead of looking up the registry for every error.
+var hasRules atomic.Bool
+
+// Require adds rules that all codes used with this registry must pass. New,
+// Errorf, Wrap, etc. (or Registry.New, Registry.Wrap, etc. for other
+// registries) panic if a rule returns an error:
+//
+//	guru.DefaultRegistry.Require(guru.Range(1000, 9999), func(code int) error {
+//		if code%1000 == 0 {
+//			return errors.New("use a code for a specific error")
+//		}
+//		return nil
+//	})
+//
+// The rules aren't checked when registering codes, or for Const.
+func (r *Registry) Require(rules ...Rule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, rules...)
+	hasRules.Store(true)
+}
+
+// checkRules panics if code doesn't pass the rules of the registry err was
+// created with. skip is the number of stack frames to skip for the location of
+// the caller, with 0 being the caller of checkRules.
+func checkRules(err error, code, skip int) {
+	if !hasRules.Load() {
+		return
+	}
+
+	reg := registryOf(err, false)
+	reg.mu.RLock()
+	rules := reg.rules
+	reg.mu.RUnlock()
+	for _, rule := range rules {
+		if rErr := rule(code); rErr != nil {
+			msg := "guru: " + rErr.Error()
+			if _, file, line, ok := runtime.Caller(skip + 2); ok {
+				msg += fmt.Sprintf(" (at %s:%d)", file, line)
+			}
+			panic(msg)
+		}
+	}
+}
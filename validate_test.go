@@ -0,0 +1,53 @@
+package guru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	resetRegistry(t)
+	t.Cleanup(func() { hasRules.Store(false) })
+	DefaultRegistry.Require(Range(1000, 9999), func(code int) error {
+		if code%1000 == 0 {
+			return errors.New("use a code for a specific error")
+		}
+		return nil
+	})
+	reg := &Registry{}
+	reg.Require(Range(1, 9))
+
+	tests := []struct {
+		fn   func()
+		want string
+	}{
+		{func() { New(4012, "x") }, ""},
+		{func() { Wrap(9999, io.EOF, "x") }, ""},
+		{func() { reg.New(5, "x") }, ""},
+		{func() { Const(1, "x") }, ""},
+		{func() { New(42, "x") }, "guru: code 42 is outside the range 1000 to 9999 (at "},
+		{func() { WithCode(10000, io.EOF) }, "guru: code 10000 is outside the range 1000 to 9999 (at "},
+		{func() { Errorf(4000, "x") }, "guru: use a code for a specific error (at "},
+		{func() { reg.New(4012, "x") }, "guru: code 4012 is outside the range 1 to 9 (at "},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if tt.want == "" {
+					if r != nil {
+						t.Fatalf("panic: %v", r)
+					}
+					return
+				}
+				if s, _ := r.(string); !strings.HasPrefix(s, tt.want) || !strings.Contains(s, "validate_test.go:") {
+					t.Errorf("\nout:  %#v\nwant: %#v\n", r, tt.want)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
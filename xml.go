@@ -0,0 +1,130 @@
+package guru
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+func (e constError) MarshalXML(x *xml.Encoder, _ xml.StartElement) error     { return encodeXML(x, e) }
+func (e *withCode) MarshalXML(x *xml.Encoder, _ xml.StartElement) error      { return encodeXML(x, e) }
+func (e *wrapped) MarshalXML(x *xml.Encoder, _ xml.StartElement) error       { return encodeXML(x, e) }
+func (e *withFields) MarshalXML(x *xml.Encoder, _ xml.StartElement) error    { return encodeXML(x, e) }
+func (e *withStack) MarshalXML(x *xml.Encoder, _ xml.StartElement) error     { return encodeXML(x, e) }
+func (e *withRetry) MarshalXML(x *xml.Encoder, _ xml.StartElement) error     { return encodeXML(x, e) }
+func (e *withTimeout) MarshalXML(x *xml.Encoder, _ xml.StartElement) error   { return encodeXML(x, e) }
+func (e *withPublic) MarshalXML(x *xml.Encoder, _ xml.StartElement) error    { return encodeXML(x, e) }
+func (e *withSeverity) MarshalXML(x *xml.Encoder, _ xml.StartElement) error  { return encodeXML(x, e) }
+func (e *withRequestID) MarshalXML(x *xml.Encoder, _ xml.StartElement) error { return encodeXML(x, e) }
+func (e *withNote) MarshalXML(x *xml.Encoder, _ xml.StartElement) error      { return encodeXML(x, e) }
+func (e *withOp) MarshalXML(x *xml.Encoder, _ xml.StartElement) error        { return encodeXML(x, e) }
+func (e *withDetail) MarshalXML(x *xml.Encoder, _ xml.StartElement) error    { return encodeXML(x, e) }
+func (e *withRelated) MarshalXML(x *xml.Encoder, _ xml.StartElement) error   { return encodeXML(x, e) }
+func (e *stamped) MarshalXML(x *xml.Encoder, _ xml.StartElement) error       { return encodeXML(x, e) }
+
+// XMLOptions sets the element and attribute names for MarshalXML.
+type XMLOptions struct {
+	Error   string // Element for every error; default "Error".
+	Code    string // Attribute for the code; default "code".
+	Subcode string // Attribute for the subcode; default "subcode".
+	Message string // Element for the message; default "Message".
+	Field   string // Element for every field; default "Field".
+}
+
+var xmlOpts atomic.Pointer[XMLOptions]
+
+// SetXMLOptions sets the element and attribute names for MarshalXML. Empty
+// names use the default. It's safe to call concurrently.
+func SetXMLOptions(opts XMLOptions) { xmlOpts.Store(&opts) }
+
+func xmlNames() XMLOptions {
+	var o XMLOptions
+	if p := xmlOpts.Load(); p != nil {
+		o = *p
+	}
+	for _, f := range []struct {
+		v   *string
+		def string
+	}{
+		{&o.Error, "Error"}, {&o.Code, "code"}, {&o.Subcode, "subcode"},
+		{&o.Message, "Message"}, {&o.Field, "Field"},
+	} {
+		if *f.v == "" {
+			*f.v = f.def
+		}
+	}
+	return o
+}
+
+// MarshalXML encodes err as XML, for example for SOAP faults:
+//
+//	<Error code="42"><Message>context</Message><Error><Message>oh noes</Message></Error></Error>
+//
+// Every error in the chain is an element, with the error it wraps (or errors,
+// for errors that wrap more than one error) as child elements. Fields are
+// added as <Field name="key">value</Field>. The names can be set with
+// SetXMLOptions.
+//
+// The errors from this package implement xml.Marshaler, so they can also be
+// used in structs. The element names are always those from SetXMLOptions.
+func MarshalXML(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+	return xml.Marshal(xmlError{err})
+}
+
+type xmlError struct{ err error }
+
+func (e xmlError) MarshalXML(enc *xml.Encoder, _ xml.StartElement) error {
+	return encodeXML(enc, e.err)
+}
+
+func encodeXML(enc *xml.Encoder, err error) error {
+	return writeXML(enc, toJSON(err), xmlNames())
+}
+
+func writeXML(enc *xml.Encoder, j *jsonError, o XMLOptions) error {
+	start := xml.StartElement{Name: xml.Name{Local: o.Error}}
+	if j.Code != nil {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: o.Code}, Value: fmtCode(int(*j.Code))})
+	}
+	if j.Subcode != 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: o.Subcode}, Value: fmtCode(j.Subcode)})
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if j.Message != "" {
+		if err := enc.EncodeElement(j.Message, xml.StartElement{Name: xml.Name{Local: o.Message}}); err != nil {
+			return err
+		}
+	}
+	keys := make([]string, 0, len(j.Fields))
+	for k := range j.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		err := enc.EncodeElement(fmt.Sprint(j.Fields[k]), xml.StartElement{
+			Name: xml.Name{Local: o.Field},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: k}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if j.Wrapped != nil {
+		if err := writeXML(enc, j.Wrapped, o); err != nil {
+			return err
+		}
+	}
+	for _, e := range j.Errors {
+		if err := writeXML(enc, e, o); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
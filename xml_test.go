@@ -0,0 +1,54 @@
+package guru
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMarshalXML(t *testing.T) {
+	tests := []struct {
+		in   error
+		want string
+	}{
+		{nil, ``},
+		{errors.New("x"), `<Error><Message>x</Message></Error>`},
+		{New(42, "a < b"), `<Error code="42"><Message>a &lt; b</Message></Error>`},
+		{NewSub(42, 3, "x"), `<Error code="42" subcode="3"><Message>x</Message></Error>`},
+		{Wrap(2, New(1, "x"), "y"),
+			`<Error code="2"><Message>y</Message><Error code="1"><Message>x</Message></Error></Error>`},
+		{WithFields(New(1, "x"), map[string]interface{}{"b": 2, "a": "v"}),
+			`<Error code="1"><Message>x</Message><Field name="a">v</Field><Field name="b">2</Field></Error>`},
+		{WithCode(3, errors.Join(New(1, "x"), errors.New("y"))),
+			`<Error code="3"><Error><Error code="1"><Message>x</Message></Error><Error><Message>y</Message></Error></Error></Error>`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out, err := MarshalXML(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("\nout:  %s\nwant: %s\n", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestXMLOptions(t *testing.T) {
+	SetXMLOptions(XMLOptions{Error: "Fault", Code: "faultcode", Message: "faultstring"})
+	t.Cleanup(func() { xmlOpts.Store(nil) })
+
+	out, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"Body"`
+		Err     error
+	}{Err: Wrap(2, New(1, "x"), "y")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<Body><Fault faultcode="2"><faultstring>y</faultstring><Fault faultcode="1"><faultstring>x</faultstring></Fault></Fault></Body>`
+	if string(out) != want {
+		t.Errorf("\nout:  %s\nwant: %s\n", out, want)
+	}
+}
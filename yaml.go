@@ -0,0 +1,326 @@
+package guru
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// toYAML writes reg as YAML.
+func toYAML(reg registryJSON) []byte {
+	var b bytes.Buffer
+	if len(reg.Categories) > 0 {
+		b.WriteString("categories:\n")
+		for _, c := range reg.Categories {
+			fmt.Fprintf(&b, "  - min: %d\n    max: %d\n    name: %s\n", c.Min, c.Max, yamlString(c.Name))
+		}
+	}
+	if len(reg.Codes) == 0 {
+		b.WriteString("codes: []\n")
+		return b.Bytes()
+	}
+	b.WriteString("codes:\n")
+	for _, c := range reg.Codes {
+		fmt.Fprintf(&b, "  - code: %d\n", c.Code)
+		if c.Name != "" {
+			fmt.Fprintf(&b, "    name: %s\n", yamlString(c.Name))
+		}
+		if c.Description != "" {
+			fmt.Fprintf(&b, "    description: %s\n", yamlString(c.Description))
+		}
+		if c.Message != "" {
+			fmt.Fprintf(&b, "    message: %s\n", yamlString(c.Message))
+		}
+		if c.HTTPStatus != 0 {
+			fmt.Fprintf(&b, "    http_status: %d\n", c.HTTPStatus)
+		}
+		if c.ExitCode != 0 {
+			fmt.Fprintf(&b, "    exit_code: %d\n", c.ExitCode)
+		}
+		if c.Retryable != nil {
+			fmt.Fprintf(&b, "    retryable: %t\n", *c.Retryable)
+		}
+		if c.Severity != 0 {
+			fmt.Fprintf(&b, "    severity: %s\n", c.Severity)
+		}
+		if c.HelpURL != "" {
+			fmt.Fprintf(&b, "    help_url: %s\n", yamlString(c.HelpURL))
+		}
+		if c.Deprecated {
+			b.WriteString("    deprecated: true\n")
+		}
+		if c.ReplacedBy != 0 {
+			fmt.Fprintf(&b, "    replaced_by: %d\n", c.ReplacedBy)
+		}
+		if c.DeprecationReason != "" {
+			fmt.Fprintf(&b, "    deprecation_reason: %s\n", yamlString(c.DeprecationReason))
+		}
+	}
+	return b.Bytes()
+}
+
+var rePlain = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_ .,'()/=?&%+:#-]*[A-Za-z0-9_.)/=]$|^[A-Za-z_]$`)
+
+// yamlString quotes s if it can't be written as a plain scalar.
+func yamlString(s string) string {
+	if !rePlain.MatchString(s) || strings.Contains(s, " #") || strings.Contains(s, ": ") {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "y", "n":
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// fromYAML reads the YAML written by toYAML.
+func fromYAML(data []byte) (registryJSON, error) {
+	var (
+		reg     registryJSON
+		lines   = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+		section string
+		item    interface{} // *categoryJSON or *codeJSON
+	)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		text := strings.TrimLeft(line, " ")
+		indent := len(line) - len(text)
+		if strings.HasPrefix(text, "\t") {
+			return reg, fmt.Errorf("line %d: tabs can't be used for indentation", i+1)
+		}
+		if t := strings.TrimSpace(text); t == "" || t[0] == '#' || (indent == 0 && t == "---") {
+			continue
+		}
+
+		isItem := text == "-" || strings.HasPrefix(text, "- ")
+		if indent == 0 && !isItem {
+			key, val, err := yamlKey(text)
+			if err != nil {
+				return reg, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if val != "" && val != "[]" {
+				return reg, fmt.Errorf("line %d: %q must be a list", i+1, key)
+			}
+			section, item = key, nil
+			continue
+		}
+
+		if isItem {
+			switch section {
+			case "categories":
+				reg.Categories = append(reg.Categories, categoryJSON{})
+				item = &reg.Categories[len(reg.Categories)-1]
+			case "codes":
+				reg.Codes = append(reg.Codes, codeJSON{})
+				item = &reg.Codes[len(reg.Codes)-1]
+			default:
+				item = nil // Unknown section; ignore.
+			}
+			text = strings.TrimLeft(text[1:], " ")
+			indent = len(line) - len(text)
+			if text == "" {
+				continue
+			}
+		}
+		if section != "categories" && section != "codes" {
+			continue
+		}
+		if item == nil {
+			return reg, fmt.Errorf("line %d: not in a list item", i+1)
+		}
+
+		key, val, err := yamlKey(text)
+		if err != nil {
+			return reg, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if strings.HasPrefix(val, "|") || strings.HasPrefix(val, ">") {
+			var n int
+			val, n = yamlBlock(val, lines[i+1:], indent)
+			i += n
+		} else {
+			val, err = yamlScalar(val)
+			if err != nil {
+				return reg, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+		if err := yamlSet(item, key, val); err != nil {
+			return reg, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return reg, nil
+}
+
+// yamlKey splits "key: value".
+func yamlKey(text string) (string, string, error) {
+	key, val, ok := strings.Cut(text, ":")
+	if !ok || (val != "" && val[0] != ' ') || strings.ContainsAny(key, " \"'") {
+		return "", "", fmt.Errorf("not a key: %q", text)
+	}
+	val = strings.TrimSpace(val)
+	if strings.HasPrefix(val, "#") {
+		val = ""
+	}
+	return key, val, nil
+}
+
+// yamlScalar parses a plain, single-quoted, or double-quoted scalar, which may
+// be followed by a comment.
+func yamlScalar(val string) (string, error) {
+	switch {
+	case strings.HasPrefix(val, `"`):
+		for i := 1; i < len(val); i++ {
+			switch val[i] {
+			case '\\':
+				i++
+			case '"':
+				if rest := strings.TrimSpace(val[i+1:]); rest != "" && rest[0] != '#' {
+					return "", fmt.Errorf("text after quoted string: %q", rest)
+				}
+				return strconv.Unquote(val[:i+1])
+			}
+		}
+		return "", fmt.Errorf("unterminated string: %s", val)
+	case strings.HasPrefix(val, "'"):
+		for i := 1; i < len(val); i++ {
+			if val[i] != '\'' {
+				continue
+			}
+			if i+1 < len(val) && val[i+1] == '\'' {
+				i++
+				continue
+			}
+			if rest := strings.TrimSpace(val[i+1:]); rest != "" && rest[0] != '#' {
+				return "", fmt.Errorf("text after quoted string: %q", rest)
+			}
+			return strings.ReplaceAll(val[1:i], "''", "'"), nil
+		}
+		return "", fmt.Errorf("unterminated string: %s", val)
+	}
+	if i := strings.Index(val, " #"); i > -1 {
+		val = val[:i]
+	}
+	return strings.TrimSpace(val), nil
+}
+
+// yamlBlock reads a literal ("|") or folded (">") block scalar from lines,
+// which must be indented more than indent. It returns the value and the number
+// of lines used.
+func yamlBlock(header string, lines []string, indent int) (string, int) {
+	var (
+		block []string
+		n     int
+		bi    = -1
+	)
+	for ; n < len(lines); n++ {
+		l := lines[n]
+		t := strings.TrimLeft(l, " ")
+		if t == "" {
+			block = append(block, "")
+			continue
+		}
+		li := len(l) - len(t)
+		if li <= indent {
+			break
+		}
+		if bi == -1 {
+			bi = li
+		}
+		if li < bi {
+			break
+		}
+		block = append(block, l[bi:])
+	}
+	// Trailing blank lines belong to whatever comes next.
+	for len(block) > 0 && block[len(block)-1] == "" {
+		block = block[:len(block)-1]
+		n--
+	}
+
+	var val string
+	if header[0] == '|' {
+		val = strings.Join(block, "\n")
+	} else {
+		var b strings.Builder
+		for i, l := range block {
+			switch {
+			case l == "":
+				b.WriteString("\n")
+			case i > 0 && block[i-1] != "":
+				b.WriteString(" ")
+			}
+			b.WriteString(l)
+		}
+		val = b.String()
+	}
+	if !strings.HasSuffix(header, "-") && val != "" {
+		val += "\n"
+	}
+	return val, n
+}
+
+func yamlSet(item interface{}, key, val string) error {
+	atoi := func(dst *int) error {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("%s: not a number: %q", key, val)
+		}
+		*dst = n
+		return nil
+	}
+	parseBool := func(dst *bool) error {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("%s: not a boolean: %q", key, val)
+		}
+		*dst = b
+		return nil
+	}
+
+	switch it := item.(type) {
+	case *categoryJSON:
+		switch key {
+		case "min":
+			return atoi(&it.Min)
+		case "max":
+			return atoi(&it.Max)
+		case "name":
+			it.Name = val
+		}
+	case *codeJSON:
+		switch key {
+		case "code":
+			return atoi(&it.Code)
+		case "name":
+			it.Name = val
+		case "description":
+			it.Description = val
+		case "message":
+			it.Message = val
+		case "http_status":
+			return atoi(&it.HTTPStatus)
+		case "exit_code":
+			return atoi(&it.ExitCode)
+		case "help_url":
+			it.HelpURL = val
+		case "retryable":
+			var b bool
+			if err := parseBool(&b); err != nil {
+				return err
+			}
+			it.Retryable = &b
+		case "severity":
+			if err := it.Severity.UnmarshalText([]byte(val)); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		case "deprecated":
+			return parseBool(&it.Deprecated)
+		case "replaced_by":
+			return atoi(&it.ReplacedBy)
+		case "deprecation_reason":
+			it.DeprecationReason = val
+		}
+	}
+	return nil
+}